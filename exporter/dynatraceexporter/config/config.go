@@ -17,10 +17,13 @@ package config // import "github.com/open-telemetry/opentelemetry-collector-cont
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/apiconstants"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
@@ -39,6 +42,13 @@ type Config struct {
 	// Dynatrace API token with metrics ingest permission
 	APIToken string `mapstructure:"api_token"`
 
+	// APITokenFile is the path to a file holding the Dynatrace API token, alternative to APIToken.
+	// The file is read again every TokenRefreshInterval, so that the token can be rotated without restart.
+	APITokenFile string `mapstructure:"api_token_file"`
+
+	// TokenRefreshInterval is the interval after which APITokenFile is read again
+	TokenRefreshInterval time.Duration `mapstructure:"token_refresh_interval"`
+
 	// DefaultDimensions will be added to all exported metrics
 	DefaultDimensions map[string]string `mapstructure:"default_dimensions"`
 
@@ -48,8 +58,82 @@ type Config struct {
 	// Tags will be added to all exported metrics
 	// Deprecated: Please use DefaultDimensions instead
 	Tags []string `mapstructure:"tags"`
+
+	// NonFiniteValuePolicy controls how gauge data points with a NaN or infinite value are handled.
+	// One of "drop" (default), "zero" or "error".
+	NonFiniteValuePolicy string `mapstructure:"non_finite_value_policy"`
+
+	// DetectCounterResets detects the resets of cumulative monotonic sums when converting them to the delta
+	// counters expected by Dynatrace. A point whose value is lower than the previous point of its series, e.g. after
+	// the restart of its source, is sent with its value as delta instead of a negative delta.
+	DetectCounterResets bool `mapstructure:"detect_counter_resets"`
+
+	// SendExemplarTraceIDs adds the trace ID of the first exemplar of a data point as dt.trace_id dimension,
+	// linking the metric to the trace in Dynatrace. Data points without exemplars are sent unchanged.
+	SendExemplarTraceIDs bool `mapstructure:"send_exemplar_trace_ids"`
+
+	// FlushInterval is the maximum time serialized lines are held back waiting for a full batch
+	// before they are sent to Dynatrace. 0 disables batching, each push is sent right away.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// AutoEntityMapping adds the dt.entity.* resource attributes as dimensions to all data points of the resource,
+	// allowing Dynatrace to map the metrics to the monitored entities.
+	AutoEntityMapping bool `mapstructure:"auto_entity_mapping"`
+
+	// MaxDimensionValueLength is the maximum length in bytes of dimension values created from data point attributes.
+	// Longer values are truncated before they are sent to Dynatrace.
+	MaxDimensionValueLength int `mapstructure:"max_dimension_value_length"`
+
+	// MaxDimensions is the maximum number of dimensions of a metric line. Dimensions created from data point
+	// attributes are dropped from lines with more dimensions, the default dimensions are kept.
+	MaxDimensions int `mapstructure:"max_dimensions"`
+
+	// MaxRequestBytes is the maximum size in bytes of the body of a metrics ingest request.
+	// Batches are split so that every request stays under both this size and the lines limit of the API.
+	MaxRequestBytes int `mapstructure:"max_request_bytes"`
+
+	// SendTracesAsEvents enables the traces pipeline, which exports spans as business events
+	// to the events ingest API at EventsEndpoint.
+	SendTracesAsEvents bool `mapstructure:"send_traces_as_events"`
+
+	// EventsEndpoint is the Dynatrace business events ingest endpoint spans are sent to
+	EventsEndpoint string `mapstructure:"events_endpoint"`
+
+	// Dynatrace API token with business events ingest permission
+	EventsAPIToken string `mapstructure:"events_api_token"`
 }
 
+// DefaultTokenRefreshInterval is the token refresh interval used when none is configured
+const DefaultTokenRefreshInterval = time.Minute
+
+// DimensionValueMaxLength is the maximum length of dimension values accepted by the Dynatrace API,
+// which is also the default MaxDimensionValueLength
+const DimensionValueMaxLength = 250
+
+// DimensionsMaxCount is the maximum number of dimensions of a metric line accepted by the Dynatrace API,
+// which is also the default MaxDimensions
+const DimensionsMaxCount = 50
+
+// RequestMaxBytes is the maximum size of the body of metrics ingest requests accepted by the Dynatrace API,
+// which is also the default MaxRequestBytes
+const RequestMaxBytes = 1 << 20
+
+const (
+	// CompressionNone sends the metrics ingest requests uncompressed
+	CompressionNone configcompression.CompressionType = "none"
+	// CompressionGzip compresses the metrics ingest requests with gzip
+	CompressionGzip configcompression.CompressionType = "gzip"
+)
+
+const (
+	// NonFiniteValuePolicyDrop drops data points with a non-finite value
+	NonFiniteValuePolicyDrop = "drop"
+	// NonFiniteValuePolicyZero sends data points with a non-finite value as zero
+	NonFiniteValuePolicyZero = "zero"
+	// NonFiniteValuePolicyError fails the whole batch if it contains a data point with a non-finite value
+	NonFiniteValuePolicyError = "error"
+)
+
 func (c *Config) Validate() error {
 	if err := c.QueueSettings.Validate(); err != nil {
 		return fmt.Errorf("queue settings has invalid configuration: %w", err)
@@ -60,20 +144,89 @@ func (c *Config) Validate() error {
 	}
 	c.APIToken = strings.TrimSpace(c.APIToken)
 
+	if c.APITokenFile != "" {
+		if c.APIToken != "" {
+			return errors.New("api_token and api_token_file cannot both be set")
+		}
+		if _, err := os.Stat(c.APITokenFile); err != nil {
+			return fmt.Errorf("api_token_file cannot be read: %w", err)
+		}
+		if c.TokenRefreshInterval == 0 {
+			c.TokenRefreshInterval = DefaultTokenRefreshInterval
+		}
+		if c.TokenRefreshInterval < 0 {
+			return errors.New("token_refresh_interval must be positive")
+		}
+	}
+
 	if c.Endpoint == "" {
 		c.Endpoint = apiconstants.GetDefaultOneAgentEndpoint()
 	} else {
-		if c.APIToken == "" {
+		if c.APIToken == "" && c.APITokenFile == "" {
 			return errors.New("api_token is required if Endpoint is provided")
 		}
 
-		c.HTTPClientSettings.Headers["Authorization"] = fmt.Sprintf("Api-Token %s", c.APIToken)
+		// the Authorization header of a token read from api_token_file is set on every request by the exporter
+		if c.APIToken != "" {
+			c.HTTPClientSettings.Headers["Authorization"] = fmt.Sprintf("Api-Token %s", c.APIToken)
+		}
 	}
 
 	if !(strings.HasPrefix(c.Endpoint, "http://") || strings.HasPrefix(c.Endpoint, "https://")) {
 		return errors.New("endpoint must start with https:// or http://")
 	}
 
+	switch c.Compression {
+	case "":
+		c.Compression = CompressionNone
+	case CompressionNone, CompressionGzip:
+	default:
+		return fmt.Errorf("compression must be one of %q or %q", CompressionNone, CompressionGzip)
+	}
+
+	switch c.NonFiniteValuePolicy {
+	case "":
+		c.NonFiniteValuePolicy = NonFiniteValuePolicyDrop
+	case NonFiniteValuePolicyDrop, NonFiniteValuePolicyZero, NonFiniteValuePolicyError:
+	default:
+		return fmt.Errorf("non_finite_value_policy must be one of %q, %q or %q", NonFiniteValuePolicyDrop, NonFiniteValuePolicyZero, NonFiniteValuePolicyError)
+	}
+
+	if c.FlushInterval < 0 {
+		return errors.New("flush_interval must not be negative")
+	}
+
+	if c.MaxDimensionValueLength == 0 {
+		c.MaxDimensionValueLength = DimensionValueMaxLength
+	}
+	if c.MaxDimensionValueLength < 0 || c.MaxDimensionValueLength > DimensionValueMaxLength {
+		return fmt.Errorf("max_dimension_value_length must be between 1 and %d", DimensionValueMaxLength)
+	}
+
+	if c.MaxDimensions == 0 {
+		c.MaxDimensions = DimensionsMaxCount
+	}
+	if c.MaxDimensions < 0 || c.MaxDimensions > DimensionsMaxCount {
+		return fmt.Errorf("max_dimensions must be between 1 and %d", DimensionsMaxCount)
+	}
+
+	if c.MaxRequestBytes == 0 {
+		c.MaxRequestBytes = RequestMaxBytes
+	}
+	if c.MaxRequestBytes < 0 || c.MaxRequestBytes > RequestMaxBytes {
+		return fmt.Errorf("max_request_bytes must be between 1 and %d", RequestMaxBytes)
+	}
+
+	c.EventsAPIToken = strings.TrimSpace(c.EventsAPIToken)
+	if c.SendTracesAsEvents {
+		if !(strings.HasPrefix(c.EventsEndpoint, "http://") || strings.HasPrefix(c.EventsEndpoint, "https://")) {
+			return errors.New("events_endpoint must start with https:// or http:// if send_traces_as_events is enabled")
+		}
+		if c.EventsAPIToken == "" {
+			return errors.New("events_api_token is required if send_traces_as_events is enabled")
+		}
+	}
+
 	c.HTTPClientSettings.Headers["Content-Type"] = "text/plain; charset=UTF-8"
 	c.HTTPClientSettings.Headers["User-Agent"] = "opentelemetry-collector"
 
@@ -15,10 +15,15 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/apiconstants"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -46,6 +51,172 @@ func TestConfig_Validate(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("Default NonFiniteValuePolicy", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, NonFiniteValuePolicyDrop, c.NonFiniteValuePolicy)
+	})
+
+	t.Run("Valid NonFiniteValuePolicy", func(t *testing.T) {
+		for _, policy := range []string{NonFiniteValuePolicyDrop, NonFiniteValuePolicyZero, NonFiniteValuePolicyError} {
+			c := &Config{NonFiniteValuePolicy: policy}
+			err := c.Validate()
+			assert.NoError(t, err)
+
+			assert.Equal(t, policy, c.NonFiniteValuePolicy)
+		}
+	})
+
+	t.Run("Invalid NonFiniteValuePolicy", func(t *testing.T) {
+		c := &Config{NonFiniteValuePolicy: "ignore"}
+		err := c.Validate()
+		assert.Error(t, err)
+	})
+
+	t.Run("Default Compression", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, CompressionNone, c.Compression)
+	})
+
+	t.Run("Valid Compression", func(t *testing.T) {
+		for _, compression := range []configcompression.CompressionType{CompressionNone, CompressionGzip} {
+			c := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Compression: compression}}
+			err := c.Validate()
+			assert.NoError(t, err)
+
+			assert.Equal(t, compression, c.Compression)
+		}
+	})
+
+	t.Run("Invalid Compression", func(t *testing.T) {
+		c := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Compression: configcompression.CompressionType("zstd")}}
+		err := c.Validate()
+		assert.Error(t, err)
+	})
+
+	t.Run("Default FlushInterval", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		// batching is disabled by default
+		assert.Equal(t, time.Duration(0), c.FlushInterval)
+	})
+
+	t.Run("Invalid FlushInterval", func(t *testing.T) {
+		c := &Config{FlushInterval: -time.Second}
+		err := c.Validate()
+		assert.Error(t, err)
+	})
+
+	t.Run("Default MaxDimensionValueLength", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, DimensionValueMaxLength, c.MaxDimensionValueLength)
+	})
+
+	t.Run("Invalid MaxDimensionValueLength", func(t *testing.T) {
+		for _, length := range []int{-1, DimensionValueMaxLength + 1} {
+			c := &Config{MaxDimensionValueLength: length}
+			err := c.Validate()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Default MaxDimensions", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, DimensionsMaxCount, c.MaxDimensions)
+	})
+
+	t.Run("Invalid MaxDimensions", func(t *testing.T) {
+		for _, count := range []int{-1, DimensionsMaxCount + 1} {
+			c := &Config{MaxDimensions: count}
+			err := c.Validate()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Default MaxRequestBytes", func(t *testing.T) {
+		c := &Config{}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, RequestMaxBytes, c.MaxRequestBytes)
+	})
+
+	t.Run("Invalid MaxRequestBytes", func(t *testing.T) {
+		for _, size := range []int{-1, RequestMaxBytes + 1} {
+			c := &Config{MaxRequestBytes: size}
+			err := c.Validate()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("Valid SendTracesAsEvents", func(t *testing.T) {
+		c := &Config{SendTracesAsEvents: true, EventsEndpoint: "https://example.com/api/v2/bizevents/ingest", EventsAPIToken: " token "}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, "token", c.EventsAPIToken)
+	})
+
+	t.Run("SendTracesAsEvents Invalid EventsEndpoint", func(t *testing.T) {
+		for _, endpoint := range []string{"", "example.com"} {
+			c := &Config{SendTracesAsEvents: true, EventsEndpoint: endpoint, EventsAPIToken: "token"}
+			err := c.Validate()
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("SendTracesAsEvents Missing EventsAPIToken", func(t *testing.T) {
+		c := &Config{SendTracesAsEvents: true, EventsEndpoint: "https://example.com/api/v2/bizevents/ingest"}
+		err := c.Validate()
+		assert.Error(t, err)
+	})
+
+	t.Run("Valid APITokenFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("token"), 0600))
+		c := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://example.com/"}, APITokenFile: path}
+		err := c.Validate()
+		assert.NoError(t, err)
+
+		assert.Equal(t, DefaultTokenRefreshInterval, c.TokenRefreshInterval)
+		assert.NotContains(t, c.Headers, "Authorization", "The token of the file should be set by the exporter")
+	})
+
+	t.Run("Missing APITokenFile", func(t *testing.T) {
+		c := &Config{APITokenFile: filepath.Join(t.TempDir(), "token")}
+		err := c.Validate()
+		assert.ErrorContains(t, err, "api_token_file cannot be read")
+	})
+
+	t.Run("APIToken and APITokenFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("token"), 0600))
+		c := &Config{APIToken: "token", APITokenFile: path}
+		err := c.Validate()
+		assert.EqualError(t, err, "api_token and api_token_file cannot both be set")
+	})
+
+	t.Run("Invalid TokenRefreshInterval", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(path, []byte("token"), 0600))
+		c := &Config{APITokenFile: path, TokenRefreshInterval: -time.Second}
+		err := c.Validate()
+		assert.Error(t, err)
+	})
+
 	t.Run("Invalid QueueSettings", func(t *testing.T) {
 		c := &Config{QueueSettings: exporterhelper.QueueSettings{QueueSize: -1, Enabled: true}}
 		err := c.Validate()
@@ -16,6 +16,7 @@ package dynatraceexporter // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -39,6 +40,7 @@ func NewFactory() component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		component.WithMetricsExporter(createMetricsExporter, stability),
+		component.WithTracesExporter(createTracesExporter, stability),
 	)
 }
 
@@ -53,10 +55,16 @@ func createDefaultConfig() config.Exporter {
 		},
 
 		APIToken:           "",
-		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ""},
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "", Compression: dtconfig.CompressionNone},
 
 		Tags:              []string{},
 		DefaultDimensions: make(map[string]string),
+
+		NonFiniteValuePolicy: dtconfig.NonFiniteValuePolicyDrop,
+
+		MaxDimensionValueLength: dtconfig.DimensionValueMaxLength,
+		MaxDimensions:           dtconfig.DimensionsMaxCount,
+		MaxRequestBytes:         dtconfig.RequestMaxBytes,
 	}
 }
 
@@ -69,19 +77,68 @@ func createMetricsExporter(
 
 	cfg := c.(*dtconfig.Config)
 
-	exp := newMetricsExporter(set, cfg)
+	exp, err := newMetricsExporter(set, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	exporter, err := exporterhelper.NewMetricsExporter(
 		ctx,
 		set,
 		cfg,
 		exp.PushMetricsData,
+		exporterhelper.WithTimeout(metricsTimeoutSettings(cfg)),
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithRetry(cfg.RetrySettings),
 		exporterhelper.WithStart(exp.start),
+		exporterhelper.WithShutdown(exp.shutdown),
 	)
 	if err != nil {
 		return nil, err
 	}
+	exporter = &queueDropMetricsExporter{MetricsExporter: exporter, metrics: exp.metrics}
 	return resourcetotelemetry.WrapMetricsExporter(cfg.ResourceToTelemetrySettings, exporter), nil
 }
+
+// metricsTimeoutSettings returns the timeout of a push of metrics. When batching is enabled, a push waits
+// up to the flush interval for its lines to be sent, on top of the default timeout of the send itself.
+func metricsTimeoutSettings(cfg *dtconfig.Config) exporterhelper.TimeoutSettings {
+	timeoutSettings := exporterhelper.NewDefaultTimeoutSettings()
+	if cfg.FlushInterval > 0 {
+		timeoutSettings.Timeout += cfg.FlushInterval
+	}
+	return timeoutSettings
+}
+
+// createTracesExporter creates a traces exporter sending spans as events, if enabled
+func createTracesExporter(
+	ctx context.Context,
+	set component.ExporterCreateSettings,
+	c config.Exporter,
+) (component.TracesExporter, error) {
+
+	cfg := c.(*dtconfig.Config)
+	if !cfg.SendTracesAsEvents {
+		return nil, errors.New("send_traces_as_events must be enabled to export traces")
+	}
+
+	exp := newTracesExporter(set, cfg)
+	metrics, err := newOpenCensusMetrics(cfg.ID().Name())
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := exporterhelper.NewTracesExporter(
+		ctx,
+		set,
+		cfg,
+		exp.pushTraces,
+		exporterhelper.WithQueue(cfg.QueueSettings),
+		exporterhelper.WithRetry(cfg.RetrySettings),
+		exporterhelper.WithStart(exp.start),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &queueDropTracesExporter{TracesExporter: exporter, metrics: metrics}, nil
+}
@@ -15,12 +15,15 @@
 package dynatraceexporter
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/apiconstants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtest"
@@ -44,13 +47,36 @@ func TestCreateDefaultConfig(t *testing.T) {
 			Enabled: false,
 		},
 
+		HTTPClientSettings: confighttp.HTTPClientSettings{Compression: dtconfig.CompressionNone},
+
 		Tags:              []string{},
 		DefaultDimensions: make(map[string]string),
+
+		NonFiniteValuePolicy: dtconfig.NonFiniteValuePolicyDrop,
+
+		MaxDimensionValueLength: dtconfig.DimensionValueMaxLength,
+		MaxDimensions:           dtconfig.DimensionsMaxCount,
+		MaxRequestBytes:         dtconfig.RequestMaxBytes,
 	}, cfg, "failed to create default config")
 
 	assert.NoError(t, configtest.CheckConfigStruct(cfg))
 }
 
+func TestCreateTracesExporter(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*dtconfig.Config)
+
+	_, err := factory.CreateTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	assert.EqualError(t, err, "send_traces_as_events must be enabled to export traces")
+
+	cfg.SendTracesAsEvents = true
+	cfg.EventsEndpoint = "https://example.com/api/v2/bizevents/ingest"
+	cfg.EventsAPIToken = "token"
+	exp, err := factory.CreateTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, exp)
+}
+
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
@@ -74,9 +100,16 @@ func TestLoadConfig(t *testing.T) {
 					Headers: map[string]string{
 						"Content-Type": "text/plain; charset=UTF-8",
 						"User-Agent":   "opentelemetry-collector"},
+					Compression: dtconfig.CompressionNone,
 				},
 				Tags:              []string{},
 				DefaultDimensions: make(map[string]string),
+
+				NonFiniteValuePolicy: dtconfig.NonFiniteValuePolicyDrop,
+
+				MaxDimensionValueLength: dtconfig.DimensionValueMaxLength,
+				MaxDimensions:           dtconfig.DimensionsMaxCount,
+				MaxRequestBytes:         dtconfig.RequestMaxBytes,
 			},
 		},
 		{
@@ -92,6 +125,7 @@ func TestLoadConfig(t *testing.T) {
 						"Authorization": "Api-Token token",
 						"Content-Type":  "text/plain; charset=UTF-8",
 						"User-Agent":    "opentelemetry-collector"},
+					Compression: dtconfig.CompressionGzip,
 				},
 				APIToken: "token",
 
@@ -101,6 +135,14 @@ func TestLoadConfig(t *testing.T) {
 				DefaultDimensions: map[string]string{
 					"dimension_example": "dimension_value",
 				},
+
+				NonFiniteValuePolicy: dtconfig.NonFiniteValuePolicyZero,
+				FlushInterval:        10 * time.Second,
+				AutoEntityMapping:    true,
+
+				MaxDimensionValueLength: 100,
+				MaxDimensions:           dtconfig.DimensionsMaxCount,
+				MaxRequestBytes:         dtconfig.RequestMaxBytes,
 			},
 		},
 		{
@@ -116,6 +158,7 @@ func TestLoadConfig(t *testing.T) {
 						"Authorization": "Api-Token token",
 						"Content-Type":  "text/plain; charset=UTF-8",
 						"User-Agent":    "opentelemetry-collector"},
+					Compression: dtconfig.CompressionNone,
 				},
 				APIToken: "token",
 
@@ -123,6 +166,12 @@ func TestLoadConfig(t *testing.T) {
 
 				Tags:              []string{"tag_example=tag_value"},
 				DefaultDimensions: make(map[string]string),
+
+				NonFiniteValuePolicy: dtconfig.NonFiniteValuePolicyDrop,
+
+				MaxDimensionValueLength: dtconfig.DimensionValueMaxLength,
+				MaxDimensions:           dtconfig.DimensionsMaxCount,
+				MaxRequestBytes:         dtconfig.RequestMaxBytes,
 			},
 		},
 		{
@@ -133,6 +182,26 @@ func TestLoadConfig(t *testing.T) {
 			id:           config.NewComponentIDWithName(typeStr, "missing_token"),
 			errorMessage: "api_token is required if Endpoint is provided",
 		},
+		{
+			id:           config.NewComponentIDWithName(typeStr, "bad_non_finite_value_policy"),
+			errorMessage: `non_finite_value_policy must be one of "drop", "zero" or "error"`,
+		},
+		{
+			id:           config.NewComponentIDWithName(typeStr, "bad_flush_interval"),
+			errorMessage: "flush_interval must not be negative",
+		},
+		{
+			id:           config.NewComponentIDWithName(typeStr, "bad_max_dimension_value_length"),
+			errorMessage: "max_dimension_value_length must be between 1 and 250",
+		},
+		{
+			id:           config.NewComponentIDWithName(typeStr, "bad_max_dimensions"),
+			errorMessage: "max_dimensions must be between 1 and 50",
+		},
+		{
+			id:           config.NewComponentIDWithName(typeStr, "bad_compression"),
+			errorMessage: "compression must be one of \"none\" or \"gzip\"",
+		},
 	}
 
 	for _, tt := range tests {
@@ -154,3 +223,12 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsTimeoutSettings(t *testing.T) {
+	cfg := createDefaultConfig().(*dtconfig.Config)
+	assert.Equal(t, exporterhelper.NewDefaultTimeoutSettings(), metricsTimeoutSettings(cfg))
+
+	// pushes wait up to the flush interval for their lines to be sent
+	cfg.FlushInterval = 10 * time.Second
+	assert.Equal(t, exporterhelper.NewDefaultTimeoutSettings().Timeout+10*time.Second, metricsTimeoutSettings(cfg).Timeout)
+}
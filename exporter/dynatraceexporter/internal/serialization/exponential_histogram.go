@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serialization // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/internal/serialization"
+
+import (
+	"math"
+
+	dtMetric "github.com/dynatrace-oss/dynatrace-metric-utils-go/metric"
+	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func serializeExponentialHistogramPoint(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.ExponentialHistogramDataPoint) (string, error) {
+	if dp.Count() == 0 {
+		return "", nil
+	}
+
+	min, max, sum := expHistDataPointToSummary(dp)
+
+	dm, err := dtMetric.NewMetric(
+		name,
+		dtMetric.WithPrefix(prefix),
+		dtMetric.WithDimensions(dims),
+		dtMetric.WithTimestamp(dp.Timestamp().AsTime()),
+		dtMetric.WithFloatSummaryValue(min, max, sum, int64(dp.Count())),
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	return dm.Serialize()
+}
+
+// serializeExponentialHistogram serializes the points of an exponential histogram as summaries. Next to the lines,
+// it returns the number of serialized points whose min, max or sum had to be estimated from the buckets.
+func serializeExponentialHistogram(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, truncator *DimensionValueTruncator, sendExemplarTraceIDs bool, metricLines []string) ([]string, int) {
+	hist := metric.ExponentialHistogram()
+	estimated := 0
+
+	if hist.AggregationTemporality() == pmetric.AggregationTemporalityCumulative {
+		logger.Warn(
+			"dropping cumulative exponential histogram",
+			zap.String("name", metric.Name()),
+		)
+		return metricLines, estimated
+	}
+
+	for i := 0; i < hist.DataPoints().Len(); i++ {
+		dp := hist.DataPoints().At(i)
+
+		line, err := serializeExponentialHistogramPoint(
+			metric.Name(),
+			prefix,
+			makeCombinedDimensions(defaultDimensions, dp.Attributes(), withExemplarTraceID(staticDimensions, dp.Exemplars(), sendExemplarTraceIDs), truncator),
+			dp,
+		)
+
+		if err != nil {
+			logger.Warn(
+				"Error serializing exponential histogram data point",
+				zap.String("name", metric.Name()),
+				zap.Error(err),
+			)
+		}
+
+		if line != "" {
+			metricLines = append(metricLines, line)
+			if !dp.HasMin() || !dp.HasMax() || !dp.HasSum() {
+				estimated++
+				logger.Debug(
+					"Estimated the summary of an exponential histogram data point from its buckets",
+					zap.String("name", metric.Name()),
+					zap.Bool("has-min", dp.HasMin()),
+					zap.Bool("has-max", dp.HasMax()),
+					zap.Bool("has-sum", dp.HasSum()),
+				)
+			}
+		}
+	}
+	return metricLines, estimated
+}
+
+// expHistDataPointToSummary returns the minimum, maximum and sum of the data point. Values that are not provided
+// are estimated from the boundaries of the lowest and highest non-empty buckets, and the bucket midpoints.
+// It MAY NOT be called with a data point with dp.Count() == 0.
+func expHistDataPointToSummary(dp pmetric.ExponentialHistogramDataPoint) (float64, float64, float64) {
+	// shortcut if min, max, and sum are provided
+	if dp.HasMin() && dp.HasMax() && dp.HasSum() {
+		return dp.Min(), dp.Max(), dp.Sum()
+	}
+
+	// the bucket of index i holds the values in (base^i, base^(i+1)], with base = 2^(2^-scale)
+	base := math.Pow(2, math.Pow(2, -float64(dp.Scale())))
+	lowerBound := func(index int) float64 {
+		return math.Pow(base, float64(index))
+	}
+
+	var min, max, sum float64
+	foundMin := false
+
+	// negative buckets hold the absolute values, the most negative values are in the highest buckets
+	negative := dp.Negative()
+	for i := negative.BucketCounts().Len() - 1; i >= 0; i-- {
+		count := negative.BucketCounts().At(i)
+		if count == 0 {
+			continue
+		}
+		index := int(negative.Offset()) + i
+		lower, upper := -lowerBound(index+1), -lowerBound(index)
+		if !foundMin {
+			foundMin = true
+			min = lower
+		}
+		max = upper
+		sum += float64(count) * (lower + upper) / 2
+	}
+
+	// the zero bucket only holds zeros, so it does not contribute to the sum
+	if dp.ZeroCount() > 0 {
+		if !foundMin {
+			foundMin = true
+			min = 0
+		}
+		max = 0
+	}
+
+	positive := dp.Positive()
+	for i := 0; i < positive.BucketCounts().Len(); i++ {
+		count := positive.BucketCounts().At(i)
+		if count == 0 {
+			continue
+		}
+		index := int(positive.Offset()) + i
+		lower, upper := lowerBound(index), lowerBound(index+1)
+		if !foundMin {
+			foundMin = true
+			min = lower
+		}
+		max = upper
+		sum += float64(count) * (lower + upper) / 2
+	}
+
+	// Override estimates with any values provided by the data point
+	if dp.HasMin() {
+		min = dp.Min()
+	}
+	if dp.HasMax() {
+		max = dp.Max()
+	}
+	if dp.HasSum() {
+		sum = dp.Sum()
+	}
+
+	// Keep the average between the estimated min and max, which may not hold when the sum is provided
+	avg := sum / float64(dp.Count())
+	if min > avg {
+		min = avg
+	}
+	if max < avg {
+		max = avg
+	}
+
+	return min, max, sum
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serialization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_serializeExponentialHistogramPoint(t *testing.T) {
+	timestamp := pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano())
+	dims := dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value"))
+
+	// newPoint returns a scale 0 point, with buckets of base 2: a value in [-2, -1), a zero,
+	// a value in (2, 4] and two values in (8, 16]
+	newPoint := func() pmetric.ExponentialHistogramDataPoint {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetTimestamp(timestamp)
+		dp.SetScale(0)
+		dp.SetCount(5)
+		dp.SetZeroCount(1)
+		dp.Negative().SetOffset(0)
+		dp.Negative().BucketCounts().FromRaw([]uint64{1})
+		dp.Positive().SetOffset(1)
+		dp.Positive().BucketCounts().FromRaw([]uint64{1, 0, 2})
+		return dp
+	}
+
+	t.Run("min, max and sum provided", func(t *testing.T) {
+		dp := newPoint()
+		dp.SetMin(-1.5)
+		dp.SetMax(12)
+		dp.SetSum(24.5)
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, dp)
+		assert.NoError(t, err)
+		assert.Equal(t, "prefix.exp_hist,key=value gauge,min=-1.5,max=12,sum=24.5,count=5 1626438600000", got)
+	})
+
+	t.Run("estimated from buckets", func(t *testing.T) {
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, newPoint())
+		assert.NoError(t, err)
+		// sum estimated from the midpoints: -1.5 + 0 + 3 + 2 * 12
+		assert.Equal(t, "prefix.exp_hist,key=value gauge,min=-2,max=16,sum=25.5,count=5 1626438600000", got)
+	})
+
+	t.Run("sum provided", func(t *testing.T) {
+		dp := newPoint()
+		dp.SetSum(20)
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, dp)
+		assert.NoError(t, err)
+		assert.Equal(t, "prefix.exp_hist,key=value gauge,min=-2,max=16,sum=20,count=5 1626438600000", got)
+	})
+
+	t.Run("negative scale", func(t *testing.T) {
+		// buckets of base 4, the bucket of index 1 holds the values in (4, 16]
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetTimestamp(timestamp)
+		dp.SetScale(-1)
+		dp.SetCount(2)
+		dp.Positive().SetOffset(1)
+		dp.Positive().BucketCounts().FromRaw([]uint64{2})
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, dp)
+		assert.NoError(t, err)
+		assert.Equal(t, "prefix.exp_hist,key=value gauge,min=4,max=16,sum=20,count=2 1626438600000", got)
+	})
+
+	t.Run("zero bucket only", func(t *testing.T) {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetTimestamp(timestamp)
+		dp.SetCount(3)
+		dp.SetZeroCount(3)
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, dp)
+		assert.NoError(t, err)
+		assert.Equal(t, "prefix.exp_hist,key=value gauge,min=0,max=0,sum=0,count=3 1626438600000", got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetTimestamp(timestamp)
+		got, err := serializeExponentialHistogramPoint("exp_hist", "prefix", dims, dp)
+		assert.NoError(t, err)
+		assert.Equal(t, "", got)
+	})
+}
+
+func Test_serializeExponentialHistogram(t *testing.T) {
+	emptyDims := dimensions.NewNormalizedDimensionList()
+
+	t.Run("wrong aggregation temporality", func(t *testing.T) {
+		metric := pmetric.NewMetric()
+		metric.SetName("metric_name")
+		hist := metric.SetEmptyExponentialHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		hist.DataPoints().AppendEmpty().SetCount(1)
+
+		zapCore, observedLogs := observer.New(zap.WarnLevel)
+		lines, estimated := serializeExponentialHistogram(zap.New(zapCore), "", metric, emptyDims, emptyDims, nil, false, []string{})
+		assert.Empty(t, lines)
+		assert.Equal(t, 0, estimated)
+		assert.ElementsMatch(t, []simplifiedLogRecord{
+			{
+				message: "dropping cumulative exponential histogram",
+				attributes: map[string]string{
+					"name": "metric_name",
+				},
+			},
+		}, makeSimplifiedLogRecordsFromObservedLogs(observedLogs))
+	})
+
+	t.Run("counts estimated points", func(t *testing.T) {
+		metric := pmetric.NewMetric()
+		metric.SetName("exp_hist")
+		hist := metric.SetEmptyExponentialHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+		provided := hist.DataPoints().AppendEmpty()
+		provided.SetCount(1)
+		provided.SetMin(3)
+		provided.SetMax(3)
+		provided.SetSum(3)
+		provided.Positive().SetOffset(1)
+		provided.Positive().BucketCounts().FromRaw([]uint64{1})
+
+		estimated := hist.DataPoints().AppendEmpty()
+		estimated.SetCount(1)
+		estimated.Positive().SetOffset(1)
+		estimated.Positive().BucketCounts().FromRaw([]uint64{1})
+
+		// empty points are not serialized, nor counted
+		hist.DataPoints().AppendEmpty()
+
+		zapCore, observedLogs := observer.New(zap.DebugLevel)
+		lines, estimatedCount := serializeExponentialHistogram(zap.New(zapCore), "", metric, emptyDims, emptyDims, nil, false, []string{})
+		assert.Equal(t, []string{
+			"exp_hist gauge,min=3,max=3,sum=3,count=1",
+			"exp_hist gauge,min=2,max=4,sum=3,count=1",
+		}, lines)
+		assert.Equal(t, 1, estimatedCount)
+		assert.Equal(t, 1, observedLogs.FilterMessage("Estimated the summary of an exponential histogram data point from its buckets").Len())
+	})
+}
@@ -16,14 +16,17 @@ package serialization // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"fmt"
+	"math"
 
 	dtMetric "github.com/dynatrace-oss/dynatrace-metric-utils-go/metric"
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
 )
 
-func serializeGaugePoint(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint) (string, error) {
+func serializeGaugePoint(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint, nonFiniteValuePolicy string) (string, error) {
 	var metricOption dtMetric.MetricOption
 
 	switch dp.ValueType() {
@@ -32,7 +35,18 @@ func serializeGaugePoint(name, prefix string, dims dimensions.NormalizedDimensio
 	case pmetric.NumberDataPointValueTypeInt:
 		metricOption = dtMetric.WithIntGaugeValue(dp.IntValue())
 	case pmetric.NumberDataPointValueTypeDouble:
-		metricOption = dtMetric.WithFloatGaugeValue(dp.DoubleValue())
+		value := dp.DoubleValue()
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			switch nonFiniteValuePolicy {
+			case config.NonFiniteValuePolicyZero:
+				value = 0
+			case config.NonFiniteValuePolicyError:
+				return "", fmt.Errorf("%w: %v", ErrNonFiniteValue, value)
+			default:
+				return "", errNonFiniteValueDropped
+			}
+		}
+		metricOption = dtMetric.WithFloatGaugeValue(value)
 	default:
 		return "", fmt.Errorf("unknown data type")
 	}
@@ -52,8 +66,9 @@ func serializeGaugePoint(name, prefix string, dims dimensions.NormalizedDimensio
 	return dm.Serialize()
 }
 
-func serializeGauge(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, metricLines []string) []string {
+func serializeGauge(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, nonFiniteValuePolicy string, truncator *DimensionValueTruncator, sendExemplarTraceIDs bool, metricLines []string) ([]string, int, error) {
 	points := metric.Gauge().DataPoints()
+	dropped := 0
 
 	for i := 0; i < points.Len(); i++ {
 		dp := points.At(i)
@@ -61,11 +76,19 @@ func serializeGauge(logger *zap.Logger, prefix string, metric pmetric.Metric, de
 		line, err := serializeGaugePoint(
 			metric.Name(),
 			prefix,
-			makeCombinedDimensions(defaultDimensions, dp.Attributes(), staticDimensions),
+			makeCombinedDimensions(defaultDimensions, dp.Attributes(), withExemplarTraceID(staticDimensions, dp.Exemplars(), sendExemplarTraceIDs), truncator),
 			dp,
+			nonFiniteValuePolicy,
 		)
 
 		if err != nil {
+			if ok, err := handleNonFiniteValueError(logger, metric.Name(), err); ok {
+				if err != nil {
+					return nil, dropped, err
+				}
+				dropped++
+				continue
+			}
 			logger.Warn(
 				"Error serializing gauge data point",
 				zap.String("name", metric.Name()),
@@ -78,5 +101,5 @@ func serializeGauge(logger *zap.Logger, prefix string, metric pmetric.Metric, de
 			metricLines = append(metricLines, line)
 		}
 	}
-	return metricLines
+	return metricLines, dropped, nil
 }
@@ -25,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
 )
 
 func Test_serializeGaugePoint(t *testing.T) {
@@ -33,7 +35,7 @@ func Test_serializeGaugePoint(t *testing.T) {
 		dp.SetDoubleValue(5.5)
 		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano()))
 
-		got, err := serializeGaugePoint("dbl_gauge", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), dp)
+		got, err := serializeGaugePoint("dbl_gauge", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), dp, config.NonFiniteValuePolicyDrop)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.dbl_gauge,key=value gauge,5.5 1626438600000", got)
 	})
@@ -43,7 +45,7 @@ func Test_serializeGaugePoint(t *testing.T) {
 		dp.SetIntValue(5)
 		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano()))
 
-		got, err := serializeGaugePoint("int_gauge", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), dp)
+		got, err := serializeGaugePoint("int_gauge", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), dp, config.NonFiniteValuePolicyDrop)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.int_gauge,key=value gauge,5 1626438600000", got)
 	})
@@ -52,27 +54,63 @@ func Test_serializeGaugePoint(t *testing.T) {
 		dp := pmetric.NewNumberDataPoint()
 		dp.SetIntValue(5)
 
-		got, err := serializeGaugePoint("int_gauge", "prefix", dimensions.NewNormalizedDimensionList(), dp)
+		got, err := serializeGaugePoint("int_gauge", "prefix", dimensions.NewNormalizedDimensionList(), dp, config.NonFiniteValuePolicyDrop)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.int_gauge gauge,5", got)
 	})
+
+	t.Run("non-finite values with drop policy", func(t *testing.T) {
+		for _, value := range []float64{math.NaN(), math.Inf(1)} {
+			dp := pmetric.NewNumberDataPoint()
+			dp.SetDoubleValue(value)
+
+			got, err := serializeGaugePoint("dbl_gauge", "prefix", dimensions.NewNormalizedDimensionList(), dp, config.NonFiniteValuePolicyDrop)
+			assert.ErrorIs(t, err, errNonFiniteValueDropped)
+			assert.Empty(t, got)
+		}
+	})
+
+	t.Run("non-finite values with zero policy", func(t *testing.T) {
+		for _, value := range []float64{math.NaN(), math.Inf(1)} {
+			dp := pmetric.NewNumberDataPoint()
+			dp.SetDoubleValue(value)
+
+			got, err := serializeGaugePoint("dbl_gauge", "prefix", dimensions.NewNormalizedDimensionList(), dp, config.NonFiniteValuePolicyZero)
+			assert.NoError(t, err)
+			assert.Equal(t, "prefix.dbl_gauge gauge,0", got)
+		}
+	})
+
+	t.Run("non-finite values with error policy", func(t *testing.T) {
+		for _, value := range []float64{math.NaN(), math.Inf(1)} {
+			dp := pmetric.NewNumberDataPoint()
+			dp.SetDoubleValue(value)
+
+			got, err := serializeGaugePoint("dbl_gauge", "prefix", dimensions.NewNormalizedDimensionList(), dp, config.NonFiniteValuePolicyError)
+			assert.ErrorIs(t, err, ErrNonFiniteValue)
+			assert.Empty(t, got)
+		}
+	})
 }
 
 func Test_serializeGauge(t *testing.T) {
 	type args struct {
-		prefix            string
-		metricName        string
-		intValues         []int64
-		floatValues       []float64
-		defaultDimensions dimensions.NormalizedDimensionList
-		staticDimensions  dimensions.NormalizedDimensionList
+		prefix               string
+		metricName           string
+		intValues            []int64
+		floatValues          []float64
+		defaultDimensions    dimensions.NormalizedDimensionList
+		staticDimensions     dimensions.NormalizedDimensionList
+		nonFiniteValuePolicy string
 	}
 
 	tests := []struct {
-		name     string
-		args     args
-		want     []string
-		wantLogs []simplifiedLogRecord
+		name        string
+		args        args
+		want        []string
+		wantDropped int
+		wantErr     bool
+		wantLogs    []simplifiedLogRecord
 	}{
 		{
 			name: "no data points",
@@ -116,42 +154,67 @@ func Test_serializeGauge(t *testing.T) {
 			},
 		},
 		{
-			name: "invalid double values",
+			name: "non-finite double values are dropped",
 			args: args{
 				metricName: "metric_name",
 				floatValues: []float64{
 					math.Inf(-1),
 					math.Inf(1),
 					math.NaN(),
+					1.5,
 				},
+				nonFiniteValuePolicy: config.NonFiniteValuePolicyDrop,
 			},
-			want: []string{},
-			wantLogs: []simplifiedLogRecord{
-				{
-					message: "Error serializing gauge data point",
-					attributes: map[string]string{
-						"name":       "metric_name",
-						"value-type": "Double",
-						"error":      "value is infinite",
-					},
+			want: []string{
+				"metric_name gauge,1.5",
+			},
+			wantDropped: 3,
+			wantLogs:    []simplifiedLogRecord{},
+		},
+		{
+			name: "non-finite double values are sent as zero",
+			args: args{
+				metricName: "metric_name",
+				floatValues: []float64{
+					math.Inf(-1),
+					math.Inf(1),
+					math.NaN(),
+					1.5,
 				},
-				{
-					message: "Error serializing gauge data point",
-					attributes: map[string]string{
-						"name":       "metric_name",
-						"value-type": "Double",
-						"error":      "value is infinite",
-					},
+				nonFiniteValuePolicy: config.NonFiniteValuePolicyZero,
+			},
+			want: []string{
+				"metric_name gauge,0",
+				"metric_name gauge,0",
+				"metric_name gauge,0",
+				"metric_name gauge,1.5",
+			},
+			wantLogs: []simplifiedLogRecord{},
+		},
+		{
+			name: "non-finite double values fail with error policy",
+			args: args{
+				metricName: "metric_name",
+				floatValues: []float64{
+					1.5,
+					math.NaN(),
 				},
-				{
-					message: "Error serializing gauge data point",
-					attributes: map[string]string{
-						"name":       "metric_name",
-						"value-type": "Double",
-						"error":      "value is NaN.",
-					},
+				nonFiniteValuePolicy: config.NonFiniteValuePolicyError,
+			},
+			wantErr:  true,
+			wantLogs: []simplifiedLogRecord{},
+		},
+		{
+			name: "infinite double values fail with error policy",
+			args: args{
+				metricName: "metric_name",
+				floatValues: []float64{
+					math.Inf(1),
 				},
+				nonFiniteValuePolicy: config.NonFiniteValuePolicyError,
 			},
+			wantErr:  true,
+			wantLogs: []simplifiedLogRecord{},
 		},
 	}
 	for _, tt := range tests {
@@ -177,9 +240,16 @@ func Test_serializeGauge(t *testing.T) {
 				}
 			}
 
-			actual := serializeGauge(logger, tt.args.prefix, metric, tt.args.defaultDimensions, tt.args.staticDimensions, []string{})
+			actual, dropped, err := serializeGauge(logger, tt.args.prefix, metric, tt.args.defaultDimensions, tt.args.staticDimensions, tt.args.nonFiniteValuePolicy, nil, false, []string{})
 
-			assert.ElementsMatch(t, actual, tt.want)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNonFiniteValue)
+				assert.Empty(t, actual)
+			} else {
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, actual, tt.want)
+			}
+			assert.Equal(t, tt.wantDropped, dropped)
 
 			// check that logs contain the expected messages.
 			if tt.wantLogs != nil {
@@ -43,7 +43,7 @@ func serializeHistogramPoint(name, prefix string, dims dimensions.NormalizedDime
 	return dm.Serialize()
 }
 
-func serializeHistogram(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, metricLines []string) []string {
+func serializeHistogram(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, truncator *DimensionValueTruncator, sendExemplarTraceIDs bool, metricLines []string) []string {
 	hist := metric.Histogram()
 
 	if hist.AggregationTemporality() == pmetric.AggregationTemporalityCumulative {
@@ -60,7 +60,7 @@ func serializeHistogram(logger *zap.Logger, prefix string, metric pmetric.Metric
 		line, err := serializeHistogramPoint(
 			metric.Name(),
 			prefix,
-			makeCombinedDimensions(defaultDimensions, dp.Attributes(), staticDimensions),
+			makeCombinedDimensions(defaultDimensions, dp.Attributes(), withExemplarTraceID(staticDimensions, dp.Exemplars(), sendExemplarTraceIDs), truncator),
 			dp,
 		)
 
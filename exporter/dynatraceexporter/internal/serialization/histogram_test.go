@@ -333,7 +333,7 @@ func Test_serializeHistogram(t *testing.T) {
 		zapCore, observedLogs := observer.New(zap.WarnLevel)
 		logger := zap.New(zapCore)
 
-		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, []string{})
+		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, nil, false, []string{})
 		assert.Empty(t, lines)
 
 		actualLogRecords := makeSimplifiedLogRecordsFromObservedLogs(observedLogs)
@@ -366,7 +366,7 @@ func Test_serializeHistogram(t *testing.T) {
 		zapCore, observedLogs := observer.New(zap.WarnLevel)
 		logger := zap.New(zapCore)
 
-		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, []string{})
+		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, nil, false, []string{})
 		assert.Empty(t, lines)
 
 		expectedLogRecords := []simplifiedLogRecord{
@@ -396,7 +396,7 @@ func Test_serializeHistogram(t *testing.T) {
 		zapCore, observedLogs := observer.New(zap.WarnLevel)
 		logger := zap.New(zapCore)
 
-		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, []string{})
+		lines := serializeHistogram(logger, "", metric, emptyDims, emptyDims, nil, false, []string{})
 
 		expectedLines := []string{
 			"metric_name gauge,min=1,max=5,sum=8,count=3",
@@ -15,9 +15,12 @@
 package serialization // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/internal/serialization"
 
 import (
+	"errors"
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
+	"github.com/dynatrace-oss/dynatrace-metric-utils-go/normalize"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
@@ -25,37 +28,171 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/ttlmap"
 )
 
-func SerializeMetric(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions, staticDimensions dimensions.NormalizedDimensionList, prev *ttlmap.TTLMap) ([]string, error) {
+var (
+	// ErrNonFiniteValue is returned if a data point holds a NaN or infinite value
+	// and the non-finite value policy is set to "error".
+	ErrNonFiniteValue = errors.New("non-finite value")
+
+	errNonFiniteValueDropped = errors.New("dropped data point with non-finite value")
+)
+
+// SerializeMetric serializes metric to Dynatrace metric lines. Next to the lines, it returns
+// the number of data points that were dropped because they held a non-finite value, and the number of
+// exponential histogram data points whose summary was estimated from their buckets.
+// Data point attribute values longer than the maximum length of truncator are truncated.
+// Cumulative monotonic sums are converted to delta counters, and if detectResets is set, counters decreasing
+// from one point to the next are considered reset and the value of the point is sent as delta.
+// If sendExemplarTraceIDs is set, the trace ID of the first exemplar of a data point is added as dt.trace_id dimension.
+func SerializeMetric(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions, staticDimensions dimensions.NormalizedDimensionList, prev *ttlmap.TTLMap, nonFiniteValuePolicy string, truncator *DimensionValueTruncator, detectResets bool, sendExemplarTraceIDs bool) ([]string, int, int, error) {
 	var metricLines []string
+	var dropped, estimated int
+	var err error
 
 	ce := logger.Check(zap.DebugLevel, "SerializeMetric")
 	var points int
 
 	switch metric.Type() {
 	case pmetric.MetricTypeGauge:
-		metricLines = serializeGauge(logger, prefix, metric, defaultDimensions, staticDimensions, metricLines)
+		metricLines, dropped, err = serializeGauge(logger, prefix, metric, defaultDimensions, staticDimensions, nonFiniteValuePolicy, truncator, sendExemplarTraceIDs, metricLines)
 	case pmetric.MetricTypeSum:
-		metricLines = serializeSum(logger, prefix, metric, defaultDimensions, staticDimensions, prev, metricLines)
+		metricLines, dropped, err = serializeSum(logger, prefix, metric, defaultDimensions, staticDimensions, prev, nonFiniteValuePolicy, truncator, detectResets, sendExemplarTraceIDs, metricLines)
 	case pmetric.MetricTypeHistogram:
-		metricLines = serializeHistogram(logger, prefix, metric, defaultDimensions, staticDimensions, metricLines)
+		metricLines = serializeHistogram(logger, prefix, metric, defaultDimensions, staticDimensions, truncator, sendExemplarTraceIDs, metricLines)
+	case pmetric.MetricTypeExponentialHistogram:
+		metricLines, estimated = serializeExponentialHistogram(logger, prefix, metric, defaultDimensions, staticDimensions, truncator, sendExemplarTraceIDs, metricLines)
 	default:
-		return nil, fmt.Errorf("metric type %s unsupported", metric.Type().String())
+		return nil, 0, 0, fmt.Errorf("metric type %s unsupported", metric.Type().String())
+	}
+
+	if err != nil {
+		return nil, dropped, estimated, err
 	}
 
 	if ce != nil {
 		ce.Write(zap.String("DataType", metric.Type().String()), zap.Int("points", points))
 	}
 
-	return metricLines, nil
+	return metricLines, dropped, estimated, nil
+}
+
+// handleNonFiniteValueError reports whether err was caused by a data point holding a non-finite value.
+// If so, the returned error is non-nil when the data point has to fail the batch, otherwise it was dropped.
+func handleNonFiniteValueError(logger *zap.Logger, name string, err error) (bool, error) {
+	switch {
+	case errors.Is(err, errNonFiniteValueDropped):
+		logger.Debug("dropping data point with non-finite value", zap.String("name", name))
+		return true, nil
+	case errors.Is(err, ErrNonFiniteValue):
+		return true, fmt.Errorf("%s: %w", name, err)
+	}
+	return false, nil
+}
+
+// DimensionValueTruncator truncates dimension values longer than MaxLength bytes
+// and counts the number of truncated values. A nil truncator or a MaxLength of 0 leaves values untouched.
+// It also caps the number of dimensions of a line at MaxDimensions, dropping the dimensions created from
+// data point attributes first, and counts the number of lines whose dimensions were capped in Capped.
+// A MaxDimensions of 0 leaves the dimensions untouched.
+type DimensionValueTruncator struct {
+	MaxLength int
+	Truncated int
+
+	MaxDimensions int
+	Capped        int
+}
+
+func (t *DimensionValueTruncator) truncate(value string) string {
+	if t == nil || t.MaxLength <= 0 || len(value) <= t.MaxLength {
+		return value
+	}
+	t.Truncated++
+	end := t.MaxLength
+	// do not cut a multi-byte character in half
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
 }
 
-func makeCombinedDimensions(defaultDimensions dimensions.NormalizedDimensionList, dataPointAttributes pcommon.Map, staticDimensions dimensions.NormalizedDimensionList) dimensions.NormalizedDimensionList {
+// capDimensions drops the attribute dimensions that would raise the number of distinct dimension keys of a line
+// above MaxDimensions. The default and static dimensions are always kept, so attribute dimensions overriding one
+// of them do not count towards the limit.
+func (t *DimensionValueTruncator) capDimensions(dimsFromAttributes []dimensions.Dimension, defaultDimensions, staticDimensions dimensions.NormalizedDimensionList) []dimensions.Dimension {
+	if t == nil || t.MaxDimensions <= 0 {
+		return dimsFromAttributes
+	}
+
+	keys := make(map[string]struct{})
+	collectKeys := func(dims []dimensions.Dimension) string {
+		for _, dim := range dims {
+			keys[dim.Key] = struct{}{}
+		}
+		return ""
+	}
+	defaultDimensions.Format(collectKeys)
+	staticDimensions.Format(collectKeys)
+	if len(keys)+len(dimsFromAttributes) <= t.MaxDimensions {
+		return dimsFromAttributes
+	}
+
+	kept := dimsFromAttributes[:0]
+	for _, dim := range dimsFromAttributes {
+		key, err := normalize.DimensionKey(dim.Key)
+		if err != nil {
+			// invalid keys are dropped when the dimensions are normalized
+			kept = append(kept, dim)
+			continue
+		}
+		if _, ok := keys[key]; !ok {
+			if len(keys) >= t.MaxDimensions {
+				continue
+			}
+			keys[key] = struct{}{}
+		}
+		kept = append(kept, dim)
+	}
+	if len(kept) < len(dimsFromAttributes) {
+		t.Capped++
+	}
+	return kept
+}
+
+// EntityDimensionPrefix is the prefix of the dimensions mapping a metric line to a Dynatrace entity
+const EntityDimensionPrefix = "dt.entity."
+
+// exemplarTraceIDKey is the dimension linking a metric line to the trace of an exemplar of its data point
+const exemplarTraceIDKey = "dt.trace_id"
+
+// withExemplarTraceID adds the trace ID of the first exemplar that has one to the static dimensions, so that it
+// overrides data point attributes and is never dropped when capping the number of dimensions.
+func withExemplarTraceID(staticDimensions dimensions.NormalizedDimensionList, exemplars pmetric.ExemplarSlice, enabled bool) dimensions.NormalizedDimensionList {
+	if !enabled {
+		return staticDimensions
+	}
+	for i := 0; i < exemplars.Len(); i++ {
+		traceID := exemplars.At(i).TraceID()
+		if !traceID.IsEmpty() {
+			return dimensions.MergeLists(
+				staticDimensions,
+				dimensions.NewNormalizedDimensionList(dimensions.NewDimension(exemplarTraceIDKey, traceID.HexString())),
+			)
+		}
+	}
+	return staticDimensions
+}
+
+func makeCombinedDimensions(defaultDimensions dimensions.NormalizedDimensionList, dataPointAttributes pcommon.Map, staticDimensions dimensions.NormalizedDimensionList, truncator *DimensionValueTruncator) dimensions.NormalizedDimensionList {
 	dimsFromAttributes := make([]dimensions.Dimension, 0, dataPointAttributes.Len())
 
 	dataPointAttributes.Range(func(k string, v pcommon.Value) bool {
 		dimsFromAttributes = append(dimsFromAttributes, dimensions.NewDimension(k, v.AsString()))
 		return true
 	})
+	// cap before truncating, so that only the values of the kept dimensions are counted as truncated
+	dimsFromAttributes = truncator.capDimensions(dimsFromAttributes, defaultDimensions, staticDimensions)
+	for i := range dimsFromAttributes {
+		dimsFromAttributes[i].Value = truncator.truncate(dimsFromAttributes[i].Value)
+	}
 	return dimensions.MergeLists(
 		defaultDimensions,
 		dimensions.NewNormalizedDimensionList(dimsFromAttributes...),
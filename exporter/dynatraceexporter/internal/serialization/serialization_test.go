@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/ttlmap"
 )
 
@@ -43,7 +44,7 @@ func TestSerializeMetric(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		serialized, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev)
+		serialized, _, _, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev, config.NonFiniteValuePolicyDrop, nil, false, false)
 		assert.NoError(t, err)
 
 		assert.Len(t, serialized, 1)
@@ -63,7 +64,7 @@ func TestSerializeMetric(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		serialized, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev)
+		serialized, _, _, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev, config.NonFiniteValuePolicyDrop, nil, false, false)
 		assert.NoError(t, err)
 
 		assert.Len(t, serialized, 1)
@@ -84,7 +85,7 @@ func TestSerializeMetric(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		serialized, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev)
+		serialized, _, _, err := SerializeMetric(logger, "prefix", metric, defaultDims, staticDims, prev, config.NonFiniteValuePolicyDrop, nil, false, false)
 		assert.NoError(t, err)
 
 		assert.Len(t, serialized, 1)
@@ -112,7 +113,7 @@ func Test_makeCombinedDimensions(t *testing.T) {
 		dimensions.NewDimension("c", "default"),
 	)
 
-	actual := makeCombinedDimensions(defaultDims, attributes, staticDims)
+	actual := makeCombinedDimensions(defaultDims, attributes, staticDims, nil)
 
 	sortAndStringify :=
 		func(dims []dimensions.Dimension) string {
@@ -129,6 +130,106 @@ func Test_makeCombinedDimensions(t *testing.T) {
 	assert.Equal(t, actual.Format(sortAndStringify), expected.Format(sortAndStringify))
 }
 
+func Test_makeCombinedDimensions_truncation(t *testing.T) {
+	empty := dimensions.NewNormalizedDimensionList()
+	attributes := pcommon.NewMap()
+	attributes.PutStr("long", strings.Repeat("a", 12))
+	attributes.PutStr("multibyte", "aaaaaaaaaé")
+	attributes.PutStr("short", "abc")
+	truncator := &DimensionValueTruncator{MaxLength: 10}
+
+	actual := makeCombinedDimensions(empty, attributes, empty, truncator)
+
+	values := map[string]string{}
+	actual.Format(func(dims []dimensions.Dimension) string {
+		for _, dim := range dims {
+			values[dim.Key] = dim.Value
+		}
+		return ""
+	})
+	assert.Equal(t, strings.Repeat("a", 10), values["long"])
+	// the two byte character does not fit, so it is dropped entirely
+	assert.Equal(t, "aaaaaaaaa", values["multibyte"])
+	assert.Equal(t, "abc", values["short"])
+	assert.Equal(t, 2, truncator.Truncated)
+}
+
+func Test_makeCombinedDimensions_maxDimensions(t *testing.T) {
+	defaultDims := dimensions.NewNormalizedDimensionList(
+		dimensions.NewDimension("default_a", "default"),
+		dimensions.NewDimension("default_b", "default"),
+	)
+	staticDims := dimensions.NewNormalizedDimensionList(
+		dimensions.NewDimension("dt.metrics.source", "opentelemetry"),
+	)
+	attributes := pcommon.NewMap()
+	attributes.PutStr("default_a", "attribute")
+	attributes.PutStr("first", strings.Repeat("a", 12))
+	attributes.PutStr("second", strings.Repeat("b", 12))
+	attributes.PutStr("third", strings.Repeat("c", 12))
+	truncator := &DimensionValueTruncator{MaxLength: 10, MaxDimensions: 5}
+
+	actual := makeCombinedDimensions(defaultDims, attributes, staticDims, truncator)
+
+	values := map[string]string{}
+	actual.Format(func(dims []dimensions.Dimension) string {
+		for _, dim := range dims {
+			values[dim.Key] = dim.Value
+		}
+		return ""
+	})
+	// the default and static dimensions are kept, the attribute overriding a default dimension
+	// does not count towards the limit and the last attribute is dropped
+	assert.Equal(t, map[string]string{
+		"default_a":         "attribute",
+		"default_b":         "default",
+		"dt.metrics.source": "opentelemetry",
+		"first":             strings.Repeat("a", 10),
+		"second":            strings.Repeat("b", 10),
+	}, values)
+	assert.Equal(t, 1, truncator.Capped)
+	// the value of the dropped dimension is not counted as truncated
+	assert.Equal(t, 2, truncator.Truncated)
+
+	truncator = &DimensionValueTruncator{MaxDimensions: 6}
+	makeCombinedDimensions(defaultDims, attributes, staticDims, truncator)
+	assert.Equal(t, 0, truncator.Capped)
+}
+
+func Test_withExemplarTraceID(t *testing.T) {
+	staticDims := dimensions.NewNormalizedDimensionList(dimensions.NewDimension("dt.metrics.source", "opentelemetry"))
+	exemplars := pmetric.NewExemplarSlice()
+	exemplars.AppendEmpty().SetIntValue(1)
+	exemplars.AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	exemplars.AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{16}))
+
+	values := func(dims dimensions.NormalizedDimensionList) map[string]string {
+		values := map[string]string{}
+		dims.Format(func(dims []dimensions.Dimension) string {
+			for _, dim := range dims {
+				values[dim.Key] = dim.Value
+			}
+			return ""
+		})
+		return values
+	}
+
+	t.Run("first exemplar with trace ID", func(t *testing.T) {
+		assert.Equal(t, map[string]string{
+			"dt.metrics.source": "opentelemetry",
+			"dt.trace_id":       "0102030405060708090a0b0c0d0e0f10",
+		}, values(withExemplarTraceID(staticDims, exemplars, true)))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"dt.metrics.source": "opentelemetry"}, values(withExemplarTraceID(staticDims, exemplars, false)))
+	})
+
+	t.Run("without exemplars", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"dt.metrics.source": "opentelemetry"}, values(withExemplarTraceID(staticDims, pmetric.NewExemplarSlice(), true)))
+	})
+}
+
 type simplifiedLogRecord struct {
 	message    string
 	attributes map[string]string
@@ -16,6 +16,8 @@ package serialization // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	dtMetric "github.com/dynatrace-oss/dynatrace-metric-utils-go/metric"
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
@@ -26,29 +28,32 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/ttlmap"
 )
 
-func serializeSumPoint(name, prefix string, dims dimensions.NormalizedDimensionList, t pmetric.AggregationTemporality, dp pmetric.NumberDataPoint, prev *ttlmap.TTLMap) (string, error) {
+func serializeSumPoint(name, prefix string, dims dimensions.NormalizedDimensionList, t pmetric.AggregationTemporality, dp pmetric.NumberDataPoint, prev *ttlmap.TTLMap, detectResets bool) (string, error) {
 	switch t {
+	case pmetric.AggregationTemporalityDelta:
+		// delta points are already in the shape Dynatrace expects and are passed
+		// through as-is, without touching the previous-point cache
+		return serializeDeltaCounter(name, prefix, dims, dp)
 	case pmetric.AggregationTemporalityCumulative:
-		return serializeCumulativeCounter(name, prefix, dims, dp, prev)
+		return serializeCumulativeCounter(name, prefix, dims, dp, prev, detectResets)
 	// for now unspecified is treated as delta
 	case pmetric.AggregationTemporalityUnspecified:
-		fallthrough
-	case pmetric.AggregationTemporalityDelta:
 		return serializeDeltaCounter(name, prefix, dims, dp)
 	}
 
 	return "", nil
 }
 
-func serializeSum(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, prev *ttlmap.TTLMap, metricLines []string) []string {
+func serializeSum(logger *zap.Logger, prefix string, metric pmetric.Metric, defaultDimensions dimensions.NormalizedDimensionList, staticDimensions dimensions.NormalizedDimensionList, prev *ttlmap.TTLMap, nonFiniteValuePolicy string, truncator *DimensionValueTruncator, detectResets bool, sendExemplarTraceIDs bool, metricLines []string) ([]string, int, error) {
 	sum := metric.Sum()
+	dropped := 0
 
 	if !sum.IsMonotonic() && sum.AggregationTemporality() == pmetric.AggregationTemporalityDelta {
 		logger.Warn(
 			"dropping delta non-monotonic sum",
 			zap.String("name", metric.Name()),
 		)
-		return metricLines
+		return metricLines, dropped, nil
 	}
 
 	points := metric.Sum().DataPoints()
@@ -60,10 +65,11 @@ func serializeSum(logger *zap.Logger, prefix string, metric pmetric.Metric, defa
 			line, err := serializeSumPoint(
 				metric.Name(),
 				prefix,
-				makeCombinedDimensions(defaultDimensions, dp.Attributes(), staticDimensions),
+				makeCombinedDimensions(defaultDimensions, dp.Attributes(), withExemplarTraceID(staticDimensions, dp.Exemplars(), sendExemplarTraceIDs), truncator),
 				metric.Sum().AggregationTemporality(),
 				dp,
 				prev,
+				detectResets,
 			)
 
 			if err != nil {
@@ -83,11 +89,19 @@ func serializeSum(logger *zap.Logger, prefix string, metric pmetric.Metric, defa
 			line, err := serializeGaugePoint(
 				metric.Name(),
 				prefix,
-				makeCombinedDimensions(defaultDimensions, dp.Attributes(), staticDimensions),
+				makeCombinedDimensions(defaultDimensions, dp.Attributes(), withExemplarTraceID(staticDimensions, dp.Exemplars(), sendExemplarTraceIDs), truncator),
 				dp,
+				nonFiniteValuePolicy,
 			)
 
 			if err != nil {
+				if ok, err := handleNonFiniteValueError(logger, metric.Name(), err); ok {
+					if err != nil {
+						return nil, dropped, err
+					}
+					dropped++
+					continue
+				}
 				logger.Warn(
 					"Error serializing non-monotonic Sum as gauge",
 					zap.String("name", metric.Name()),
@@ -102,7 +116,7 @@ func serializeSum(logger *zap.Logger, prefix string, metric pmetric.Metric, defa
 		}
 	}
 
-	return metricLines
+	return metricLines, dropped, nil
 }
 
 func serializeDeltaCounter(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint) (string, error) {
@@ -134,8 +148,8 @@ func serializeDeltaCounter(name, prefix string, dims dimensions.NormalizedDimens
 	return dm.Serialize()
 }
 
-func serializeCumulativeCounter(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint, prev *ttlmap.TTLMap) (string, error) {
-	dm, err := convertTotalCounterToDelta(name, prefix, dims, dp, prev)
+func serializeCumulativeCounter(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint, prev *ttlmap.TTLMap, detectResets bool) (string, error) {
+	dm, err := convertTotalCounterToDelta(name, prefix, dims, dp, prev, detectResets)
 
 	if err != nil {
 		return "", err
@@ -148,13 +162,19 @@ func serializeCumulativeCounter(name, prefix string, dims dimensions.NormalizedD
 	return dm.Serialize()
 }
 
-func convertTotalCounterToDelta(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint, prevCounters *ttlmap.TTLMap) (*dtMetric.Metric, error) {
+// convertTotalCounterToDelta converts a cumulative counter point to the delta from the previous point of the series.
+// The first point of a series is only remembered. If detectResets is set, a point with a lower value than the previous
+// point is a reset of the counter, and its value is the delta since the reset.
+// The series of a point is identified by its attributes and its entity dimensions, so that resources mapped to
+// different entities do not share a series.
+func convertTotalCounterToDelta(name, prefix string, dims dimensions.NormalizedDimensionList, dp pmetric.NumberDataPoint, prevCounters *ttlmap.TTLMap, detectResets bool) (*dtMetric.Metric, error) {
 	id := name
 
 	dp.Attributes().Sort().Range(func(k string, v pcommon.Value) bool {
 		id += fmt.Sprintf(",%s=%s", k, v.AsString())
 		return true
 	})
+	id += dims.Format(entityDimensionsID)
 
 	prevCounter := prevCounters.Get(id)
 
@@ -179,9 +199,17 @@ func convertTotalCounterToDelta(name, prefix string, dims dimensions.NormalizedD
 
 	switch {
 	case dp.ValueType() == pmetric.NumberDataPointValueTypeInt:
-		valueOpt = dtMetric.WithIntCounterValueDelta(dp.IntValue() - oldCount.IntValue())
+		if detectResets && dp.IntValue() < oldCount.IntValue() {
+			valueOpt = dtMetric.WithIntCounterValueDelta(dp.IntValue())
+		} else {
+			valueOpt = dtMetric.WithIntCounterValueDelta(dp.IntValue() - oldCount.IntValue())
+		}
 	case dp.ValueType() == pmetric.NumberDataPointValueTypeDouble:
-		valueOpt = dtMetric.WithFloatCounterValueDelta(dp.DoubleValue() - oldCount.DoubleValue())
+		if detectResets && dp.DoubleValue() < oldCount.DoubleValue() {
+			valueOpt = dtMetric.WithFloatCounterValueDelta(dp.DoubleValue())
+		} else {
+			valueOpt = dtMetric.WithFloatCounterValueDelta(dp.DoubleValue() - oldCount.DoubleValue())
+		}
 	default:
 		return nil, fmt.Errorf("%s value type %s not supported", name, metricValueTypeToString(dp.ValueType()))
 	}
@@ -215,3 +243,15 @@ func metricValueTypeToString(t pmetric.NumberDataPointValueType) string {
 		return "MetricValueTypeUnknown"
 	}
 }
+
+// entityDimensionsID returns the entity dimensions sorted by key, in the form of the attributes of a series ID
+func entityDimensionsID(dims []dimensions.Dimension) string {
+	var entities []string
+	for _, dim := range dims {
+		if strings.HasPrefix(dim.Key, EntityDimensionPrefix) {
+			entities = append(entities, fmt.Sprintf(",%s=%s", dim.Key, dim.Value))
+		}
+	}
+	sort.Strings(entities)
+	return strings.Join(entities, "")
+}
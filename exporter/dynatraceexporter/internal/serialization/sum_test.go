@@ -21,11 +21,13 @@ import (
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/ttlmap"
 )
 
@@ -34,7 +36,7 @@ func Test_serializeSumPoint(t *testing.T) {
 		dp := pmetric.NewNumberDataPoint()
 		dp.SetIntValue(5)
 
-		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(), pmetric.AggregationTemporalityDelta, dp, ttlmap.New(1, 1))
+		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(), pmetric.AggregationTemporalityDelta, dp, ttlmap.New(1, 1), false)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.int_sum count,delta=5", got)
 	})
@@ -46,7 +48,7 @@ func Test_serializeSumPoint(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		got, err := serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityDelta, dp, prev)
+		got, err := serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityDelta, dp, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.double_sum,key=value count,delta=5.5 1626438600000", got)
 	})
@@ -56,7 +58,7 @@ func Test_serializeSumPoint(t *testing.T) {
 		dp.SetIntValue(5)
 		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano()))
 
-		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityDelta, dp, ttlmap.New(1, 1))
+		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityDelta, dp, ttlmap.New(1, 1), false)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.int_sum,key=value count,delta=5 1626438600000", got)
 	})
@@ -72,11 +74,11 @@ func Test_serializeSumPoint(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		got, err := serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev)
+		got, err := serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "", got)
 
-		got, err = serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev)
+		got, err = serializeSumPoint("double_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.double_sum,key=value count,delta=1.5 1626438660000", got)
 	})
@@ -92,11 +94,11 @@ func Test_serializeSumPoint(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev)
+		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "", got)
 
-		got, err = serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev)
+		got, err = serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "prefix.int_sum,key=value count,delta=5 1626438660000", got)
 	})
@@ -128,10 +130,10 @@ func Test_serializeSumPoint(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "a")), pmetric.AggregationTemporalityCumulative, dp, prev)
-		got2, err2 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "b")), pmetric.AggregationTemporalityCumulative, dp2, prev)
-		got3, err3 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "a")), pmetric.AggregationTemporalityCumulative, dp3, prev)
-		got4, err4 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "b")), pmetric.AggregationTemporalityCumulative, dp4, prev)
+		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "a")), pmetric.AggregationTemporalityCumulative, dp, prev, false)
+		got2, err2 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "b")), pmetric.AggregationTemporalityCumulative, dp2, prev, false)
+		got3, err3 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "a")), pmetric.AggregationTemporalityCumulative, dp3, prev, false)
+		got4, err4 := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "b")), pmetric.AggregationTemporalityCumulative, dp4, prev, false)
 
 		assert.NoError(t, err)
 		assert.NoError(t, err2)
@@ -154,13 +156,13 @@ func Test_serializeSumPoint(t *testing.T) {
 
 		prev := ttlmap.New(1, 1)
 
-		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev)
+		got, err := serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "", got)
 
 		assert.Equal(t, dp, prev.Get("int_sum"))
 
-		got, err = serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev)
+		got, err = serializeSumPoint("int_sum", "prefix", dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value")), pmetric.AggregationTemporalityCumulative, dp2, prev, false)
 		assert.NoError(t, err)
 		assert.Equal(t, "", got)
 
@@ -168,6 +170,92 @@ func Test_serializeSumPoint(t *testing.T) {
 	})
 }
 
+func Test_convertTotalCounterToDelta_detectResets(t *testing.T) {
+	dims := dimensions.NewNormalizedDimensionList(dimensions.NewDimension("key", "value"))
+	point := func(value int64, minute int) pmetric.NumberDataPoint {
+		dp := pmetric.NewNumberDataPoint()
+		dp.SetIntValue(value)
+		dp.Attributes().PutStr("key", "value")
+		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, minute, 0, 0, time.UTC).UnixNano()))
+		return dp
+	}
+	serialize := func(prev *ttlmap.TTLMap, detectResets bool, dp pmetric.NumberDataPoint) string {
+		got, err := serializeSumPoint("int_sum", "prefix", dims, pmetric.AggregationTemporalityCumulative, dp, prev, detectResets)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("monotonic sequence", func(t *testing.T) {
+		prev := ttlmap.New(1, 1)
+		assert.Equal(t, "", serialize(prev, true, point(5, 30)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=3 1626438660000", serialize(prev, true, point(8, 31)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=0 1626438720000", serialize(prev, true, point(8, 32)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=4 1626438780000", serialize(prev, true, point(12, 33)))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		prev := ttlmap.New(1, 1)
+		assert.Equal(t, "", serialize(prev, true, point(10, 30)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=3 1626438660000", serialize(prev, true, point(3, 31)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=2 1626438720000", serialize(prev, true, point(5, 32)))
+	})
+
+	t.Run("reset without detection", func(t *testing.T) {
+		prev := ttlmap.New(1, 1)
+		assert.Equal(t, "", serialize(prev, false, point(10, 30)))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=-7 1626438660000", serialize(prev, false, point(3, 31)))
+	})
+
+	t.Run("double reset", func(t *testing.T) {
+		prev := ttlmap.New(1, 1)
+		dp := pmetric.NewNumberDataPoint()
+		dp.SetDoubleValue(10.5)
+		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano()))
+		dp2 := pmetric.NewNumberDataPoint()
+		dp2.SetDoubleValue(1.5)
+		dp2.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 31, 0, 0, time.UTC).UnixNano()))
+		assert.Equal(t, "", serialize(prev, true, dp))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=1.5 1626438660000", serialize(prev, true, dp2))
+	})
+
+	t.Run("new series", func(t *testing.T) {
+		prev := ttlmap.New(1, 1)
+		assert.Equal(t, "", serialize(prev, true, point(10, 30)))
+		other := point(100, 31)
+		other.Attributes().PutStr("key", "other")
+		// the first point of a new series is remembered but not sent
+		assert.Equal(t, "", serialize(prev, true, other))
+		assert.Equal(t, "prefix.int_sum,key=value count,delta=5 1626438720000", serialize(prev, true, point(15, 32)))
+	})
+}
+
+func Test_convertTotalCounterToDelta_entities(t *testing.T) {
+	point := func(value int64, minute int) pmetric.NumberDataPoint {
+		dp := pmetric.NewNumberDataPoint()
+		dp.SetIntValue(value)
+		dp.Attributes().PutStr("key", "value")
+		dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, minute, 0, 0, time.UTC).UnixNano()))
+		return dp
+	}
+	hostDims := func(host string) dimensions.NormalizedDimensionList {
+		return dimensions.NewNormalizedDimensionList(
+			dimensions.NewDimension("dt.entity.host", host),
+			dimensions.NewDimension("key", "value"),
+		)
+	}
+	serialize := func(prev *ttlmap.TTLMap, host string, dp pmetric.NumberDataPoint) string {
+		got, err := serializeSumPoint("int_sum", "prefix", hostDims(host), pmetric.AggregationTemporalityCumulative, dp, prev, false)
+		require.NoError(t, err)
+		return got
+	}
+
+	prev := ttlmap.New(1, 1)
+	assert.Equal(t, "", serialize(prev, "HOST-A", point(10, 30)))
+	assert.Equal(t, "", serialize(prev, "HOST-B", point(1000, 30)))
+	assert.Equal(t, "prefix.int_sum,dt.entity.host=HOST-A,key=value count,delta=5 1626438660000", serialize(prev, "HOST-A", point(15, 31)))
+	assert.Equal(t, "prefix.int_sum,dt.entity.host=HOST-B,key=value count,delta=20 1626438660000", serialize(prev, "HOST-B", point(1020, 31)))
+}
+
 func Test_serializeSum(t *testing.T) {
 	empty := dimensions.NewNormalizedDimensionList()
 	t.Run("non-monotonic delta is dropped", func(t *testing.T) {
@@ -181,7 +269,8 @@ func Test_serializeSum(t *testing.T) {
 		zapCore, observedLogs := observer.New(zap.WarnLevel)
 		logger := zap.New(zapCore)
 
-		lines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+		lines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+		assert.NoError(t, err)
 
 		assert.Empty(t, lines)
 
@@ -213,7 +302,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLines := []string{
 				"metric_name count,delta=12",
@@ -222,6 +312,25 @@ func Test_serializeSum(t *testing.T) {
 			assert.Empty(t, observedLogs.All())
 		})
 
+		t.Run("is passed through without using the previous point cache", func(t *testing.T) {
+			dp.SetDoubleValue(4.5)
+			dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, 30, 0, 0, time.UTC).UnixNano()))
+
+			prev := ttlmap.New(10, 10)
+
+			zapCore, observedLogs := observer.New(zap.WarnLevel)
+			logger := zap.New(zapCore)
+
+			// the same delta point exported twice is sent as-is both times
+			for i := 0; i < 2; i++ {
+				actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"metric_name count,delta=4.5 1626438600000"}, actualLines)
+			}
+			assert.Nil(t, prev.Get("metric_name"))
+			assert.Empty(t, observedLogs.All())
+		})
+
 		t.Run("with invalid value logs warning and returns no line", func(t *testing.T) {
 			dp.SetDoubleValue(math.NaN())
 
@@ -230,7 +339,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLogRecords := []simplifiedLogRecord{
 				{
@@ -266,7 +376,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLines := []string{
 				"metric_name gauge,12.3",
@@ -277,7 +388,7 @@ func Test_serializeSum(t *testing.T) {
 			assert.Empty(t, observedLogs.All())
 		})
 
-		t.Run("with invalid value logs warning and returns no line", func(t *testing.T) {
+		t.Run("with non-finite value is dropped", func(t *testing.T) {
 			dp.SetDoubleValue(math.NaN())
 
 			prev := ttlmap.New(10, 10)
@@ -285,21 +396,34 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, dropped, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
-			expectedLogRecords := []simplifiedLogRecord{
-				{
-					message: "Error serializing non-monotonic Sum as gauge",
-					attributes: map[string]string{
-						"name":       "metric_name",
-						"value-type": "Double",
-						"error":      "value is NaN.",
-					},
-				},
-			}
+			assert.Empty(t, actualLines)
+			assert.Equal(t, 1, dropped)
+			assert.Empty(t, makeSimplifiedLogRecordsFromObservedLogs(observedLogs))
+		})
+
+		t.Run("with non-finite value is sent as zero", func(t *testing.T) {
+			dp.SetDoubleValue(math.Inf(1))
+
+			prev := ttlmap.New(10, 10)
+
+			actualLines, dropped, err := serializeSum(zap.NewNop(), "", metric, empty, empty, prev, config.NonFiniteValuePolicyZero, nil, false, false, []string{})
+			assert.NoError(t, err)
 
+			assert.Equal(t, []string{"metric_name gauge,0"}, actualLines)
+			assert.Equal(t, 0, dropped)
+		})
+
+		t.Run("with non-finite value fails with error policy", func(t *testing.T) {
+			dp.SetDoubleValue(math.NaN())
+
+			prev := ttlmap.New(10, 10)
+
+			actualLines, _, err := serializeSum(zap.NewNop(), "", metric, empty, empty, prev, config.NonFiniteValuePolicyError, nil, false, false, []string{})
+			assert.ErrorIs(t, err, ErrNonFiniteValue)
 			assert.Empty(t, actualLines)
-			assert.ElementsMatch(t, makeSimplifiedLogRecordsFromObservedLogs(observedLogs), expectedLogRecords)
 		})
 
 		invalidDp := sum.DataPoints().AppendEmpty()
@@ -326,7 +450,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLines := []string{
 				"metric_name count,delta=0.5",
@@ -346,7 +471,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLogRecords := []simplifiedLogRecord{
 				{
@@ -373,7 +499,8 @@ func Test_serializeSum(t *testing.T) {
 			zapCore, observedLogs := observer.New(zap.WarnLevel)
 			logger := zap.New(zapCore)
 
-			actualLines := serializeSum(logger, "", metric, empty, empty, prev, []string{})
+			actualLines, _, err := serializeSum(logger, "", metric, empty, empty, prev, config.NonFiniteValuePolicyDrop, nil, false, false, []string{})
+			assert.NoError(t, err)
 
 			expectedLogRecords := []simplifiedLogRecord{
 				{
@@ -18,16 +18,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/apiconstants"
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 
@@ -39,10 +45,12 @@ import (
 const (
 	cSweepIntervalSeconds = 300
 	cMaxAgeSeconds        = 900
+
+	headerRetryAfter = "Retry-After"
 )
 
 // NewExporter exports to a Dynatrace Metrics v2 API
-func newMetricsExporter(params component.ExporterCreateSettings, cfg *config.Config) *exporter {
+func newMetricsExporter(params component.ExporterCreateSettings, cfg *config.Config) (*exporter, error) {
 	var confDefaultDims []dimensions.Dimension
 	for key, value := range cfg.DefaultDimensions {
 		confDefaultDims = append(confDefaultDims, dimensions.NewDimension(key, value))
@@ -55,16 +63,28 @@ func newMetricsExporter(params component.ExporterCreateSettings, cfg *config.Con
 
 	staticDimensions := dimensions.NewNormalizedDimensionList(dimensions.NewDimension("dt.metrics.source", "opentelemetry"))
 
+	metrics, err := newOpenCensusMetrics(cfg.ID().Name())
+	if err != nil {
+		return nil, err
+	}
+
 	prevPts := ttlmap.New(cSweepIntervalSeconds, cMaxAgeSeconds)
 	prevPts.Start()
 
+	var token *fileToken
+	if cfg.APITokenFile != "" {
+		token = newFileToken(cfg.APITokenFile, cfg.TokenRefreshInterval)
+	}
+
 	return &exporter{
 		settings:          params.TelemetrySettings,
 		cfg:               cfg,
+		token:             token,
 		defaultDimensions: defaultDimensions,
 		staticDimensions:  staticDimensions,
 		prevPts:           prevPts,
-	}
+		metrics:           metrics,
+	}, nil
 }
 
 // exporter forwards metrics to a Dynatrace agent
@@ -74,10 +94,23 @@ type exporter struct {
 	client     *http.Client
 	isDisabled bool
 
+	// token provides the API token read from api_token_file, nil if the token is configured by api_token
+	token *fileToken
+
 	defaultDimensions dimensions.NormalizedDimensionList
 	staticDimensions  dimensions.NormalizedDimensionList
 
 	prevPts *ttlmap.TTLMap
+
+	metrics *opencensusMetrics
+
+	// pending holds the serialized lines which did not fill a whole batch yet, nil if there are none.
+	// They are sent once enough lines are accumulated or by the flush loop.
+	pendingMu sync.Mutex
+	pending   *pendingBatch
+
+	stopFlush chan struct{}
+	flushDone chan struct{}
 }
 
 // for backwards-compatibility with deprecated `Tags` config option
@@ -97,7 +130,10 @@ func (e *exporter) PushMetricsData(ctx context.Context, md pmetric.Metrics) erro
 		return nil
 	}
 
-	lines := e.serializeMetrics(md)
+	lines, err := e.serializeMetrics(md)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
 	e.settings.Logger.Debug(
 		"Serialization complete",
 		zap.Int("data-point-count", md.DataPointCount()),
@@ -110,22 +146,133 @@ func (e *exporter) PushMetricsData(ctx context.Context, md pmetric.Metrics) erro
 		return nil
 	}
 
-	err := e.send(ctx, lines)
+	if e.cfg.FlushInterval <= 0 {
+		return e.send(ctx, lines)
+	}
 
-	if err != nil {
-		return err
+	return e.enqueue(ctx, lines)
+}
+
+// pendingBatch holds the lines of the pushes waiting to be sent together. Each push waits until
+// the batch is sent and returns the result of the send, so that exporterhelper retries the lines
+// of a failed batch and nothing is reported as sent before it is.
+type pendingBatch struct {
+	entries []*pendingEntry
+	lines   int
+
+	// done is closed once the batch is sent, err holds the result of the send
+	done chan struct{}
+	err  error
+}
+
+// pendingEntry holds the lines of a single push. A push whose context is done before its batch
+// is sent is cancelled and its lines are not sent.
+type pendingEntry struct {
+	lines     []string
+	cancelled bool
+}
+
+// enqueue adds lines to the pending batch and waits until the batch is sent, either because it
+// reached the payload lines limit or by the flush loop.
+func (e *exporter) enqueue(ctx context.Context, lines []string) error {
+	entry := &pendingEntry{lines: lines}
+
+	e.pendingMu.Lock()
+	batch := e.pending
+	if batch == nil {
+		batch = &pendingBatch{done: make(chan struct{})}
+		e.pending = batch
+	}
+	batch.entries = append(batch.entries, entry)
+	batch.lines += len(lines)
+	if batch.lines >= apiconstants.GetPayloadLinesLimit() {
+		e.pending = nil
+		e.pendingMu.Unlock()
+		return e.sendPending(ctx, batch)
+	}
+	e.pendingMu.Unlock()
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		e.pendingMu.Lock()
+		if e.pending == batch {
+			// the batch is not being sent yet, withdraw the lines so that they are only sent by the retry
+			entry.cancelled = true
+			batch.lines -= len(lines)
+			e.pendingMu.Unlock()
+			return ctx.Err()
+		}
+		e.pendingMu.Unlock()
+		// the lines are being sent, report the actual result
+		<-batch.done
+		return batch.err
 	}
+}
 
-	return nil
+// flush sends the pending batch regardless of its number of lines.
+func (e *exporter) flush(ctx context.Context) error {
+	e.pendingMu.Lock()
+	batch := e.pending
+	e.pending = nil
+	e.pendingMu.Unlock()
+
+	if batch == nil {
+		return nil
+	}
+
+	return e.sendPending(ctx, batch)
+}
+
+// sendPending sends the lines of the entries of batch which were not cancelled and reports the result
+// to the pushes waiting for the batch. The batch must not be pending anymore.
+func (e *exporter) sendPending(ctx context.Context, batch *pendingBatch) error {
+	lines := make([]string, 0, batch.lines)
+	for _, entry := range batch.entries {
+		if !entry.cancelled {
+			lines = append(lines, entry.lines...)
+		}
+	}
+	if len(lines) > 0 {
+		batch.err = e.send(ctx, lines)
+	}
+	close(batch.done)
+	return batch.err
+}
+
+// flushLoop periodically sends the pending batch until stopFlush is closed. Send errors are
+// returned to the pushes waiting for the batch.
+func (e *exporter) flushLoop() {
+	defer close(e.flushDone)
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = e.flush(context.Background())
+		case <-e.stopFlush:
+			return
+		}
+	}
 }
 
-func (e *exporter) serializeMetrics(md pmetric.Metrics) []string {
+func (e *exporter) serializeMetrics(md pmetric.Metrics) ([]string, error) {
 	var lines []string
+	dropped := 0
+	estimated := 0
+	truncator := &serialization.DimensionValueTruncator{MaxLength: e.cfg.MaxDimensionValueLength, MaxDimensions: e.cfg.MaxDimensions}
 
 	resourceMetrics := md.ResourceMetrics()
 
 	for i := 0; i < resourceMetrics.Len(); i++ {
 		resourceMetric := resourceMetrics.At(i)
+		defaultDimensions := e.defaultDimensions
+		if e.cfg.AutoEntityMapping {
+			defaultDimensions = dimensions.MergeLists(defaultDimensions, entityDimensions(resourceMetric.Resource().Attributes()))
+		}
 		libraryMetrics := resourceMetric.ScopeMetrics()
 		for j := 0; j < libraryMetrics.Len(); j++ {
 			libraryMetric := libraryMetrics.At(j)
@@ -133,7 +280,13 @@ func (e *exporter) serializeMetrics(md pmetric.Metrics) []string {
 			for k := 0; k < metrics.Len(); k++ {
 				metric := metrics.At(k)
 
-				metricLines, err := serialization.SerializeMetric(e.settings.Logger, e.cfg.Prefix, metric, e.defaultDimensions, e.staticDimensions, e.prevPts)
+				metricLines, droppedPoints, estimatedPoints, err := serialization.SerializeMetric(e.settings.Logger, e.cfg.Prefix, metric, defaultDimensions, e.staticDimensions, e.prevPts, e.cfg.NonFiniteValuePolicy, truncator, e.cfg.DetectCounterResets, e.cfg.SendExemplarTraceIDs)
+				dropped += droppedPoints
+				estimated += estimatedPoints
+
+				if errors.Is(err, serialization.ErrNonFiniteValue) {
+					return nil, err
+				}
 
 				if err != nil {
 					e.settings.Logger.Warn(
@@ -157,7 +310,32 @@ func (e *exporter) serializeMetrics(md pmetric.Metrics) []string {
 		}
 	}
 
-	return lines
+	if dropped > 0 {
+		e.metrics.recordDroppedMetrics(dropped)
+	}
+	if truncator.Truncated > 0 {
+		e.metrics.recordTruncatedDimensions(truncator.Truncated)
+	}
+	if truncator.Capped > 0 {
+		e.metrics.recordCappedDimensions(truncator.Capped)
+	}
+	if estimated > 0 {
+		e.metrics.recordEstimatedHistograms(estimated)
+	}
+
+	return lines, nil
+}
+
+// entityDimensions returns the dt.entity.* attributes as dimensions
+func entityDimensions(attributes pcommon.Map) dimensions.NormalizedDimensionList {
+	var dims []dimensions.Dimension
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		if strings.HasPrefix(k, serialization.EntityDimensionPrefix) {
+			dims = append(dims, dimensions.NewDimension(k, v.AsString()))
+		}
+		return true
+	})
+	return dimensions.NewNormalizedDimensionList(dims...)
 }
 
 var lastLog int64
@@ -177,20 +355,66 @@ func (e *exporter) send(ctx context.Context, lines []string) error {
 		lastLog = time.Now().Unix()
 	}
 
-	for i := 0; i < len(lines); i += apiconstants.GetPayloadLinesLimit() {
-		end := i + apiconstants.GetPayloadLinesLimit()
+	maxBytes := e.cfg.MaxRequestBytes
+	if maxBytes <= 0 {
+		maxBytes = config.RequestMaxBytes
+	}
+
+	// a chunk rejected by Dynatrace is not retried, the following chunks are still sent
+	var rejected error
+	sendChunk := func(chunk []string) error {
+		err := e.sendChunk(ctx, chunk)
+		if consumererror.IsPermanent(err) {
+			if rejected == nil {
+				rejected = err
+			}
+			return nil
+		}
+		return err
+	}
 
-		if end > len(lines) {
-			end = len(lines)
+	start, size := 0, 0
+	for i, line := range lines {
+		if len(line) > maxBytes {
+			e.settings.Logger.Warn(
+				"Dropping metric line larger than max_request_bytes",
+				zap.Int("line-len", len(line)),
+				zap.Int("max_request_bytes", maxBytes),
+			)
+			if err := sendChunk(lines[start:i]); err != nil {
+				return err
+			}
+			e.metrics.recordDroppedMetrics(1)
+			start, size = i+1, 0
+			continue
 		}
 
-		err := e.sendBatch(ctx, lines[i:end])
-		if err != nil {
-			return err
+		// lines are joined with a newline, which counts towards the request size
+		lineSize := len(line)
+		if i > start {
+			lineSize++
+		}
+		if i-start == apiconstants.GetPayloadLinesLimit() || size+lineSize > maxBytes {
+			if err := sendChunk(lines[start:i]); err != nil {
+				return err
+			}
+			start, size, lineSize = i, 0, len(line)
 		}
+		size += lineSize
 	}
 
-	return nil
+	if err := sendChunk(lines[start:]); err != nil {
+		return err
+	}
+	return rejected
+}
+
+// sendChunk sends a chunk of lines fitting in a single request, if it is not empty.
+func (e *exporter) sendChunk(ctx context.Context, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return e.sendBatch(ctx, lines)
 }
 
 // send sends a serialized metric batch to Dynatrace.
@@ -209,11 +433,21 @@ func (e *exporter) sendBatch(ctx context.Context, lines []string) error {
 		return consumererror.NewPermanent(err)
 	}
 
+	if e.token != nil {
+		token, err := e.token.get()
+		if token == "" {
+			return err
+		}
+		if err != nil {
+			e.settings.Logger.Warn("Failed to refresh the API token, using the previous token", zap.Error(err))
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Api-Token %s", token))
+	}
+
 	resp, err := e.client.Do(req)
 
 	if err != nil {
-		e.settings.Logger.Error("failed to send request", zap.Error(err))
-		return fmt.Errorf("sendBatch: %w", err)
+		return sendError(e.settings.Logger, "sendBatch", err)
 	}
 
 	defer resp.Body.Close()
@@ -224,21 +458,20 @@ func (e *exporter) sendBatch(ctx context.Context, lines []string) error {
 	}
 
 	if resp.StatusCode == http.StatusBadRequest {
-		// At least some metrics were not accepted
+		// At least some metrics were not accepted, resending them will not help
+		rejected := consumererror.NewPermanent(fmt.Errorf("metric lines rejected by Dynatrace: %s", resp.Status))
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			// if the response cannot be read, do not retry the batch as it may have been successful
 			e.settings.Logger.Error("Failed to read response from Dynatrace", zap.Error(err))
-			return nil
+			return rejected
 		}
 
 		responseBody := metricsResponse{}
 		if err := json.Unmarshal(bodyBytes, &responseBody); err != nil {
-			// if the response cannot be read, do not retry the batch as it may have been successful
 			bodyStr := string(bodyBytes)
 			bodyStr = truncateString(bodyStr, 1000)
 			e.settings.Logger.Error("Failed to unmarshal response from Dynatrace", zap.Error(err), zap.String("body", bodyStr))
-			return nil
+			return rejected
 		}
 
 		e.settings.Logger.Warn(
@@ -258,12 +491,15 @@ func (e *exporter) sendBatch(ctx context.Context, lines []string) error {
 			}
 		}
 
-		return nil
+		return consumererror.NewPermanent(fmt.Errorf("%d of %d metric lines rejected by Dynatrace: %s",
+			responseBody.Invalid, len(lines), responseBody.Error.Message))
 	}
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		// token is missing or wrong format
-		e.isDisabled = true
+		// token is missing or wrong format, unless it is read from a file where it may be rotated
+		if e.token == nil {
+			e.isDisabled = true
+		}
 		return consumererror.NewPermanent(fmt.Errorf("API token missing or invalid"))
 	}
 
@@ -275,6 +511,13 @@ func (e *exporter) sendBatch(ctx context.Context, lines []string) error {
 		return consumererror.NewPermanent(fmt.Errorf("metrics ingest v2 module not found - ensure module is enabled and endpoint is correct"))
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		// Dynatrace is overloaded, wait for the delay it suggests before retrying.
+		// Without a Retry-After header, the delay is 0 and the retry_on_failure backoff applies.
+		delay := retryAfter(resp.Header.Get(headerRetryAfter), time.Now())
+		return exporterhelper.NewThrottleRetry(fmt.Errorf("request throttled by Dynatrace: %s", resp.Status), delay)
+	}
+
 	// No known errors
 	return nil
 }
@@ -289,9 +532,34 @@ func (e *exporter) start(_ context.Context, host component.Host) (err error) {
 
 	e.client = client
 
+	if e.token != nil {
+		if _, err = e.token.get(); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+	}
+
+	if e.cfg.FlushInterval > 0 {
+		e.stopFlush = make(chan struct{})
+		e.flushDone = make(chan struct{})
+		go e.flushLoop()
+	}
+
 	return nil
 }
 
+// shutdown stops the flush loop and sends the remaining pending lines
+func (e *exporter) shutdown(ctx context.Context) error {
+	if e.stopFlush == nil {
+		return nil
+	}
+
+	close(e.stopFlush)
+	<-e.flushDone
+	e.stopFlush = nil
+
+	return e.flush(ctx)
+}
+
 func truncateString(str string, num int) string {
 	truncated := str
 	if len(str) > num {
@@ -303,6 +571,42 @@ func truncateString(str string, num int) string {
 	return truncated
 }
 
+// sendError wraps an error returned by the HTTP client, which exporterhelper retries.
+// Connection resets and unexpected EOFs are usually caused by network blips or by idle
+// connections closed by a proxy, they are explicitly retried and only logged as a warning.
+func sendError(logger *zap.Logger, op string, err error) error {
+	if isConnectionReset(err) {
+		logger.Warn("connection reset while sending request, the request will be retried", zap.Error(err))
+	} else {
+		logger.Error("failed to send request", zap.Error(err))
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// retryAfter returns the delay suggested by a Retry-After header value, which holds either a number of seconds
+// or an HTTP date. A missing, invalid or past value results in a delay of 0.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// isConnectionReset returns whether err was caused by the connection being closed by the peer
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // Response from Dynatrace is expected to be in JSON format
 type metricsResponse struct {
 	Ok      int                  `json:"linesOk"`
@@ -15,20 +15,28 @@
 package dynatraceexporter
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/dynatrace-oss/dynatrace-metric-utils-go/metric/dimensions"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/consumererror"
@@ -37,6 +45,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/internal/serialization"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/ttlmap"
 )
 
@@ -392,6 +401,394 @@ func Test_exporter_PushMetricsData_EmptyPayload(t *testing.T) {
 	}
 }
 
+func Test_exporter_PushMetricsData_NonFiniteValues(t *testing.T) {
+	newMetrics := func() pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("double_gauge")
+		dataPoints := metric.SetEmptyGauge().DataPoints()
+		for _, value := range []float64{math.NaN(), math.Inf(1), 1.5} {
+			dataPoint := dataPoints.AppendEmpty()
+			dataPoint.SetDoubleValue(value)
+			dataPoint.SetTimestamp(testTimestamp)
+		}
+		return md
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		wantLines   []string
+		wantDropped interface{}
+		wantErr     bool
+	}{
+		{
+			name:   "drop",
+			policy: config.NonFiniteValuePolicyDrop,
+			wantLines: []string{
+				"double_gauge gauge,1.5 1626438600000",
+			},
+			wantDropped: 2,
+		},
+		{
+			name:   "zero",
+			policy: config.NonFiniteValuePolicyZero,
+			wantLines: []string{
+				"double_gauge gauge,0 1626438600000",
+				"double_gauge gauge,0 1626438600000",
+				"double_gauge gauge,1.5 1626438600000",
+			},
+		},
+		{
+			name:    "error",
+			policy:  config.NonFiniteValuePolicyError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				sent = strings.Split(string(bodyBytes), "\n")
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer ts.Close()
+
+			e := &exporter{
+				settings: componenttest.NewNopTelemetrySettings(),
+				cfg: &config.Config{
+					HTTPClientSettings:   confighttp.HTTPClientSettings{Endpoint: ts.URL},
+					NonFiniteValuePolicy: tt.policy,
+				},
+				client:  ts.Client(),
+				metrics: newTestMetrics(t),
+			}
+
+			err := e.PushMetricsData(context.Background(), newMetrics())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, serialization.ErrNonFiniteValue)
+				assert.True(t, consumererror.IsPermanent(err))
+				assert.Nil(t, sent, "batch should not be sent")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantLines, sent)
+			validateMetric(t, e.metrics.views.droppedMetrics, tt.wantDropped)
+		})
+	}
+}
+
+func Test_exporter_PushMetricsData_AutoEntityMapping(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("dt.entity.process_group_instance", "PROCESS_GROUP_INSTANCE-0123456789ABCDEF")
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("int_gauge")
+	dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(10)
+	dataPoint.SetTimestamp(testTimestamp)
+	dataPoint.Attributes().PutStr("dt.entity.host", "HOST-0123456789ABCDEF")
+
+	tests := []struct {
+		name              string
+		autoEntityMapping bool
+		wantLine          string
+	}{
+		{
+			name:              "enabled",
+			autoEntityMapping: true,
+			wantLine:          "int_gauge,dt.entity.process_group_instance=PROCESS_GROUP_INSTANCE-0123456789ABCDEF,dt.entity.host=HOST-0123456789ABCDEF,dt.metrics.source=opentelemetry gauge,10 1626438600000",
+		},
+		{
+			name:              "disabled",
+			autoEntityMapping: false,
+			wantLine:          "int_gauge,dt.entity.host=HOST-0123456789ABCDEF,dt.metrics.source=opentelemetry gauge,10 1626438600000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				bodyBytes, _ := io.ReadAll(r.Body)
+				sent = string(bodyBytes)
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer ts.Close()
+
+			e := &exporter{
+				settings: componenttest.NewNopTelemetrySettings(),
+				cfg: &config.Config{
+					HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+					AutoEntityMapping:  tt.autoEntityMapping,
+				},
+				client:           ts.Client(),
+				staticDimensions: dimensions.NewNormalizedDimensionList(dimensions.NewDimension("dt.metrics.source", "opentelemetry")),
+			}
+
+			err := e.PushMetricsData(context.Background(), md)
+			assert.NoError(t, err)
+			// merged dimensions are not ordered, compare the metric key and dimensions separately
+			wantKey, wantDims := splitMetricLine(tt.wantLine)
+			sentKey, sentDims := splitMetricLine(sent)
+			assert.Equal(t, wantKey, sentKey)
+			assert.ElementsMatch(t, wantDims, sentDims)
+		})
+	}
+}
+
+func Test_exporter_serializeMetrics_AutoEntityMapping_cumulative(t *testing.T) {
+	batch := func(valueA, valueB int64, minute int) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		for _, host := range []struct {
+			id    string
+			value int64
+		}{{"HOST-A", valueA}, {"HOST-B", valueB}} {
+			rm := md.ResourceMetrics().AppendEmpty()
+			rm.Resource().Attributes().PutStr("dt.entity.host", host.id)
+			metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+			metric.SetName("requests")
+			sum := metric.SetEmptySum()
+			sum.SetIsMonotonic(true)
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetIntValue(host.value)
+			dp.SetTimestamp(pcommon.Timestamp(time.Date(2021, 07, 16, 12, minute, 0, 0, time.UTC).UnixNano()))
+		}
+		return md
+	}
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg:      &config.Config{AutoEntityMapping: true},
+		prevPts:  ttlmap.New(cSweepIntervalSeconds, cMaxAgeSeconds),
+	}
+	lines, err := e.serializeMetrics(batch(10, 1000, 30))
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+
+	lines, err = e.serializeMetrics(batch(15, 1020, 31))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"requests,dt.entity.host=HOST-A count,delta=5 1626438660000",
+		"requests,dt.entity.host=HOST-B count,delta=20 1626438660000",
+	}, lines)
+}
+
+// splitMetricLine splits a metric line into the line without dimensions and its dimensions
+func splitMetricLine(line string) (string, []string) {
+	keyAndDims, rest, _ := strings.Cut(line, " ")
+	parts := strings.Split(keyAndDims, ",")
+	return parts[0] + " " + rest, parts[1:]
+}
+
+func Test_exporter_PushMetricsData_MaxDimensionValueLength(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("int_gauge")
+	dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(10)
+	dataPoint.SetTimestamp(testTimestamp)
+	dataPoint.Attributes().PutStr("long", strings.Repeat("a", 20))
+	dataPoint.Attributes().PutStr("short", "abc")
+
+	var sent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		sent = string(bodyBytes)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings:      confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			MaxDimensionValueLength: 10,
+		},
+		client:  ts.Client(),
+		metrics: newTestMetrics(t),
+	}
+
+	err := e.PushMetricsData(context.Background(), md)
+	assert.NoError(t, err)
+	key, dims := splitMetricLine(sent)
+	assert.Equal(t, "int_gauge gauge,10 1626438600000", key)
+	assert.ElementsMatch(t, []string{"long=aaaaaaaaaa", "short=abc"}, dims)
+	validateMetric(t, e.metrics.views.truncatedDimensions, 1)
+}
+
+func Test_exporter_PushMetricsData_MaxDimensions(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("int_gauge")
+	dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(10)
+	dataPoint.SetTimestamp(testTimestamp)
+	for _, key := range []string{"a", "b", "c", "d"} {
+		dataPoint.Attributes().PutStr(key, "value")
+	}
+
+	var sent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		sent = string(bodyBytes)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			MaxDimensions:      4,
+		},
+		defaultDimensions: dimensions.NewNormalizedDimensionList(dimensions.NewDimension("default", "value")),
+		client:            ts.Client(),
+		metrics:           newTestMetrics(t),
+	}
+
+	err := e.PushMetricsData(context.Background(), md)
+	assert.NoError(t, err)
+	key, dims := splitMetricLine(sent)
+	assert.Equal(t, "int_gauge gauge,10 1626438600000", key)
+	// the default dimension is kept and the last attribute is dropped
+	assert.ElementsMatch(t, []string{"default=value", "a=value", "b=value", "c=value"}, dims)
+	validateMetric(t, e.metrics.views.cappedDimensions, 1)
+}
+
+func Test_exporter_PushMetricsData_SendExemplarTraceIDs(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metric := metrics.AppendEmpty()
+	metric.SetName("with_exemplar")
+	dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(10)
+	dataPoint.SetTimestamp(testTimestamp)
+	exemplar := dataPoint.Exemplars().AppendEmpty()
+	exemplar.SetIntValue(10)
+	exemplar.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	metric = metrics.AppendEmpty()
+	metric.SetName("without_exemplar")
+	dataPoint = metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dataPoint.SetIntValue(10)
+	dataPoint.SetTimestamp(testTimestamp)
+
+	var sent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		sent = string(bodyBytes)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings:   confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			SendExemplarTraceIDs: true,
+		},
+		client:  ts.Client(),
+		metrics: newTestMetrics(t),
+	}
+
+	err := e.PushMetricsData(context.Background(), md)
+	assert.NoError(t, err)
+	assert.Equal(t, "with_exemplar,dt.trace_id=0102030405060708090a0b0c0d0e0f10 gauge,10 1626438600000\nwithout_exemplar gauge,10 1626438600000", sent)
+}
+
+func Test_exporter_PushMetricsData_ExponentialHistogram(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("exp_hist")
+	hist := metric.SetEmptyExponentialHistogram()
+	hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dataPoint := hist.DataPoints().AppendEmpty()
+	dataPoint.SetTimestamp(testTimestamp)
+	dataPoint.SetCount(3)
+	dataPoint.SetSum(30)
+	dataPoint.Positive().SetOffset(3)
+	dataPoint.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	var sent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		sent = string(bodyBytes)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		},
+		client:  ts.Client(),
+		metrics: newTestMetrics(t),
+	}
+
+	err := e.PushMetricsData(context.Background(), md)
+	assert.NoError(t, err)
+	assert.Equal(t, "exp_hist gauge,min=8,max=32,sum=30,count=3 1626438600000", sent)
+	validateMetric(t, e.metrics.views.estimatedHistograms, 1)
+}
+
+func Test_exporter_PushMetricsData_Compression(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for i, name := range []string{"gauge_a", "gauge_b"} {
+		metric := metrics.AppendEmpty()
+		metric.SetName(name)
+		dataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+		dataPoint.SetIntValue(int64(i))
+		dataPoint.SetTimestamp(testTimestamp)
+	}
+
+	push := func(t *testing.T, compression configcompression.CompressionType) (string, string) {
+		var sent, encoding string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding = r.Header.Get("Content-Encoding")
+			body := io.Reader(r.Body)
+			if encoding == "gzip" {
+				gz, err := gzip.NewReader(r.Body)
+				require.NoError(t, err)
+				body = gz
+			}
+			bodyBytes, err := io.ReadAll(body)
+			require.NoError(t, err)
+			sent = string(bodyBytes)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer ts.Close()
+
+		cfg := &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL, Compression: compression},
+			APIToken:           "token",
+		}
+		require.NoError(t, cfg.Validate())
+
+		e, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+		require.NoError(t, err)
+		require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+
+		require.NoError(t, e.PushMetricsData(context.Background(), md))
+		require.NoError(t, e.shutdown(context.Background()))
+		return sent, encoding
+	}
+
+	uncompressed, encoding := push(t, config.CompressionNone)
+	assert.Empty(t, encoding)
+	assert.Equal(t, "gauge_a,dt.metrics.source=opentelemetry gauge,0 1626438600000\ngauge_b,dt.metrics.source=opentelemetry gauge,1 1626438600000", uncompressed)
+
+	compressed, encoding := push(t, config.CompressionGzip)
+	assert.Equal(t, "gzip", encoding)
+	assert.Equal(t, uncompressed, compressed)
+}
+
 func Test_exporter_PushMetricsData_isDisabled(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Fatal("Server should not be called")
@@ -449,8 +846,8 @@ func Test_exporter_send_BadRequest(t *testing.T) {
 		client: ts.Client(),
 	}
 	err := e.send(context.Background(), []string{""})
-	if consumererror.IsPermanent(err) {
-		t.Errorf("Expected error to not be permanent %v", err)
+	if !consumererror.IsPermanent(err) {
+		t.Errorf("Expected error to be permanent %v", err)
 		return
 	}
 	if e.isDisabled {
@@ -484,6 +881,75 @@ func Test_exporter_send_Unauthorized(t *testing.T) {
 	}
 }
 
+func Test_exporter_send_UnauthorizedTokenFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte{})
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("revoked-token"), 0600))
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		},
+		client: ts.Client(),
+		token:  newFileToken(path, time.Minute),
+	}
+	err := e.send(context.Background(), []string{""})
+	assert.True(t, consumererror.IsPermanent(err), "Expected error to be permanent %v", err)
+	// the token may be rotated in the file
+	assert.False(t, e.isDisabled, "Expected exporter to not be disabled")
+}
+
+func Test_exporter_send_TokenFileRotation(t *testing.T) {
+	var authorization []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first-token\n"), 0600))
+	cfg := &config.Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		APITokenFile:       path,
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, config.DefaultTokenRefreshInterval, cfg.TokenRefreshInterval)
+	assert.NotContains(t, cfg.Headers, "Authorization")
+
+	e, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, e.shutdown(context.Background()))
+	}()
+	now := time.Now()
+	e.token.now = func() time.Time { return now }
+
+	require.NoError(t, e.send(context.Background(), []string{"metric 1"}))
+
+	require.NoError(t, os.WriteFile(path, []byte("second-token"), 0600))
+	now = now.Add(config.DefaultTokenRefreshInterval)
+	require.NoError(t, e.send(context.Background(), []string{"metric 1"}))
+
+	assert.Equal(t, []string{"Api-Token first-token", "Api-Token second-token"}, authorization)
+}
+
+func Test_exporter_start_MissingTokenFile(t *testing.T) {
+	cfg := &config.Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "http://example.com"},
+		APITokenFile:       filepath.Join(t.TempDir(), "token"),
+	}
+	e, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.Error(t, e.start(context.Background(), componenttest.NewNopHost()))
+}
+
 func Test_exporter_send_TooLarge(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusRequestEntityTooLarge)
@@ -537,6 +1003,113 @@ func Test_exporter_send_NotFound(t *testing.T) {
 	}
 }
 
+// resetConnection closes the connection of a request without responding
+func resetConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	require.NoError(t, err)
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		// a zero linger makes closing the connection send a TCP reset
+		assert.NoError(t, tcpConn.SetLinger(0))
+	}
+	assert.NoError(t, conn.Close())
+}
+
+func Test_exporter_send_Throttled(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{
+			name:       "seconds",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "30",
+			expected:   30 * time.Second,
+		},
+		{
+			name:       "http date",
+			status:     http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat),
+			expected:   2 * time.Minute,
+		},
+		{
+			name:     "missing",
+			status:   http.StatusTooManyRequests,
+			expected: 0,
+		},
+	}
+	throttle := regexp.MustCompile(`^Throttle \((.+)\), error: `)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			e := &exporter{
+				settings: componenttest.NewNopTelemetrySettings(),
+				cfg: &config.Config{
+					APIToken:           "token",
+					HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+				},
+				client: ts.Client(),
+			}
+			err := e.send(context.Background(), []string{"metric 1"})
+			require.Error(t, err)
+			assert.False(t, consumererror.IsPermanent(err))
+
+			match := throttle.FindStringSubmatch(err.Error())
+			require.Len(t, match, 2, err.Error())
+			delay, err := time.ParseDuration(match[1])
+			require.NoError(t, err)
+			// the HTTP date has a resolution of a second and is compared to the time of the response
+			assert.InDelta(t, tt.expected, delay, float64(time.Second))
+		})
+	}
+}
+
+func Test_retryAfter(t *testing.T) {
+	now := time.Date(2022, 11, 7, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{value: "120", expected: 2 * time.Minute},
+		{value: " 5 ", expected: 5 * time.Second},
+		{value: "Mon, 07 Nov 2022 12:01:30 GMT", expected: 90 * time.Second},
+		{value: "Mon, 07 Nov 2022 11:59:00 GMT", expected: 0},
+		{value: "-1", expected: 0},
+		{value: "soon", expected: 0},
+		{value: "", expected: 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retryAfter(tt.value, now), tt.value)
+	}
+}
+
+func Test_exporter_send_ConnectionReset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resetConnection(t, w)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		},
+		client: ts.Client(),
+	}
+	err := e.send(context.Background(), []string{""})
+	require.Error(t, err)
+	assert.True(t, isConnectionReset(err), "Expected a connection reset %v", err)
+	assert.False(t, consumererror.IsPermanent(err), "Expected error to not be permanent %v", err)
+}
+
 func Test_exporter_send_chunking(t *testing.T) {
 	sentChunks := 0
 
@@ -569,8 +1142,8 @@ func Test_exporter_send_chunking(t *testing.T) {
 	if sentChunks != 2 {
 		t.Errorf("Expected batch to be sent in 2 chunks")
 	}
-	if consumererror.IsPermanent(err) {
-		t.Errorf("Expected error to not be permanent %v", err)
+	if !consumererror.IsPermanent(err) {
+		t.Errorf("Expected error to be permanent %v", err)
 		return
 	}
 	if e.isDisabled {
@@ -579,6 +1152,211 @@ func Test_exporter_send_chunking(t *testing.T) {
 	}
 }
 
+func Test_exporter_send_maxRequestBytes(t *testing.T) {
+	const maxRequestBytes = 1000
+	var bodySizes []int
+	receivedLines := 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodySizes = append(bodySizes, len(body))
+		receivedLines += len(strings.Split(string(body), "\n"))
+		response, _ := json.Marshal(metricsResponse{Ok: 1})
+		_, _ = w.Write(response)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			MaxRequestBytes:    maxRequestBytes,
+		},
+		client: ts.Client(),
+	}
+
+	batch := make([]string, 100)
+	for i := range batch {
+		batch[i] = fmt.Sprintf("metric.%d,dim=%s gauge,1", i, strings.Repeat("x", 80+i))
+	}
+
+	require.NoError(t, e.send(context.Background(), batch))
+	assert.Greater(t, len(bodySizes), 1)
+	for _, size := range bodySizes {
+		assert.LessOrEqual(t, size, maxRequestBytes)
+	}
+	assert.Equal(t, len(batch), receivedLines)
+}
+
+func Test_exporter_send_lineLargerThanMaxRequestBytes(t *testing.T) {
+	var bodies []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		response, _ := json.Marshal(metricsResponse{Ok: 1})
+		_, _ = w.Write(response)
+	}))
+	defer ts.Close()
+
+	metrics, err := newOpenCensusMetrics(t.Name())
+	require.NoError(t, err)
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			MaxRequestBytes:    20,
+		},
+		client:  ts.Client(),
+		metrics: metrics,
+	}
+
+	require.NoError(t, e.send(context.Background(), []string{"short,a=b gauge,1", strings.Repeat("x", 21), "other gauge,2"}))
+	assert.Equal(t, []string{"short,a=b gauge,1", "other gauge,2"}, bodies)
+}
+
+func Test_exporter_enqueue_sendsFullBatches(t *testing.T) {
+	var sentLines []int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sentLines = append(sentLines, len(strings.Split(string(body), "\n")))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			FlushInterval:      time.Hour,
+		},
+		client: ts.Client(),
+	}
+
+	batch := make([]string, 1001)
+	for i := 0; i < 1001; i++ {
+		batch[i] = fmt.Sprintf("%d", i)
+	}
+
+	// a push reaching the lines limit is sent right away, without waiting for the flush interval
+	require.NoError(t, e.enqueue(context.Background(), batch))
+	assert.Equal(t, []int{1000, 1}, sentLines)
+	assert.Nil(t, e.pending)
+}
+
+func Test_exporter_enqueue_waitsForFlush(t *testing.T) {
+	var bodies []string
+	status := http.StatusAccepted
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			FlushInterval:      time.Hour,
+		},
+		client: ts.Client(),
+	}
+
+	// enqueue waits for the pending lines to be flushed and returns the result of the flush
+	push := func(lines ...string) <-chan error {
+		result := make(chan error, 1)
+		go func() {
+			result <- e.enqueue(context.Background(), lines)
+		}()
+		return result
+	}
+	waitPending := func(lines int) {
+		require.Eventually(t, func() bool {
+			e.pendingMu.Lock()
+			defer e.pendingMu.Unlock()
+			return e.pending != nil && e.pending.lines == lines
+		}, 5*time.Second, time.Millisecond)
+	}
+
+	first, second := push("a"), push("b")
+	waitPending(2)
+	require.NoError(t, e.flush(context.Background()))
+	assert.NoError(t, <-first)
+	assert.NoError(t, <-second)
+	require.Len(t, bodies, 1)
+	assert.ElementsMatch(t, []string{"a", "b"}, strings.Split(bodies[0], "\n"))
+
+	// a failed flush is returned to the pushes so that exporterhelper retries their lines
+	status = http.StatusServiceUnavailable
+	failed := push("c")
+	waitPending(1)
+	assert.Error(t, e.flush(context.Background()))
+	assert.Error(t, <-failed)
+}
+
+func Test_exporter_enqueue_cancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("Server should not be called")
+	}))
+	defer ts.Close()
+
+	e := &exporter{
+		settings: componenttest.NewNopTelemetrySettings(),
+		cfg: &config.Config{
+			HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+			FlushInterval:      time.Hour,
+		},
+		client: ts.Client(),
+	}
+
+	// the lines of a push cancelled before the flush are withdrawn, they are sent by the retry only
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, e.enqueue(ctx, []string{"a"}), context.DeadlineExceeded)
+	assert.NoError(t, e.flush(context.Background()))
+}
+
+func Test_exporter_PushMetricsData_FlushInterval(t *testing.T) {
+	received := make(chan string, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("gauge_int_with_dims")
+	intGaugeDataPoint := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	intGaugeDataPoint.SetIntValue(10)
+	intGaugeDataPoint.SetTimestamp(testTimestamp)
+
+	cfg := &config.Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+		FlushInterval:      50 * time.Millisecond,
+	}
+	e, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	defer unregisterMetrics(e.metrics)
+	require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, e.shutdown(context.Background())) }()
+
+	require.NoError(t, e.PushMetricsData(context.Background(), md))
+
+	select {
+	case body := <-received:
+		assert.Equal(t, "gauge_int_with_dims,dt.metrics.source=opentelemetry gauge,10 1626438600000", body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("partial batch was not flushed")
+	}
+}
+
 func Test_exporter_PushMetricsData_Error(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
@@ -660,9 +1438,10 @@ func Test_exporter_start_InvalidHTTPClientSettings(t *testing.T) {
 		},
 	}
 
-	exp := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	exp, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
 
-	err := exp.start(context.Background(), componenttest.NewNopHost())
+	err = exp.start(context.Background(), componenttest.NewNopHost())
 	if err == nil {
 		t.Errorf("Expected error when creating a metrics exporter with invalid HTTP Client Settings")
 		return
@@ -674,7 +1453,8 @@ func Test_exporter_new_with_tags(t *testing.T) {
 		DefaultDimensions: map[string]string{"test_tag": "value"},
 	}
 
-	exp := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	exp, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
 
 	assert.Equal(t, dimensions.NewNormalizedDimensionList(dimensions.NewDimension("test_tag", "value")), exp.defaultDimensions)
 }
@@ -684,7 +1464,8 @@ func Test_exporter_new_with_default_dimensions(t *testing.T) {
 		DefaultDimensions: map[string]string{"test_dimension": "value"},
 	}
 
-	exp := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	exp, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
 
 	assert.Equal(t, dimensions.NewNormalizedDimensionList(dimensions.NewDimension("test_dimension", "value")), exp.defaultDimensions)
 }
@@ -695,7 +1476,8 @@ func Test_exporter_new_with_default_dimensions_override_tag(t *testing.T) {
 		DefaultDimensions: map[string]string{"from": "default_dimensions"},
 	}
 
-	exp := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	exp, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
 
 	assert.Equal(t, dimensions.NewNormalizedDimensionList(dimensions.NewDimension("from", "default_dimensions")), exp.defaultDimensions)
 }
@@ -723,7 +1505,10 @@ func Test_LineTooLong(t *testing.T) {
 	intGaugeDataPoint := intGaugeDataPoints.AppendEmpty()
 	intGaugeDataPoint.SetIntValue(10)
 	intGaugeDataPoint.SetTimestamp(testTimestamp)
-	exp := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), &config.Config{DefaultDimensions: dims})
+	exp, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), &config.Config{DefaultDimensions: dims})
+	require.NoError(t, err)
 
-	assert.Empty(t, exp.serializeMetrics(md))
+	lines, err := exp.serializeMetrics(md)
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
 }
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// exporterKey used to identify exporters in metrics and traces.
+	exporterKey = "exporter"
+	// metricPrefix used to prefix dynatrace specific metrics
+	metricPrefix = "dynatraceexporter"
+	nameSep      = "/"
+)
+
+type opencensusMetrics struct {
+	stats struct {
+		droppedMetrics      *stats.Int64Measure
+		truncatedDimensions *stats.Int64Measure
+		cappedDimensions    *stats.Int64Measure
+		queueDropped        *stats.Int64Measure
+		estimatedHistograms *stats.Int64Measure
+	}
+	views struct {
+		droppedMetrics      *view.View
+		truncatedDimensions *view.View
+		cappedDimensions    *view.View
+		queueDropped        *view.View
+		estimatedHistograms *view.View
+	}
+}
+
+// newOpenCensusMetrics registers the internal telemetry views of an exporter instance
+func newOpenCensusMetrics(instanceName string) (*opencensusMetrics, error) {
+	m := &opencensusMetrics{}
+	prefix := metricPrefix + nameSep
+	if instanceName != "" {
+		prefix += instanceName + nameSep
+	}
+
+	m.stats.droppedMetrics = stats.Int64(prefix+"dropped_metrics", "Number of metric data points dropped before being sent to Dynatrace", stats.UnitDimensionless)
+
+	m.stats.truncatedDimensions = stats.Int64(prefix+"truncated_dimensions", "Number of dimension values truncated to the maximum dimension value length", stats.UnitDimensionless)
+
+	m.stats.cappedDimensions = stats.Int64(prefix+"capped_dimensions", "Number of metric lines whose dimensions were dropped down to the maximum number of dimensions", stats.UnitDimensionless)
+
+	m.stats.queueDropped = stats.Int64(prefix+"queue_dropped", "Number of metric data points or spans dropped because the sending queue was full", stats.UnitDimensionless)
+
+	m.stats.estimatedHistograms = stats.Int64(prefix+"estimated_histograms", "Number of exponential histogram data points whose min, max or sum was estimated from their buckets", stats.UnitDimensionless)
+
+	m.views.droppedMetrics = fromMeasure(m.stats.droppedMetrics, view.Sum())
+	m.views.truncatedDimensions = fromMeasure(m.stats.truncatedDimensions, view.Sum())
+	m.views.cappedDimensions = fromMeasure(m.stats.cappedDimensions, view.Sum())
+	m.views.queueDropped = fromMeasure(m.stats.queueDropped, view.Sum())
+	m.views.estimatedHistograms = fromMeasure(m.stats.estimatedHistograms, view.Sum())
+
+	err := view.Register(
+		m.views.droppedMetrics,
+		m.views.truncatedDimensions,
+		m.views.cappedDimensions,
+		m.views.queueDropped,
+		m.views.estimatedHistograms,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromMeasure(measure stats.Measure, agg *view.Aggregation) *view.View {
+	return &view.View{
+		Name:        buildExporterCustomMetricName(measure.Name()),
+		Description: measure.Description(),
+		Measure:     measure,
+		Aggregation: agg,
+	}
+}
+
+func buildExporterCustomMetricName(metric string) string {
+	return exporterKey + nameSep + typeStr + nameSep + metric
+}
+
+// recordDroppedMetrics increments the metric that records the number of dropped metric data points
+func (m *opencensusMetrics) recordDroppedMetrics(count int) {
+	stats.Record(context.Background(), m.stats.droppedMetrics.M(int64(count)))
+}
+
+// recordTruncatedDimensions increments the metric that records the number of truncated dimension values
+func (m *opencensusMetrics) recordTruncatedDimensions(count int) {
+	stats.Record(context.Background(), m.stats.truncatedDimensions.M(int64(count)))
+}
+
+// recordCappedDimensions increments the metric that records the number of metric lines whose dimensions were capped
+func (m *opencensusMetrics) recordCappedDimensions(count int) {
+	stats.Record(context.Background(), m.stats.cappedDimensions.M(int64(count)))
+}
+
+// recordQueueDropped increments the metric that records the number of metric data points or spans rejected by the sending queue
+func (m *opencensusMetrics) recordQueueDropped(count int) {
+	stats.Record(context.Background(), m.stats.queueDropped.M(int64(count)))
+}
+
+// recordEstimatedHistograms increments the metric that records the number of histogram data points with an estimated summary
+func (m *opencensusMetrics) recordEstimatedHistograms(count int) {
+	stats.Record(context.Background(), m.stats.estimatedHistograms.M(int64(count)))
+}
+
+// sendingQueueIsFull is the message of the error returned by exporterhelper when the sending queue rejects a request
+const sendingQueueIsFull = "sending_queue is full"
+
+func isSendingQueueFull(err error) bool {
+	return err != nil && err.Error() == sendingQueueIsFull
+}
+
+// queueDropMetricsExporter records the metric data points rejected by the sending queue of the wrapped exporter
+type queueDropMetricsExporter struct {
+	component.MetricsExporter
+	metrics *opencensusMetrics
+}
+
+func (e *queueDropMetricsExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	// count before consuming, the data points cannot be counted once they are handed over to the queue
+	count := md.DataPointCount()
+	err := e.MetricsExporter.ConsumeMetrics(ctx, md)
+	if isSendingQueueFull(err) {
+		e.metrics.recordQueueDropped(count)
+	}
+	return err
+}
+
+// queueDropTracesExporter records the spans rejected by the sending queue of the wrapped exporter
+type queueDropTracesExporter struct {
+	component.TracesExporter
+	metrics *opencensusMetrics
+}
+
+func (e *queueDropTracesExporter) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	count := td.SpanCount()
+	err := e.TracesExporter.ConsumeTraces(ctx, td)
+	if isSendingQueueFull(err) {
+		e.metrics.recordQueueDropped(count)
+	}
+	return err
+}
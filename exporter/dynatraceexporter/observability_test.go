@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func TestRecordMetrics(t *testing.T) {
+	metrics := newTestMetrics(t)
+	testCases := []struct {
+		fn       func()        // function to test updating metrics
+		v        *view.View    // view to reference
+		m        stats.Measure // expected measure of the view
+		calls    int           // number of times to call fn
+		expected int           // expected value of reported metric at end of calls
+	}{
+		{func() { metrics.recordDroppedMetrics(2) }, metrics.views.droppedMetrics, metrics.stats.droppedMetrics, 3, 6},
+		{func() { metrics.recordTruncatedDimensions(1) }, metrics.views.truncatedDimensions, metrics.stats.truncatedDimensions, 3, 3},
+		{func() { metrics.recordCappedDimensions(1) }, metrics.views.cappedDimensions, metrics.stats.cappedDimensions, 3, 3},
+		{func() { metrics.recordQueueDropped(4) }, metrics.views.queueDropped, metrics.stats.queueDropped, 3, 12},
+		{func() { metrics.recordEstimatedHistograms(1) }, metrics.views.estimatedHistograms, metrics.stats.estimatedHistograms, 3, 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.m.Name(), func(t *testing.T) {
+			for i := 0; i < tc.calls; i++ {
+				tc.fn()
+			}
+			validateMetric(t, tc.v, tc.expected)
+		})
+	}
+}
+
+func validateMetric(t *testing.T, v *view.View, expected interface{}) {
+	// hack to reset stats to 0
+	defer func() {
+		view.Unregister(v)
+		err := view.Register(v)
+		assert.NoError(t, err)
+	}()
+	rows, err := view.RetrieveData(v.Name)
+	assert.NoError(t, err)
+	if expected != nil {
+		require.Len(t, rows, 1)
+		value := reflect.Indirect(reflect.ValueOf(rows[0].Data)).FieldByName("Value").Interface()
+		assert.EqualValues(t, expected, value)
+	} else {
+		assert.Len(t, rows, 0)
+	}
+}
+
+// TestRegisterViewsExpectingFailure validates that if an error is returned from view.Register, the metrics are not created
+func TestRegisterViewsExpectingFailure(t *testing.T) {
+	statName := "dynatraceexporter/" + t.Name() + "/dropped_metrics"
+	stat := stats.Int64(statName, "", stats.UnitDimensionless)
+	err := view.Register(&view.View{
+		Name:        buildExporterCustomMetricName(statName),
+		Description: "some description",
+		Measure:     stat,
+		Aggregation: view.Count(),
+	})
+	require.NoError(t, err)
+	metrics, err := newOpenCensusMetrics(t.Name())
+	assert.Error(t, err)
+	assert.Nil(t, metrics)
+}
+
+// newTestMetrics builds a new metrics that will cleanup when testing.T completes
+func newTestMetrics(t *testing.T) *opencensusMetrics {
+	m, err := newOpenCensusMetrics(t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		unregisterMetrics(m)
+	})
+	return m
+}
+
+// unregisterMetrics is used to unregister the metrics for testing purposes
+func unregisterMetrics(metrics *opencensusMetrics) {
+	view.Unregister(
+		metrics.views.droppedMetrics,
+		metrics.views.truncatedDimensions,
+		metrics.views.cappedDimensions,
+		metrics.views.queueDropped,
+		metrics.views.estimatedHistograms,
+	)
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileToken provides the API token held by a file, which is read again once the refresh interval elapsed
+// so that the token can be rotated without restarting the collector.
+type fileToken struct {
+	path     string
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	readAt time.Time
+}
+
+func newFileToken(path string, interval time.Duration) *fileToken {
+	return &fileToken{path: path, interval: interval, now: time.Now}
+}
+
+// get returns the token, reading the file again if the refresh interval elapsed since it was last read.
+// If the file cannot be read again, the previous token is returned along with the error.
+func (t *fileToken) get() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.token != "" && now.Sub(t.readAt) < t.interval {
+		return t.token, nil
+	}
+	content, err := os.ReadFile(t.path)
+	if err != nil {
+		return t.token, fmt.Errorf("failed to read api_token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return t.token, errors.New("api_token_file is empty")
+	}
+	t.token = token
+	t.readAt = now
+	return t.token, nil
+}
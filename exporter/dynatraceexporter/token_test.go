@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileToken_get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0600))
+
+	now := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	token := newFileToken(path, time.Minute)
+	token.now = func() time.Time { return now }
+
+	got, err := token.get()
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	// the file is not read again before the refresh interval elapsed
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0600))
+	now = now.Add(30 * time.Second)
+	got, err = token.get()
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	now = now.Add(30 * time.Second)
+	got, err = token.get()
+	require.NoError(t, err)
+	assert.Equal(t, "second", got)
+
+	// the previous token is kept if the file cannot be read again
+	require.NoError(t, os.Remove(path))
+	now = now.Add(time.Minute)
+	got, err = token.get()
+	assert.Error(t, err)
+	assert.Equal(t, "second", got)
+}
+
+func Test_fileToken_get_empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(" \n"), 0600))
+
+	got, err := newFileToken(path, time.Minute).get()
+	assert.EqualError(t, err, "api_token_file is empty")
+	assert.Empty(t, got)
+}
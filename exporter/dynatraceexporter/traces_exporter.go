@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
+)
+
+const (
+	// maxEventsPerRequest is the maximum number of span events sent in a single request
+	maxEventsPerRequest = 500
+
+	spanEventType     = "opentelemetry.span"
+	spanEventProvider = "opentelemetry-collector"
+)
+
+// newTracesExporter exports spans as events to a Dynatrace business events ingest API
+func newTracesExporter(params component.ExporterCreateSettings, cfg *config.Config) *tracesExporter {
+	return &tracesExporter{
+		settings:            params.TelemetrySettings,
+		cfg:                 cfg,
+		maxEventsPerRequest: maxEventsPerRequest,
+	}
+}
+
+// tracesExporter forwards spans as events to Dynatrace
+type tracesExporter struct {
+	settings component.TelemetrySettings
+	cfg      *config.Config
+	client   *http.Client
+
+	maxEventsPerRequest int
+}
+
+func (e *tracesExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
+	events := spansToEvents(td)
+	if len(events) == 0 {
+		return nil
+	}
+
+	for i := 0; i < len(events); i += e.maxEventsPerRequest {
+		end := i + e.maxEventsPerRequest
+		if end > len(events) {
+			end = len(events)
+		}
+
+		if err := e.sendEvents(ctx, events[i:end]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// spansToEvents maps every span to a flat event holding the resource and span attributes
+// next to the span fields. Span fields take precedence over attributes of the same name.
+func spansToEvents(td ptrace.Traces) []map[string]interface{} {
+	var events []map[string]interface{}
+
+	resourceSpans := td.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		resourceSpan := resourceSpans.At(i)
+		scopeSpans := resourceSpan.ScopeSpans()
+		for j := 0; j < scopeSpans.Len(); j++ {
+			spans := scopeSpans.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				events = append(events, spanToEvent(resourceSpan.Resource(), spans.At(k)))
+			}
+		}
+	}
+
+	return events
+}
+
+func spanToEvent(resource pcommon.Resource, span ptrace.Span) map[string]interface{} {
+	event := resource.Attributes().AsRaw()
+	span.Attributes().Range(func(k string, v pcommon.Value) bool {
+		event[k] = v.AsRaw()
+		return true
+	})
+
+	event["event.type"] = spanEventType
+	event["event.provider"] = spanEventProvider
+	event["timestamp"] = span.StartTimestamp().AsTime().UTC().Format(time.RFC3339Nano)
+	event["trace.id"] = span.TraceID().HexString()
+	event["span.id"] = span.SpanID().HexString()
+	if !span.ParentSpanID().IsEmpty() {
+		event["span.parent_id"] = span.ParentSpanID().HexString()
+	}
+	event["span.name"] = span.Name()
+	event["span.kind"] = span.Kind().String()
+	event["span.duration_ms"] = float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Millisecond)
+	event["span.status_code"] = span.Status().Code().String()
+	if msg := span.Status().Message(); msg != "" {
+		event["span.status_message"] = msg
+	}
+
+	return event
+}
+
+// sendEvents sends a batch of events to Dynatrace as a JSON array.
+func (e *tracesExporter) sendEvents(ctx context.Context, events []map[string]interface{}) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	e.settings.Logger.Debug(
+		"sending a batch of span events",
+		zap.Int("events", len(events)),
+		zap.String("endpoint", e.cfg.EventsEndpoint),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.cfg.EventsEndpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return sendError(e.settings.Logger, "sendEvents", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest:
+		// the events are invalid, resending them will not help
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return consumererror.NewPermanent(fmt.Errorf("events rejected by Dynatrace: %s", truncateString(string(bodyBytes), 1000)))
+	case resp.StatusCode == http.StatusRequestEntityTooLarge:
+		return consumererror.NewPermanent(fmt.Errorf("payload too large"))
+	case resp.StatusCode == http.StatusUnauthorized:
+		return consumererror.NewPermanent(fmt.Errorf("events API token missing or invalid"))
+	case resp.StatusCode == http.StatusForbidden:
+		return consumererror.NewPermanent(fmt.Errorf("events API token missing the required scope (bizevents.ingest)"))
+	case resp.StatusCode == http.StatusNotFound:
+		return consumererror.NewPermanent(fmt.Errorf("events ingest API not found - ensure events_endpoint is correct"))
+	default:
+		return fmt.Errorf("events ingest failed with status %s", resp.Status)
+	}
+}
+
+// start creates the HTTP client from the exporter HTTP settings, authenticated with the events API token
+func (e *tracesExporter) start(_ context.Context, host component.Host) error {
+	clientSettings := e.cfg.HTTPClientSettings
+	clientSettings.Endpoint = e.cfg.EventsEndpoint
+	// compression only applies to the metrics ingest requests
+	clientSettings.Compression = config.CompressionNone
+	clientSettings.Headers = make(map[string]string, len(e.cfg.Headers)+2)
+	for k, v := range e.cfg.Headers {
+		clientSettings.Headers[k] = v
+	}
+	clientSettings.Headers["Authorization"] = fmt.Sprintf("Api-Token %s", e.cfg.EventsAPIToken)
+	clientSettings.Headers["Content-Type"] = "application/json; charset=utf-8"
+
+	client, err := clientSettings.ToClient(host, e.settings)
+	if err != nil {
+		e.settings.Logger.Error("Failed to construct HTTP client", zap.Error(err))
+		return fmt.Errorf("start: %w", err)
+	}
+
+	e.client = client
+
+	return nil
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynatraceexporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/dynatraceexporter/config"
+)
+
+func newTestTracesExporter(t *testing.T, endpoint string) *tracesExporter {
+	cfg := createDefaultConfig().(*config.Config)
+	cfg.SendTracesAsEvents = true
+	cfg.EventsEndpoint = endpoint
+	cfg.EventsAPIToken = "events-token"
+	require.NoError(t, cfg.Validate())
+
+	e := newTracesExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+	return e
+}
+
+func Test_tracesExporter_pushTraces(t *testing.T) {
+	var header http.Header
+	var events []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &events))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	rs.Resource().Attributes().PutStr("span.name", "overridden")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("GET /cart")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetStartTimestamp(testTimestamp)
+	span.SetEndTimestamp(testTimestamp + pcommon.Timestamp(1500*time.Microsecond))
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Status().SetMessage("not found")
+	span.Attributes().PutInt("http.status_code", 404)
+
+	e := newTestTracesExporter(t, ts.URL)
+	require.NoError(t, e.pushTraces(context.Background(), td))
+
+	assert.Equal(t, "Api-Token events-token", header.Get("Authorization"))
+	assert.Equal(t, "application/json; charset=utf-8", header.Get("Content-Type"))
+	assert.Equal(t, []map[string]interface{}{{
+		"event.type":          "opentelemetry.span",
+		"event.provider":      "opentelemetry-collector",
+		"timestamp":           "2021-07-16T12:30:00Z",
+		"trace.id":            "0102030405060708090a0b0c0d0e0f10",
+		"span.id":             "0102030405060708",
+		"span.parent_id":      "0807060504030201",
+		"span.name":           "GET /cart",
+		"span.kind":           "SPAN_KIND_SERVER",
+		"span.duration_ms":    1.5,
+		"span.status_code":    "STATUS_CODE_ERROR",
+		"span.status_message": "not found",
+		"service.name":        "checkout",
+		"http.status_code":    float64(404),
+	}}, events)
+}
+
+func Test_tracesExporter_pushTraces_batching(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []map[string]interface{}
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &events))
+		mu.Lock()
+		batchSizes = append(batchSizes, len(events))
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 5; i++ {
+		spans.AppendEmpty().SetName("span")
+	}
+
+	e := newTestTracesExporter(t, ts.URL)
+	e.maxEventsPerRequest = 2
+	require.NoError(t, e.pushTraces(context.Background(), td))
+
+	assert.Equal(t, []int{2, 2, 1}, batchSizes)
+}
+
+func Test_tracesExporter_pushTraces_Empty(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Server should not be called")
+	}))
+	defer ts.Close()
+
+	e := newTestTracesExporter(t, ts.URL)
+	require.NoError(t, e.pushTraces(context.Background(), ptrace.NewTraces()))
+}
+
+func Test_tracesExporter_sendEvents_Errors(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{status: http.StatusBadRequest, permanent: true},
+		{status: http.StatusUnauthorized, permanent: true},
+		{status: http.StatusForbidden, permanent: true},
+		{status: http.StatusNotFound, permanent: true},
+		{status: http.StatusRequestEntityTooLarge, permanent: true},
+		{status: http.StatusServiceUnavailable, permanent: false},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer ts.Close()
+
+			e := newTestTracesExporter(t, ts.URL)
+			err := e.sendEvents(context.Background(), []map[string]interface{}{{"span.name": "span"}})
+			require.Error(t, err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
+
+func newRetryingTracesExporter(t *testing.T, endpoint string) component.TracesExporter {
+	cfg := createDefaultConfig().(*config.Config)
+	cfg.SendTracesAsEvents = true
+	cfg.EventsEndpoint = endpoint
+	cfg.EventsAPIToken = "events-token"
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.InitialInterval = 10 * time.Millisecond
+	cfg.RetrySettings.MaxInterval = 10 * time.Millisecond
+	cfg.RetrySettings.MaxElapsedTime = 5 * time.Second
+	require.NoError(t, cfg.Validate())
+
+	exp, err := createTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+	return exp
+}
+
+func Test_tracesExporter_retriesConnectionReset(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			resetConnection(t, w)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+	exp := newRetryingTracesExporter(t, ts.URL)
+	assert.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func Test_tracesExporter_doesNotRetryBadRequest(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+	exp := newRetryingTracesExporter(t, ts.URL)
+	err := exp.ConsumeTraces(context.Background(), td)
+	assert.True(t, consumererror.IsPermanent(err), "Expected error to be permanent %v", err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func Test_tracesExporter_queueOverflow(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	cfg := createDefaultConfig().(*config.Config)
+	cfg.SendTracesAsEvents = true
+	cfg.EventsEndpoint = ts.URL
+	cfg.EventsAPIToken = "events-token"
+	cfg.RetrySettings.Enabled = false
+	// the single consumer blocks on the first request, so that the queue overflows after a second one
+	cfg.QueueSettings.NumConsumers = 1
+	cfg.QueueSettings.QueueSize = 1
+	require.NoError(t, cfg.Validate())
+
+	exp, err := createTracesExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	rejected := 0
+	for i := 0; i < 5; i++ {
+		td := ptrace.NewTraces()
+		spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		spans.AppendEmpty().SetName("first")
+		spans.AppendEmpty().SetName("second")
+		if err := exp.ConsumeTraces(context.Background(), td); err != nil {
+			assert.True(t, isSendingQueueFull(err), "Expected the sending queue to be full %v", err)
+			rejected++
+		}
+	}
+	require.GreaterOrEqual(t, rejected, 3)
+	validateMetric(t, exp.(*queueDropTracesExporter).metrics.views.queueDropped, 2*rejected)
+}
@@ -17,6 +17,9 @@ package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collect
 import (
 	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 
 	"github.com/Shopify/sarama"
@@ -27,10 +30,72 @@ import (
 
 // Authentication defines authentication.
 type Authentication struct {
-	PlainText *PlainTextConfig            `mapstructure:"plain_text"`
-	SASL      *SASLConfig                 `mapstructure:"sasl"`
-	TLS       *configtls.TLSClientSetting `mapstructure:"tls"`
-	Kerberos  *KerberosConfig             `mapstructure:"kerberos"`
+	PlainText *PlainTextConfig `mapstructure:"plain_text"`
+	SASL      *SASLConfig      `mapstructure:"sasl"`
+	TLS       *TLSConfig       `mapstructure:"tls"`
+	Kerberos  *KerberosConfig  `mapstructure:"kerberos"`
+}
+
+// TLSConfig defines the TLS configuration. Next to the file paths of
+// configtls.TLSClientSetting, the CA, cert and key can be provided inline as PEM strings.
+type TLSConfig struct {
+	configtls.TLSClientSetting `mapstructure:",squash"`
+
+	// CA cert in PEM format, alternative to ca_file
+	CAPem string `mapstructure:"ca_pem"`
+	// Client cert in PEM format, alternative to cert_file
+	CertPem string `mapstructure:"cert_pem"`
+	// Client key in PEM format, alternative to key_file
+	KeyPem string `mapstructure:"key_pem" json:"-"`
+}
+
+// Validate checks that each of the CA, cert and key is provided either as a file path or inline, not both,
+// and that the minimum TLS version is at least 1.2.
+func (cfg TLSConfig) Validate() error {
+	switch cfg.MinVersion {
+	case "", "1.2", "1.3": // empty defaults to 1.2
+	default:
+		return fmt.Errorf("min_version should be '1.2' or '1.3'. configured value %v", cfg.MinVersion)
+	}
+	if cfg.CAFile != "" && cfg.CAPem != "" {
+		return errors.New("ca_file and ca_pem cannot both be set")
+	}
+	if cfg.CertFile != "" && cfg.CertPem != "" {
+		return errors.New("cert_file and cert_pem cannot both be set")
+	}
+	if cfg.KeyFile != "" && cfg.KeyPem != "" {
+		return errors.New("key_file and key_pem cannot both be set")
+	}
+	if (cfg.CertPem == "") != (cfg.KeyPem == "") {
+		return errors.New("cert_pem and key_pem have to be set together")
+	}
+	return nil
+}
+
+// LoadTLSConfig builds a tls.Config from the file based settings and the inline PEM strings.
+func (cfg TLSConfig) LoadTLSConfig() (*tls.Config, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := cfg.TLSClientSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CAPem != "" {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM([]byte(cfg.CAPem)) {
+			return nil, errors.New("failed to parse ca_pem")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if cfg.CertPem != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.CertPem), []byte(cfg.KeyPem))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load cert_pem and key_pem: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
 }
 
 // PlainTextConfig defines plaintext authentication.
@@ -51,6 +116,15 @@ type SASLConfig struct {
 	AWSMSK AWSMSKConfig `mapstructure:"aws_msk"`
 }
 
+// Validate checks that the SASL mechanism is supported.
+func (cfg SASLConfig) Validate() error {
+	switch cfg.Mechanism {
+	case "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		return nil
+	}
+	return fmt.Errorf(`invalid SASL Mechanism %q: can be either "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256" or "SCRAM-SHA-512"`, cfg.Mechanism)
+}
+
 // AWSMSKConfig defines the additional SASL authentication
 // measures needed to use AWS_MSK_IAM mechanism
 type AWSMSKConfig struct {
@@ -128,13 +202,13 @@ func configureSASL(config SASLConfig, saramaConfig *sarama.Config) error {
 		}
 		saramaConfig.Net.SASL.Mechanism = awsmsk.Mechanism
 	default:
-		return fmt.Errorf(`invalid SASL Mechanism %q: can be either "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256" or "SCRAM-SHA-512"`, config.Mechanism)
+		return config.Validate()
 	}
 
 	return nil
 }
 
-func configureTLS(config configtls.TLSClientSetting, saramaConfig *sarama.Config) error {
+func configureTLS(config TLSConfig, saramaConfig *sarama.Config) error {
 	tlsConfig, err := config.LoadTLSConfig()
 	if err != nil {
 		return fmt.Errorf("error loading tls config: %w", err)
@@ -15,6 +15,9 @@
 package kafkaexporter
 
 import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Shopify/sarama"
@@ -77,12 +80,14 @@ func TestAuthentication(t *testing.T) {
 			saramaConfig: saramaPlaintext,
 		},
 		{
-			auth:         Authentication{TLS: &configtls.TLSClientSetting{}},
+			auth:         Authentication{TLS: &TLSConfig{TLSClientSetting: configtls.TLSClientSetting{}}},
 			saramaConfig: saramaTLSCfg,
 		},
 		{
-			auth: Authentication{TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{CAFile: "/doesnotexists"},
+			auth: Authentication{TLS: &TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{CAFile: "/doesnotexists"},
+				},
 			}},
 			saramaConfig: saramaTLSCfg,
 			err:          "failed to load TLS config",
@@ -139,3 +144,158 @@ func TestAuthentication(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSConfig_inlinePEM(t *testing.T) {
+	caPem, err := os.ReadFile(filepath.Join("testdata", "ca.crt"))
+	require.NoError(t, err)
+	certPem, err := os.ReadFile(filepath.Join("testdata", "client.crt"))
+	require.NoError(t, err)
+	keyPem, err := os.ReadFile(filepath.Join("testdata", "client.key"))
+	require.NoError(t, err)
+
+	tlsConfig, err := TLSConfig{
+		CAPem:   string(caPem),
+		CertPem: string(certPem),
+		KeyPem:  string(keyPem),
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	fromFiles, err := configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   filepath.Join("testdata", "ca.crt"),
+			CertFile: filepath.Join("testdata", "client.crt"),
+			KeyFile:  filepath.Join("testdata", "client.key"),
+		},
+	}.LoadTLSConfig()
+	require.NoError(t, err)
+
+	assert.True(t, fromFiles.RootCAs.Equal(tlsConfig.RootCAs))
+	require.Len(t, tlsConfig.Certificates, 1)
+	fromFilesCert, err := fromFiles.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, fromFilesCert.Certificate, tlsConfig.Certificates[0].Certificate)
+
+	config := &sarama.Config{}
+	err = ConfigureAuthentication(Authentication{TLS: &TLSConfig{CAPem: string(caPem)}}, config)
+	require.NoError(t, err)
+	assert.True(t, config.Net.TLS.Enable)
+	assert.True(t, fromFiles.RootCAs.Equal(config.Net.TLS.Config.RootCAs))
+}
+
+func TestTLSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TLSConfig
+		err    string
+	}{
+		{
+			name:   "inline only",
+			config: TLSConfig{CAPem: "ca", CertPem: "cert", KeyPem: "key"},
+		},
+		{
+			name: "files only",
+			config: TLSConfig{TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{CAFile: "ca.crt", CertFile: "client.crt", KeyFile: "client.key"},
+			}},
+		},
+		{
+			name: "ca file and inline cert",
+			config: TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CAFile: "ca.crt"}},
+				CertPem:          "cert",
+				KeyPem:           "key",
+			},
+		},
+		{
+			name: "ca set twice",
+			config: TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CAFile: "ca.crt"}},
+				CAPem:            "ca",
+			},
+			err: "ca_file and ca_pem cannot both be set",
+		},
+		{
+			name: "cert set twice",
+			config: TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{CertFile: "client.crt"}},
+				CertPem:          "cert",
+				KeyPem:           "key",
+			},
+			err: "cert_file and cert_pem cannot both be set",
+		},
+		{
+			name: "key set twice",
+			config: TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{TLSSetting: configtls.TLSSetting{KeyFile: "client.key"}},
+				CertPem:          "cert",
+				KeyPem:           "key",
+			},
+			err: "key_file and key_pem cannot both be set",
+		},
+		{
+			name:   "inline cert without key",
+			config: TLSConfig{CertPem: "cert"},
+			err:    "cert_pem and key_pem have to be set together",
+		},
+		{
+			name: "min version 1.3",
+			config: TLSConfig{TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{MinVersion: "1.3"},
+			}},
+		},
+		{
+			name: "min version below 1.2",
+			config: TLSConfig{TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{MinVersion: "1.1"},
+			}},
+			err: "min_version should be '1.2' or '1.3'. configured value 1.1",
+		},
+		{
+			name: "unknown min version",
+			config: TLSConfig{TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{MinVersion: "TLS1.2"},
+			}},
+			err: "min_version should be '1.2' or '1.3'. configured value TLS1.2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_minVersion(t *testing.T) {
+	tests := []struct {
+		minVersion string
+		expected   uint16
+	}{
+		{minVersion: "", expected: tls.VersionTLS12},
+		{minVersion: "1.2", expected: tls.VersionTLS12},
+		{minVersion: "1.3", expected: tls.VersionTLS13},
+	}
+	for _, test := range tests {
+		t.Run(test.minVersion, func(t *testing.T) {
+			config := &sarama.Config{}
+			tlsConfig := &TLSConfig{TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{MinVersion: test.minVersion},
+			}}
+			err := ConfigureAuthentication(Authentication{TLS: tlsConfig}, config)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config.Net.TLS.Config.MinVersion)
+		})
+	}
+}
+
+func TestTLSConfig_invalidPEM(t *testing.T) {
+	_, err := TLSConfig{CAPem: "not a pem"}.LoadTLSConfig()
+	assert.EqualError(t, err, "failed to parse ca_pem")
+
+	_, err = TLSConfig{CertPem: "not a pem", KeyPem: "not a pem"}.LoadTLSConfig()
+	assert.Error(t, err)
+}
@@ -16,10 +16,13 @@ package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
@@ -37,9 +40,84 @@ type Config struct {
 	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
 	Topic string `mapstructure:"topic"`
 
+	// TopicFromAttribute is the name of an attribute whose string value is the topic to export a batch to.
+	// The attribute is looked up in the resource attributes of the batch first, then in the attributes of
+	// its spans, data points or log records. Falls back to Topic when the attribute is absent or not a string.
+	// The encoding is selected from the resulting topic by EncodingByTopic.
+	TopicFromAttribute string `mapstructure:"topic_from_attribute"`
+
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 
+	// EncodingByTopic overrides Encoding for the messages produced to the given topics, so that exporters
+	// fanning out to several topics can share a configuration. Falls back to Encoding for other topics.
+	EncodingByTopic map[string]string `mapstructure:"encoding_by_topic"`
+
+	// SendSchemaVersionHeader adds an otlp-proto-version header to every message
+	// when Encoding is otlp_proto, allowing consumers to check compatibility.
+	SendSchemaVersionHeader bool `mapstructure:"send_schema_version_header"`
+
+	// CoalesceByKey produces a single message for all records of an export batch sharing
+	// the same message key, instead of one message per record.
+	CoalesceByKey bool `mapstructure:"coalesce_by_key"`
+
+	// TombstoneAttribute is the name of a record attribute marking the record as a deletion.
+	// Marked records are produced as a keyed message with a nil value, deleting the key from compacted topics.
+	TombstoneAttribute string `mapstructure:"tombstone_attribute"`
+
+	// PartitionTracesByID keys the trace messages without key with the trace ID of the first span of the batch,
+	// so that the default partitioner produces the batches of a trace to the same partition of the topic.
+	// Metrics and logs messages are not affected.
+	PartitionTracesByID bool `mapstructure:"partition_traces_by_id"`
+
+	// EmitKeyAsHeader is the name of a header to which the message key is copied, for consumers
+	// that need the key without reading the record key. Messages without key get no header.
+	EmitKeyAsHeader string `mapstructure:"emit_key_as_header"`
+
+	// HeadersFromAttributes are the keys of resource attributes added as headers to the messages of a batch,
+	// with the string value of the attribute. The value is taken from the first resource of the batch having
+	// the attribute, and attributes absent from all the resources are skipped.
+	HeadersFromAttributes []string `mapstructure:"headers_from_attributes"`
+
+	// RawPayloadAttribute is the name of the span attribute holding the pre-serialized payload
+	// produced as message value by the traces raw encoding. Required by the traces raw encoding.
+	RawPayloadAttribute string `mapstructure:"raw_payload_attribute"`
+
+	// BatchDeadline bounds the total time spent producing the messages of an export batch.
+	// When set, the messages are produced one at a time and the whole batch is retried once the deadline
+	// is exceeded. Zero disables the deadline.
+	BatchDeadline time.Duration `mapstructure:"batch_deadline"`
+
+	// PreCompress compresses message payloads in the exporter rather than in the producer, and marks
+	// them with a content-encoding header. The options are 'gzip' and 'zstd', empty disables pre-compression.
+	// It requires Producer.Compression to be 'none' so that the broker does not recompress payloads.
+	PreCompress string `mapstructure:"pre_compress"`
+
+	// SaramaDebugLogging routes the logs of the Sarama client through the collector logger at debug level.
+	// Sarama's logger is global, so this applies to all the Kafka components of the collector.
+	SaramaDebugLogging bool `mapstructure:"sarama_debug_logging"`
+
+	// StickyPartitioning keeps producing the messages of a key to the partition it was first assigned to,
+	// even if the number of partitions changes, instead of hashing the key on every message.
+	StickyPartitioning bool `mapstructure:"sticky_partitioning"`
+
+	// KeyHashAlgorithm is the hash of message keys selecting their partition. The options are 'fnv', the hash of
+	// the default Sarama partitioner, and 'murmur2', the hash of the default Java client partitioner, so that keys
+	// are produced to the same partitions as Java producers. Empty uses the default Sarama partitioner.
+	KeyHashAlgorithm string `mapstructure:"key_hash_algorithm"`
+
+	// DeadLetterTopic is the name of a topic to which the messages rejected by the brokers are produced,
+	// instead of failing the export batch. Empty disables the dead letter topic.
+	DeadLetterTopic string `mapstructure:"dead_letter_topic"`
+
+	// DeadLetterEnvelope produces a JSON envelope holding the rejected message and the cause of the
+	// failure to the dead letter topic, instead of the rejected message as is.
+	DeadLetterEnvelope bool `mapstructure:"dead_letter_envelope"`
+
+	// MaxInFlight bounds the number of concurrent produce calls, independently of the number of queue consumers
+	// set by sending_queue.num_consumers, to limit the pressure on the broker connections. Zero disables the limit.
+	MaxInFlight int `mapstructure:"max_in_flight"`
+
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
 	Metadata Metadata `mapstructure:"metadata"`
@@ -87,6 +165,46 @@ type Producer struct {
 	// broker request. Defaults to 0 for unlimited. Similar to
 	// `queue.buffering.max.messages` in the JVM producer.
 	FlushMaxMessages int `mapstructure:"flush_max_messages"`
+
+	// Idempotent enables the idempotent producer, so that retried messages are not duplicated by the brokers.
+	// It raises RequiredAcks to WaitForAll and limits the in-flight requests per broker to one, as required
+	// by sarama, and requires a protocol version of at least 0.11.0.0.
+	Idempotent bool `mapstructure:"idempotent"`
+
+	// CompressionParams tunes the compression of messages.
+	CompressionParams CompressionParams `mapstructure:"compression_params"`
+
+	// requiredAcksSet is true if RequiredAcks is set in the configuration rather than defaulted,
+	// so that the idempotent producer rejects an explicit WaitForLocal.
+	requiredAcksSet bool
+}
+
+// Unmarshal unmarshals the producer configuration and records whether required_acks is set.
+func (p *Producer) Unmarshal(conf *confmap.Conf) error {
+	if conf == nil {
+		return nil
+	}
+	if err := conf.Unmarshal(p, confmap.WithErrorUnused()); err != nil {
+		return err
+	}
+	p.requiredAcksSet = conf.IsSet("required_acks")
+	return nil
+}
+
+// CompressionParams defines the parameters of the compression codecs.
+type CompressionParams struct {
+	Zstd ZstdCompressionParams `mapstructure:"zstd"`
+}
+
+// ZstdCompressionParams defines the parameters of the zstd compression, used by the 'zstd' producer
+// compression and pre-compression.
+type ZstdCompressionParams struct {
+	// Level is the zstd compression level, from 1 to 22. Zero uses the default level.
+	Level int `mapstructure:"level"`
+
+	// DictionaryFile is the path to a pre-trained zstd dictionary. Kafka consumers cannot decompress record
+	// batches compressed with a dictionary, so it is only used by the 'zstd' pre-compression.
+	DictionaryFile string `mapstructure:"dictionary_file"`
 }
 
 // MetadataRetry defines retry configuration for Metadata.
@@ -101,17 +219,99 @@ type MetadataRetry struct {
 
 var _ config.Exporter = (*Config)(nil)
 
+// validateEncoding checks that encoding is built in or registered with RegisterMarshaler.
+// Whether it supports the signal of the exporter is only known when the exporter is created.
+func validateEncoding(encoding string) error {
+	if encoding == "" || isKnownEncoding(encoding) {
+		return nil
+	}
+	return fmt.Errorf("encoding %q is not registered, registered encodings are: %s", encoding, strings.Join(knownEncodings(), ", "))
+}
+
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
 	if cfg.Producer.RequiredAcks < -1 || cfg.Producer.RequiredAcks > 1 {
 		return fmt.Errorf("producer.required_acks has to be between -1 and 1. configured value %v", cfg.Producer.RequiredAcks)
 	}
 
+	if cfg.Producer.Idempotent {
+		// WaitForLocal is the default, which is raised to WaitForAll unless it is set explicitly
+		requiredAcks := cfg.Producer.RequiredAcks
+		if requiredAcks != sarama.WaitForAll && (cfg.Producer.requiredAcksSet || requiredAcks == sarama.NoResponse) {
+			return fmt.Errorf("producer.idempotent requires producer.required_acks to be -1 (WaitForAll). configured value %v", cfg.Producer.RequiredAcks)
+		}
+		if cfg.ProtocolVersion != "" {
+			version, err := sarama.ParseKafkaVersion(cfg.ProtocolVersion)
+			if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+				return fmt.Errorf("producer.idempotent requires protocol_version to be at least 0.11.0.0. configured value %v", cfg.ProtocolVersion)
+			}
+		}
+	}
+
+	if err := validateEncoding(cfg.Encoding); err != nil {
+		return err
+	}
+	for _, encoding := range cfg.EncodingByTopic {
+		if err := validateEncoding(encoding); err != nil {
+			return err
+		}
+	}
+
 	_, err := saramaProducerCompressionCodec(cfg.Producer.Compression)
 	if err != nil {
 		return err
 	}
 
+	if cfg.PreCompress != "" {
+		if cfg.PreCompress != preCompressGzip && cfg.PreCompress != preCompressZstd {
+			return fmt.Errorf("pre_compress should be empty, 'gzip', or 'zstd'. configured value %v", cfg.PreCompress)
+		}
+		if cfg.Producer.Compression != "none" {
+			return fmt.Errorf("pre_compress requires producer.compression to be 'none'. configured value %v", cfg.Producer.Compression)
+		}
+	}
+
+	zstdParams := cfg.Producer.CompressionParams.Zstd
+	if zstdParams.Level != 0 && (zstdParams.Level < 1 || zstdParams.Level > 22) {
+		return fmt.Errorf("producer.compression_params.zstd.level should be between 1 and 22. configured value %v", zstdParams.Level)
+	}
+	if zstdParams.DictionaryFile != "" {
+		if cfg.PreCompress != preCompressZstd {
+			return fmt.Errorf("producer.compression_params.zstd.dictionary_file requires pre_compress to be 'zstd'. configured value %v", cfg.PreCompress)
+		}
+		if _, err := os.Stat(zstdParams.DictionaryFile); err != nil {
+			return fmt.Errorf("producer.compression_params.zstd.dictionary_file cannot be read: %w", err)
+		}
+	}
+
+	if cfg.KeyHashAlgorithm != "" && cfg.KeyHashAlgorithm != keyHashFNV && cfg.KeyHashAlgorithm != keyHashMurmur2 {
+		return fmt.Errorf("key_hash_algorithm should be empty, 'fnv', or 'murmur2'. configured value %v", cfg.KeyHashAlgorithm)
+	}
+
+	if cfg.BatchDeadline < 0 {
+		return fmt.Errorf("batch_deadline must not be negative. configured value %v", cfg.BatchDeadline)
+	}
+
+	if cfg.MaxInFlight < 0 {
+		return fmt.Errorf("max_in_flight must not be negative. configured value %v", cfg.MaxInFlight)
+	}
+
+	if cfg.DeadLetterEnvelope && cfg.DeadLetterTopic == "" {
+		return fmt.Errorf("dead_letter_envelope requires dead_letter_topic to be set")
+	}
+
+	if cfg.Authentication.TLS != nil {
+		if err := cfg.Authentication.TLS.Validate(); err != nil {
+			return fmt.Errorf("auth.tls has invalid configuration: %w", err)
+		}
+	}
+
+	if cfg.Authentication.SASL != nil {
+		if err := cfg.Authentication.SASL.Validate(); err != nil {
+			return fmt.Errorf("auth.sasl has invalid configuration: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -76,6 +78,38 @@ func TestLoadConfig(t *testing.T) {
 					MaxMessageBytes: 10000000,
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
+					requiredAcksSet: true,
+				},
+			},
+		},
+		{
+			id: config.NewComponentIDWithName(typeStr, "sasl"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				Topic:            "spans",
+				Encoding:         "otlp_proto",
+				Brokers:          []string{"foo:123"},
+				Authentication: Authentication{
+					SASL: &SASLConfig{
+						Username:  "jdoe",
+						Password:  "pass",
+						Mechanism: "SCRAM-SHA-512",
+					},
+				},
+				Metadata: Metadata{
+					Full: defaultMetadataFull,
+					Retry: MetadataRetry{
+						Max:     defaultMetadataRetryMax,
+						Backoff: defaultMetadataRetryBackoff,
+					},
+				},
+				Producer: Producer{
+					MaxMessageBytes: defaultProducerMaxMessageBytes,
+					RequiredAcks:    defaultProducerRequiredAcks,
+					Compression:     defaultCompression,
 				},
 			},
 		},
@@ -108,6 +142,297 @@ func TestValidate_err_compression(t *testing.T) {
 	assert.Equal(t, err.Error(), "producer.compression should be one of 'none', 'gzip', 'snappy', 'lz4', or 'zstd'. configured value idk")
 }
 
+func TestValidate_zstd_compression_params(t *testing.T) {
+	tests := []struct {
+		name        string
+		preCompress string
+		params      ZstdCompressionParams
+		expectedErr string
+	}{
+		{
+			name:   "level",
+			params: ZstdCompressionParams{Level: 22},
+		},
+		{
+			name:        "level out of range",
+			params:      ZstdCompressionParams{Level: 23},
+			expectedErr: "producer.compression_params.zstd.level should be between 1 and 22. configured value 23",
+		},
+		{
+			name:        "negative level",
+			params:      ZstdCompressionParams{Level: -1},
+			expectedErr: "producer.compression_params.zstd.level should be between 1 and 22. configured value -1",
+		},
+		{
+			name:        "dictionary",
+			preCompress: "zstd",
+			params:      ZstdCompressionParams{DictionaryFile: filepath.Join("testdata", "spans.zstd.dict")},
+		},
+		{
+			name:        "dictionary without zstd pre-compression",
+			preCompress: "gzip",
+			params:      ZstdCompressionParams{DictionaryFile: filepath.Join("testdata", "spans.zstd.dict")},
+			expectedErr: "producer.compression_params.zstd.dictionary_file requires pre_compress to be 'zstd'. configured value gzip",
+		},
+		{
+			name:        "missing dictionary",
+			preCompress: "zstd",
+			params:      ZstdCompressionParams{DictionaryFile: filepath.Join("testdata", "missing.dict")},
+			expectedErr: "producer.compression_params.zstd.dictionary_file cannot be read",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Producer: Producer{
+					Compression:       "none",
+					CompressionParams: CompressionParams{Zstd: tt.params},
+				},
+				PreCompress: tt.preCompress,
+			}
+
+			err := config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestValidate_err_batch_deadline(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		BatchDeadline: -time.Second,
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "batch_deadline must not be negative. configured value -1s")
+}
+
+func TestValidate_err_max_in_flight(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		MaxInFlight: -1,
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "max_in_flight must not be negative. configured value -1")
+}
+
+func TestValidate_err_key_hash_algorithm(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		KeyHashAlgorithm: "crc32",
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "key_hash_algorithm should be empty, 'fnv', or 'murmur2'. configured value crc32")
+}
+
+func TestValidate_idempotent(t *testing.T) {
+	tests := []struct {
+		name            string
+		requiredAcks    sarama.RequiredAcks
+		requiredAcksSet bool
+		protocolVersion string
+		expectedErr     string
+	}{
+		{
+			name:            "wait for all",
+			requiredAcks:    sarama.WaitForAll,
+			requiredAcksSet: true,
+		},
+		{
+			name:         "default required acks",
+			requiredAcks: sarama.WaitForLocal,
+		},
+		{
+			name:            "wait for local",
+			requiredAcks:    sarama.WaitForLocal,
+			requiredAcksSet: true,
+			expectedErr:     "producer.idempotent requires producer.required_acks to be -1 (WaitForAll). configured value 1",
+		},
+		{
+			name:         "no response",
+			requiredAcks: sarama.NoResponse,
+			expectedErr:  "producer.idempotent requires producer.required_acks to be -1 (WaitForAll). configured value 0",
+		},
+		{
+			name:            "protocol version",
+			requiredAcks:    sarama.WaitForAll,
+			protocolVersion: "2.0.0",
+		},
+		{
+			name:            "old protocol version",
+			requiredAcks:    sarama.WaitForAll,
+			protocolVersion: "0.10.2.0",
+			expectedErr:     "producer.idempotent requires protocol_version to be at least 0.11.0.0. configured value 0.10.2.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				ProtocolVersion: tt.protocolVersion,
+				Producer: Producer{
+					Compression:     "none",
+					RequiredAcks:    tt.requiredAcks,
+					Idempotent:      true,
+					requiredAcksSet: tt.requiredAcksSet,
+				},
+			}
+
+			err := config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestProducer_Unmarshal_requiredAcksSet(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"producer": map[string]interface{}{"idempotent": true},
+	})
+	require.NoError(t, config.UnmarshalExporter(conf, cfg))
+	assert.False(t, cfg.Producer.requiredAcksSet)
+	assert.Equal(t, defaultProducerRequiredAcks, cfg.Producer.RequiredAcks)
+	assert.Equal(t, defaultProducerMaxMessageBytes, cfg.Producer.MaxMessageBytes)
+	assert.NoError(t, cfg.Validate())
+
+	cfg = NewFactory().CreateDefaultConfig().(*Config)
+	conf = confmap.NewFromStringMap(map[string]interface{}{
+		"producer": map[string]interface{}{"idempotent": true, "required_acks": 1},
+	})
+	require.NoError(t, config.UnmarshalExporter(conf, cfg))
+	assert.True(t, cfg.Producer.requiredAcksSet)
+	assert.EqualError(t, cfg.Validate(), "producer.idempotent requires producer.required_acks to be -1 (WaitForAll). configured value 1")
+}
+
+func TestValidate_err_dead_letter_envelope(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		DeadLetterEnvelope: true,
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "dead_letter_envelope requires dead_letter_topic to be set")
+}
+
+func TestValidate_default_topic(t *testing.T) {
+	// an empty topic selects the default topic of each signal, with or without topic_from_attribute
+	config := createDefaultConfig().(*Config)
+	assert.NoError(t, config.Validate())
+
+	config.TopicFromAttribute = "tenant"
+	assert.NoError(t, config.Validate())
+}
+
+func TestValidate_err_pre_compress(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		PreCompress: "snappy",
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "pre_compress should be empty, 'gzip', or 'zstd'. configured value snappy")
+}
+
+func TestValidate_err_pre_compress_with_compression(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "gzip",
+		},
+		PreCompress: "gzip",
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "pre_compress requires producer.compression to be 'none'. configured value gzip")
+}
+
+func TestValidate_err_tls(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		Authentication: Authentication{
+			TLS: &TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{CAFile: "ca.crt"},
+				},
+				CAPem: "-----BEGIN CERTIFICATE-----",
+			},
+		},
+	}
+
+	err := config.Validate()
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "auth.tls has invalid configuration: ca_file and ca_pem cannot both be set")
+}
+
+func TestValidate_sasl_mechanism(t *testing.T) {
+	tests := []struct {
+		mechanism   string
+		expectedErr string
+	}{
+		{mechanism: "PLAIN"},
+		{mechanism: "SCRAM-SHA-256"},
+		{mechanism: "SCRAM-SHA-512"},
+		{mechanism: "AWS_MSK_IAM"},
+		{
+			mechanism:   "scram-sha-512",
+			expectedErr: `auth.sasl has invalid configuration: invalid SASL Mechanism "scram-sha-512": can be either "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256" or "SCRAM-SHA-512"`,
+		},
+		{
+			mechanism:   "",
+			expectedErr: `auth.sasl has invalid configuration: invalid SASL Mechanism "": can be either "PLAIN", "AWS_MSK_IAM", "SCRAM-SHA-256" or "SCRAM-SHA-512"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mechanism, func(t *testing.T) {
+			config := &Config{
+				Producer: Producer{
+					Compression: "none",
+				},
+				Authentication: Authentication{
+					SASL: &SASLConfig{
+						Username:  "jdoe",
+						Password:  "pass",
+						Mechanism: tt.mechanism,
+					},
+				},
+			}
+
+			err := config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
 func Test_saramaProducerCompressionCodec(t *testing.T) {
 	tests := map[string]struct {
 		compression         string
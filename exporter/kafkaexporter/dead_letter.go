@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// deadLetterEnvelope is produced to the dead letter topic in place of a message rejected by the brokers
+// when DeadLetterEnvelope is enabled, so that the cause of the failure can be triaged.
+type deadLetterEnvelope struct {
+	// Payload is the value of the rejected message, base64 encoded in JSON. Empty for tombstones.
+	Payload []byte `json:"payload"`
+	// Error is the error returned by the brokers for the message.
+	Error string `json:"error"`
+	// Timestamp is the time at which the message was routed to the dead letter topic.
+	Timestamp time.Time `json:"timestamp"`
+	// Topic is the topic the message was destined for.
+	Topic string `json:"topic"`
+	// Retries is the number of times the producer retries a message before reporting it as failed.
+	Retries int `json:"retries"`
+}
+
+// deadLetterQueue produces the messages rejected by the brokers to a dead letter topic.
+type deadLetterQueue struct {
+	topic    string
+	envelope bool
+	retries  int
+}
+
+// newDeadLetterQueue returns the dead letter queue of the config, or nil if no dead letter topic is set.
+func newDeadLetterQueue(config Config) *deadLetterQueue {
+	if config.DeadLetterTopic == "" {
+		return nil
+	}
+	return &deadLetterQueue{
+		topic:    config.DeadLetterTopic,
+		envelope: config.DeadLetterEnvelope,
+		// the producer retries are not configurable, so they are the sarama default
+		retries: sarama.NewConfig().Producer.Retry.Max,
+	}
+}
+
+// send produces the failed messages to the dead letter topic.
+func (q *deadLetterQueue) send(producer sarama.SyncProducer, failed sarama.ProducerErrors) error {
+	messages := make([]*sarama.ProducerMessage, 0, len(failed))
+	for _, e := range failed {
+		message, err := q.message(e.Msg, e.Err)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, message)
+	}
+	if err := producer.SendMessages(messages); err != nil {
+		return fmt.Errorf("failed to produce %d messages to the dead letter topic %q: %w", len(messages), q.topic, err)
+	}
+	return nil
+}
+
+// message returns the message produced to the dead letter topic for a message that failed with cause.
+// The message keeps the key and headers of the failed message, and either its value or an envelope.
+func (q *deadLetterQueue) message(failed *sarama.ProducerMessage, cause error) (*sarama.ProducerMessage, error) {
+	message := &sarama.ProducerMessage{
+		Topic:   q.topic,
+		Key:     failed.Key,
+		Value:   failed.Value,
+		Headers: failed.Headers,
+	}
+	if !q.envelope {
+		return message, nil
+	}
+
+	envelope := deadLetterEnvelope{
+		Error:     cause.Error(),
+		Timestamp: time.Now().UTC(),
+		Topic:     failed.Topic,
+		Retries:   q.retries,
+	}
+	if failed.Value != nil {
+		payload, err := failed.Value.Encode()
+		if err != nil {
+			return nil, err
+		}
+		envelope.Payload = payload
+	}
+	value, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	message.Value = sarama.ByteEncoder(value)
+	return message, nil
+}
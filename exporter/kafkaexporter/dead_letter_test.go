@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rejectingProducer rejects the messages produced to the given topic with err and records the other messages.
+type rejectingProducer struct {
+	sarama.SyncProducer
+	rejectedTopic string
+	err           error
+	produced      []*sarama.ProducerMessage
+}
+
+func (p *rejectingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	if msg.Topic == p.rejectedTopic {
+		return 0, 0, p.err
+	}
+	p.produced = append(p.produced, msg)
+	return 0, int64(len(p.produced)), nil
+}
+
+func (p *rejectingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	var errs sarama.ProducerErrors
+	for _, msg := range msgs {
+		if _, _, err := p.SendMessage(msg); err != nil {
+			errs = append(errs, &sarama.ProducerError{Msg: msg, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func testMessages() []*sarama.ProducerMessage {
+	return []*sarama.ProducerMessage{
+		{
+			Topic:   "spans",
+			Key:     sarama.StringEncoder("trace-1"),
+			Value:   sarama.ByteEncoder("span-1"),
+			Headers: []sarama.RecordHeader{{Key: []byte("content-encoding"), Value: []byte("gzip")}},
+		},
+		{Topic: "spans", Key: sarama.StringEncoder("trace-2")},
+	}
+}
+
+func TestSendMessages_deadLetterTopic(t *testing.T) {
+	send := map[string]func(sarama.SyncProducer, []*sarama.ProducerMessage, *deadLetterQueue) error{
+		"without deadline": sendMessages,
+		"within deadline": func(producer sarama.SyncProducer, messages []*sarama.ProducerMessage, dlq *deadLetterQueue) error {
+			var abandoned int32
+			return sendMessagesWithin(producer, messages, dlq, time.Minute, &abandoned)
+		},
+	}
+	for _, sendMessages := range send {
+		producer := &rejectingProducer{rejectedTopic: "spans", err: sarama.ErrMessageSizeTooLarge}
+		dlq := newDeadLetterQueue(Config{DeadLetterTopic: "spans-dlq"})
+
+		require.NoError(t, sendMessages(producer, testMessages(), dlq))
+		require.Len(t, producer.produced, 2)
+		assert.Equal(t, &sarama.ProducerMessage{
+			Topic:   "spans-dlq",
+			Key:     sarama.StringEncoder("trace-1"),
+			Value:   sarama.ByteEncoder("span-1"),
+			Headers: []sarama.RecordHeader{{Key: []byte("content-encoding"), Value: []byte("gzip")}},
+		}, producer.produced[0])
+		assert.Equal(t, &sarama.ProducerMessage{Topic: "spans-dlq", Key: sarama.StringEncoder("trace-2")}, producer.produced[1])
+	}
+}
+
+func TestSendMessages_deadLetterEnvelope(t *testing.T) {
+	producer := &rejectingProducer{rejectedTopic: "spans", err: sarama.ErrMessageSizeTooLarge}
+	dlq := newDeadLetterQueue(Config{DeadLetterTopic: "spans-dlq", DeadLetterEnvelope: true})
+
+	before := time.Now()
+	require.NoError(t, sendMessages(producer, testMessages(), dlq))
+	require.Len(t, producer.produced, 2)
+
+	message := producer.produced[0]
+	assert.Equal(t, "spans-dlq", message.Topic)
+	assert.Equal(t, sarama.StringEncoder("trace-1"), message.Key)
+	value, err := message.Value.Encode()
+	require.NoError(t, err)
+
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal(value, &envelope))
+	assert.Equal(t, "c3Bhbi0x", envelope["payload"])
+	assert.Equal(t, sarama.ErrMessageSizeTooLarge.Error(), envelope["error"])
+	assert.Equal(t, "spans", envelope["topic"])
+	assert.Equal(t, 3.0, envelope["retries"])
+	timestamp, err := time.Parse(time.RFC3339Nano, envelope["timestamp"].(string))
+	require.NoError(t, err)
+	assert.False(t, timestamp.Before(before.Truncate(time.Second)))
+	assert.False(t, timestamp.After(time.Now()))
+
+	// tombstones have no payload
+	value, err = producer.produced[1].Value.Encode()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(value, &envelope))
+	assert.Nil(t, envelope["payload"])
+}
+
+func TestSendMessages_deadLetterTopic_connectionError(t *testing.T) {
+	producer := &rejectingProducer{rejectedTopic: "spans", err: sarama.ErrOutOfBrokers}
+	dlq := newDeadLetterQueue(Config{DeadLetterTopic: "spans-dlq"})
+
+	err := sendMessages(producer, testMessages(), dlq)
+	assert.True(t, isConnectionError(err))
+	assert.Empty(t, producer.produced)
+}
+
+func TestSendMessages_deadLetterTopic_rejected(t *testing.T) {
+	producer := &rejectingProducer{rejectedTopic: "spans-dlq", err: sarama.ErrMessageSizeTooLarge}
+	dlq := newDeadLetterQueue(Config{DeadLetterTopic: "spans-dlq"})
+	messages := testMessages()
+	messages[0].Topic = "spans-dlq"
+
+	err := sendMessages(producer, messages, dlq)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to produce 1 messages to the dead letter topic "spans-dlq"`)
+}
+
+func TestNewDeadLetterQueue_disabled(t *testing.T) {
+	assert.Nil(t, newDeadLetterQueue(Config{DeadLetterEnvelope: true}))
+}
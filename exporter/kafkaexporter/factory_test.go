@@ -141,6 +141,7 @@ func TestCreateLogsExporter_err(t *testing.T) {
 
 func TestWithMarshalers(t *testing.T) {
 	cm := &customMarshaler{}
+	unregisterMarshalers(t, cm.Encoding())
 	f := NewFactory(WithTracesMarshalers(cm))
 	cfg := createDefaultConfig().(*Config)
 	// disable contacting broker
@@ -16,6 +16,7 @@ package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"bytes"
+	"strconv"
 
 	"github.com/Shopify/sarama"
 	"github.com/gogo/protobuf/jsonpb"
@@ -28,28 +29,44 @@ import (
 
 type jaegerMarshaler struct {
 	marshaler jaegerSpanMarshaler
+	// coalesceByKey produces a single jaeger Batch message per trace ID instead of a message per span
+	coalesceByKey bool
+	// tombstoneAttribute is the span attribute marking a span as a deletion of its trace ID
+	tombstoneAttribute string
 }
 
 var _ TracesMarshaler = (*jaegerMarshaler)(nil)
+var _ keyCoalescingMarshaler = (*jaegerMarshaler)(nil)
+var _ tombstoneMarshaler = (*jaegerMarshaler)(nil)
 
 func (j jaegerMarshaler) Marshal(traces ptrace.Traces, topic string) ([]*sarama.ProducerMessage, error) {
 	batches, err := jaeger.ProtoFromTraces(traces)
 	if err != nil {
 		return nil, err
 	}
+	if j.coalesceByKey {
+		return j.marshalByTraceID(batches, topic)
+	}
 	var messages []*sarama.ProducerMessage
 
 	var errs error
 	for _, batch := range batches {
 		for _, span := range batch.Spans {
 			span.Process = batch.Process
+			key := []byte(span.TraceID.String())
+			if j.isTombstone(span) {
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Key:   sarama.ByteEncoder(key),
+				})
+				continue
+			}
 			bts, err := j.marshaler.marshal(span)
 			// continue to process spans that can be serialized
 			if err != nil {
 				errs = multierr.Append(errs, err)
 				continue
 			}
-			key := []byte(span.TraceID.String())
 			messages = append(messages, &sarama.ProducerMessage{
 				Topic: topic,
 				Value: sarama.ByteEncoder(bts),
@@ -60,12 +77,97 @@ func (j jaegerMarshaler) Marshal(traces ptrace.Traces, topic string) ([]*sarama.
 	return messages, errs
 }
 
+// marshalByTraceID groups the spans of all batches by trace ID and produces one message
+// per trace ID, holding a jaeger Batch of the spans sharing that trace ID.
+// A trace ID with at least one tombstone span produces a single tombstone.
+func (j jaegerMarshaler) marshalByTraceID(batches []*jaegerproto.Batch, topic string) ([]*sarama.ProducerMessage, error) {
+	var traceIDs []jaegerproto.TraceID
+	grouped := make(map[jaegerproto.TraceID]*jaegerproto.Batch)
+	tombstones := make(map[jaegerproto.TraceID]bool)
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			span.Process = batch.Process
+			if j.isTombstone(span) {
+				tombstones[span.TraceID] = true
+			}
+			group, ok := grouped[span.TraceID]
+			if !ok {
+				group = &jaegerproto.Batch{}
+				grouped[span.TraceID] = group
+				traceIDs = append(traceIDs, span.TraceID)
+			}
+			group.Spans = append(group.Spans, span)
+		}
+	}
+
+	messages := make([]*sarama.ProducerMessage, 0, len(traceIDs))
+	var errs error
+	for _, traceID := range traceIDs {
+		if tombstones[traceID] {
+			messages = append(messages, &sarama.ProducerMessage{
+				Topic: topic,
+				Key:   sarama.ByteEncoder(traceID.String()),
+			})
+			continue
+		}
+		bts, err := j.marshaler.marshalBatch(grouped[traceID])
+		// continue to process traces that can be serialized
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		messages = append(messages, &sarama.ProducerMessage{
+			Topic: topic,
+			Value: sarama.ByteEncoder(bts),
+			Key:   sarama.ByteEncoder(traceID.String()),
+		})
+	}
+	return messages, errs
+}
+
 func (j jaegerMarshaler) Encoding() string {
 	return j.marshaler.encoding()
 }
 
+func (j jaegerMarshaler) withCoalesceByKey() TracesMarshaler {
+	j.coalesceByKey = true
+	return j
+}
+
+func (j jaegerMarshaler) withTombstoneAttribute(attribute string) TracesMarshaler {
+	j.tombstoneAttribute = attribute
+	return j
+}
+
+// isTombstone returns whether the span has a truthy tombstone attribute, i.e. a true bool,
+// a string parsing to true or a non-zero number.
+func (j jaegerMarshaler) isTombstone(span *jaegerproto.Span) bool {
+	if j.tombstoneAttribute == "" {
+		return false
+	}
+	for _, tag := range span.Tags {
+		if tag.Key != j.tombstoneAttribute {
+			continue
+		}
+		switch tag.VType {
+		case jaegerproto.ValueType_BOOL:
+			return tag.VBool
+		case jaegerproto.ValueType_STRING:
+			b, err := strconv.ParseBool(tag.VStr)
+			return err == nil && b
+		case jaegerproto.ValueType_INT64:
+			return tag.VInt64 != 0
+		case jaegerproto.ValueType_FLOAT64:
+			return tag.VFloat64 != 0
+		}
+		return false
+	}
+	return false
+}
+
 type jaegerSpanMarshaler interface {
 	marshal(span *jaegerproto.Span) ([]byte, error)
+	marshalBatch(batch *jaegerproto.Batch) ([]byte, error)
 	encoding() string
 }
 
@@ -78,6 +180,10 @@ func (p jaegerProtoSpanMarshaler) marshal(span *jaegerproto.Span) ([]byte, error
 	return span.Marshal()
 }
 
+func (p jaegerProtoSpanMarshaler) marshalBatch(batch *jaegerproto.Batch) ([]byte, error) {
+	return batch.Marshal()
+}
+
 func (p jaegerProtoSpanMarshaler) encoding() string {
 	return "jaeger_proto"
 }
@@ -100,6 +206,12 @@ func (p jaegerJSONSpanMarshaler) marshal(span *jaegerproto.Span) ([]byte, error)
 	return out.Bytes(), err
 }
 
+func (p jaegerJSONSpanMarshaler) marshalBatch(batch *jaegerproto.Batch) ([]byte, error) {
+	out := new(bytes.Buffer)
+	err := p.pbMarshaler.Marshal(out, batch)
+	return out.Bytes(), err
+}
+
 func (p jaegerJSONSpanMarshaler) encoding() string {
 	return "jaeger_json"
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/gogo/protobuf/jsonpb"
+	jaegerproto "github.com/jaegertracing/jaeger/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -80,3 +81,114 @@ func TestJaegerMarshaler(t *testing.T) {
 		})
 	}
 }
+
+func TestJaegerMarshaler_coalesceByKey(t *testing.T) {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	traceA := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	traceB := [16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	for i, traceID := range [][16]byte{traceA, traceB, traceA} {
+		span := spans.AppendEmpty()
+		span.SetName("foo")
+		span.SetTraceID(traceID)
+		span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, byte(i)})
+	}
+
+	tests := []struct {
+		marshaler jaegerSpanMarshaler
+		unmarshal func(t *testing.T, bts []byte) *jaegerproto.Batch
+	}{
+		{
+			marshaler: jaegerProtoSpanMarshaler{},
+			unmarshal: func(t *testing.T, bts []byte) *jaegerproto.Batch {
+				batch := &jaegerproto.Batch{}
+				require.NoError(t, batch.Unmarshal(bts))
+				return batch
+			},
+		},
+		{
+			marshaler: newJaegerJSONMarshaler(),
+			unmarshal: func(t *testing.T, bts []byte) *jaegerproto.Batch {
+				batch := &jaegerproto.Batch{}
+				require.NoError(t, jsonpb.Unmarshal(bytes.NewReader(bts), batch))
+				return batch
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.marshaler.encoding(), func(t *testing.T) {
+			m := jaegerMarshaler{marshaler: test.marshaler}.withCoalesceByKey()
+			messages, err := m.Marshal(td, "topic")
+			require.NoError(t, err)
+			require.Len(t, messages, 2)
+
+			spanCounts := map[string]int{}
+			for _, message := range messages {
+				key, err := message.Key.Encode()
+				require.NoError(t, err)
+				value, err := message.Value.Encode()
+				require.NoError(t, err)
+				batch := test.unmarshal(t, value)
+				for _, span := range batch.Spans {
+					assert.Equal(t, string(key), span.TraceID.String())
+				}
+				spanCounts[string(key)] = len(batch.Spans)
+			}
+			keyA, err := jaegerproto.TraceIDFromBytes(traceA[:])
+			require.NoError(t, err)
+			keyB, err := jaegerproto.TraceIDFromBytes(traceB[:])
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{keyA.String(): 2, keyB.String(): 1}, spanCounts)
+		})
+	}
+}
+
+func TestJaegerMarshaler_tombstoneAttribute(t *testing.T) {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	traceA := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	traceB := [16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	deleted := spans.AppendEmpty()
+	deleted.SetName("deleted")
+	deleted.SetTraceID(traceA)
+	deleted.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	deleted.Attributes().PutBool("deleted", true)
+	normal := spans.AppendEmpty()
+	normal.SetName("normal")
+	normal.SetTraceID(traceB)
+	normal.SetSpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	normal.Attributes().PutStr("deleted", "false")
+
+	keyA, err := jaegerproto.TraceIDFromBytes(traceA[:])
+	require.NoError(t, err)
+	keyB, err := jaegerproto.TraceIDFromBytes(traceB[:])
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		marshaler TracesMarshaler
+	}{
+		{
+			name:      "per span",
+			marshaler: jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}},
+		},
+		{
+			name:      "coalesced",
+			marshaler: jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}}.withCoalesceByKey(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := test.marshaler.(tombstoneMarshaler).withTombstoneAttribute("deleted")
+			messages, err := m.Marshal(td, "topic")
+			require.NoError(t, err)
+			require.Len(t, messages, 2)
+
+			assert.Equal(t, sarama.ByteEncoder(keyA.String()), messages[0].Key)
+			assert.Nil(t, messages[0].Value)
+
+			assert.Equal(t, sarama.ByteEncoder(keyB.String()), messages[1].Key)
+			assert.NotNil(t, messages[1].Value)
+		})
+	}
+}
@@ -15,49 +15,309 @@
 package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/klauspost/compress/zstd"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+var (
+	errUnrecognizedEncoding       = fmt.Errorf("unrecognized encoding")
+	errBatchDeadlineExceeded      = errors.New("batch deadline exceeded")
+	errAbandonedSendInFlight      = errors.New("a message abandoned at the batch deadline is still being produced")
+	errMissingRawPayloadAttribute = errors.New("raw_payload_attribute must be set with the raw encoding")
+)
+
+const (
+	// otlpProtoVersionHeader is the message header carrying the OTLP proto version of otlp_proto payloads.
+	otlpProtoVersionHeader = "otlp-proto-version"
+	pdataModulePath        = "go.opentelemetry.io/collector/pdata"
+	// contentEncodingHeader is the message header naming the compression applied to pre-compressed payloads.
+	contentEncodingHeader = "content-encoding"
+	preCompressGzip       = "gzip"
+	preCompressZstd       = "zstd"
+)
+
+// otlpProtoVersion is the version of the pdata module the collector was built with,
+// which determines the OTLP proto definitions used to serialize otlp_proto payloads.
+var otlpProtoVersion = pdataModuleVersion()
+
+func pdataModuleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == pdataModulePath {
+				return dep.Version
+			}
+		}
+	}
+	return "unknown"
+}
+
+// applyEncodingByTopic checks that all the encodings of EncodingByTopic are supported by the marshalers,
+// and overrides the encoding of the config with the encoding set for its topic.
+func applyEncodingByTopic[M any](config *Config, marshalers map[string]M) error {
+	for topic, encoding := range config.EncodingByTopic {
+		if _, ok := marshalers[encoding]; !ok {
+			return fmt.Errorf("%w %q for topic %q", errUnrecognizedEncoding, encoding, topic)
+		}
+	}
+	if encoding, ok := config.EncodingByTopic[config.Topic]; ok {
+		config.Encoding = encoding
+	}
+	return nil
+}
+
+// topicMarshaler is the marshaler producing the messages of a topic of EncodingByTopic.
+type topicMarshaler[M any] struct {
+	marshaler           M
+	schemaVersionHeader bool
+}
+
+// newTopicMarshalers returns the marshalers of the topics of EncodingByTopic, so that the encoding is selected
+// from the topic resolved for each batch. configure adapts a marshaler to the config with its encoding, if not nil.
+// The encodings must have been checked by applyEncodingByTopic.
+func newTopicMarshalers[M any](config Config, marshalers map[string]M, configure func(M, Config) (M, error)) (map[string]topicMarshaler[M], error) {
+	if len(config.EncodingByTopic) == 0 {
+		return nil, nil
+	}
+	byTopic := make(map[string]topicMarshaler[M], len(config.EncodingByTopic))
+	for topic, encoding := range config.EncodingByTopic {
+		topicConfig := config
+		topicConfig.Encoding = encoding
+		marshaler := marshalers[encoding]
+		if configure != nil {
+			var err error
+			if marshaler, err = configure(marshaler, topicConfig); err != nil {
+				return nil, err
+			}
+		}
+		byTopic[topic] = topicMarshaler[M]{marshaler: marshaler, schemaVersionHeader: sendSchemaVersionHeader(topicConfig)}
+	}
+	return byTopic, nil
+}
+
+// sendSchemaVersionHeader returns whether the otlp-proto-version header should be added to produced messages.
+func sendSchemaVersionHeader(config Config) bool {
+	return config.SendSchemaVersionHeader && config.Encoding == defaultEncoding
+}
+
+func addSchemaVersionHeader(messages []*sarama.ProducerMessage) {
+	for _, message := range messages {
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(otlpProtoVersionHeader),
+			Value: []byte(otlpProtoVersion),
+		})
+	}
+}
+
+// addTraceIDKey keys the messages without key with the trace ID of the first span of td.
+// Messages are left unkeyed if td has no span.
+func addTraceIDKey(messages []*sarama.ProducerMessage, td ptrace.Traces) {
+	traceID, ok := firstTraceID(td)
+	if !ok {
+		return
+	}
+	for _, message := range messages {
+		if message.Key == nil {
+			message.Key = sarama.ByteEncoder(traceID[:])
+		}
+	}
+}
+
+func firstTraceID(td ptrace.Traces) (pcommon.TraceID, bool) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			if spans := ilss.At(j).Spans(); spans.Len() > 0 {
+				return spans.At(0).TraceID(), true
+			}
+		}
+	}
+	return pcommon.TraceID{}, false
+}
+
+// addKeyHeader copies the key of keyed messages into a header of the given name.
+func addKeyHeader(messages []*sarama.ProducerMessage, header string) error {
+	for _, message := range messages {
+		if message.Key == nil {
+			continue
+		}
+		key, err := message.Key.Encode()
+		if err != nil {
+			return err
+		}
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(header),
+			Value: key,
+		})
+	}
+	return nil
+}
+
+// attributeHeaders returns the headers for the given resource attribute keys, with the string value of the
+// attribute in the first resource having it. Keys absent from all the resources are skipped.
+func attributeHeaders(keys []string, resources []pcommon.Resource) []sarama.RecordHeader {
+	var headers []sarama.RecordHeader
+	for _, key := range keys {
+		for _, resource := range resources {
+			if value, ok := resource.Attributes().Get(key); ok {
+				headers = append(headers, sarama.RecordHeader{
+					Key:   []byte(key),
+					Value: []byte(value.AsString()),
+				})
+				break
+			}
+		}
+	}
+	return headers
+}
+
+// addHeaders appends headers to the headers of messages.
+func addHeaders(messages []*sarama.ProducerMessage, headers []sarama.RecordHeader) {
+	for _, message := range messages {
+		message.Headers = append(message.Headers, headers...)
+	}
+}
+
+// preCompressor compresses message payloads in the exporter, with the content encoding it names.
+type preCompressor struct {
+	encoding string
+	compress func([]byte) ([]byte, error)
+}
+
+// newPreCompressor returns the pre-compressor of the config, or nil if pre-compression is disabled.
+func newPreCompressor(config Config) (*preCompressor, error) {
+	switch config.PreCompress {
+	case preCompressGzip:
+		return &preCompressor{encoding: preCompressGzip, compress: gzipCompress}, nil
+	case preCompressZstd:
+		params := config.Producer.CompressionParams.Zstd
+		options := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if params.Level != 0 {
+			options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(params.Level)))
+		}
+		if params.DictionaryFile != "" {
+			dict, err := os.ReadFile(params.DictionaryFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the zstd dictionary: %w", err)
+			}
+			options = append(options, zstd.WithEncoderDict(dict))
+		}
+		encoder, err := zstd.NewWriter(nil, options...)
+		if err != nil {
+			return nil, err
+		}
+		return &preCompressor{
+			encoding: preCompressZstd,
+			compress: func(payload []byte) ([]byte, error) {
+				return encoder.EncodeAll(payload, nil), nil
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+func gzipCompress(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// preCompressMessages compresses the payload of messages and marks them with the content-encoding header.
+// Messages without payload, such as tombstones, are left untouched.
+func preCompressMessages(messages []*sarama.ProducerMessage, compressor *preCompressor) error {
+	for _, message := range messages {
+		if message.Value == nil {
+			continue
+		}
+		payload, err := message.Value.Encode()
+		if err != nil {
+			return err
+		}
+		compressed, err := compressor.compress(payload)
+		if err != nil {
+			return err
+		}
+		message.Value = sarama.ByteEncoder(compressed)
+		message.Headers = append(message.Headers, sarama.RecordHeader{
+			Key:   []byte(contentEncodingHeader),
+			Value: []byte(compressor.encoding),
+		})
+	}
+	return nil
+}
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler TracesMarshaler
-	logger    *zap.Logger
+	name                  string
+	producer              sarama.SyncProducer
+	topic                 string
+	topicFromAttribute    string
+	marshaler             TracesMarshaler
+	schemaVersionHeader   bool
+	topicMarshalers       map[string]topicMarshaler[TracesMarshaler]
+	partitionByTraceID    bool
+	keyHeader             string
+	preCompressor         *preCompressor
+	batchDeadline         time.Duration
+	abandonedSends        int32
+	deadLetterQueue       *deadLetterQueue
+	headersFromAttributes []string
+	logger                *zap.Logger
 }
 
 type kafkaErrors struct {
 	count int
 	err   string
+	// connection is true if any of the messages failed because of the connection to the brokers
+	connection bool
 }
 
 func (ke kafkaErrors) Error() string {
 	return fmt.Sprintf("Failed to deliver %d messages due to %s", ke.count, ke.err)
 }
 
-func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td ptrace.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
-	}
-	err = e.producer.SendMessages(messages)
+// sendMessages produces messages with producer.
+// If dlq is not nil, the messages rejected by the brokers are produced to the dead letter topic instead of
+// failing the batch, unless the connection to the brokers failed.
+func sendMessages(producer sarama.SyncProducer, messages []*sarama.ProducerMessage, dlq *deadLetterQueue) error {
+	err := producer.SendMessages(messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
 			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
+				connection := false
+				for _, e := range prodErr {
+					connection = connection || isConnectionError(e.Err)
+				}
+				if dlq != nil && !connection {
+					return dlq.send(producer, prodErr)
+				}
+				return kafkaErrors{len(prodErr), prodErr[0].Err.Error(), connection}
 			}
 		}
 		return err
@@ -65,34 +325,185 @@ func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td ptrace.Traces)
 	return nil
 }
 
+// sendMessagesWithin produces messages one at a time with producer like sendMessages, failing with
+// errBatchDeadlineExceeded once deadline has passed. The producer cannot be interrupted: a message still being
+// produced at the deadline is abandoned and may still be delivered. abandoned counts the abandoned messages until
+// they complete, and no message is produced while any is in flight, so that they do not pile up on a slow broker.
+func sendMessagesWithin(producer sarama.SyncProducer, messages []*sarama.ProducerMessage, dlq *deadLetterQueue, deadline time.Duration, abandoned *int32) error {
+	if atomic.LoadInt32(abandoned) > 0 {
+		return fmt.Errorf("produced 0 of %d messages: %w", len(messages), errAbandonedSendInFlight)
+	}
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+	for i, message := range messages {
+		select {
+		case <-timer.C:
+			return fmt.Errorf("produced %d of %d messages: %w", i, len(messages), errBatchDeadlineExceeded)
+		default:
+		}
+		result := make(chan error, 1)
+		go func(message *sarama.ProducerMessage) {
+			result <- sendMessages(producer, []*sarama.ProducerMessage{message}, dlq)
+		}(message)
+		select {
+		case err := <-result:
+			if err != nil {
+				return fmt.Errorf("produced %d of %d messages: %w", i, len(messages), err)
+			}
+		case <-timer.C:
+			atomic.AddInt32(abandoned, 1)
+			go func() {
+				<-result
+				atomic.AddInt32(abandoned, -1)
+			}()
+			return fmt.Errorf("produced %d of %d messages: %w", i, len(messages), errBatchDeadlineExceeded)
+		}
+	}
+	return nil
+}
+
+// isConnectionError returns whether err was caused by the connection to the brokers.
+func isConnectionError(err error) bool {
+	var ke kafkaErrors
+	if errors.As(err, &ke) {
+		return ke.connection
+	}
+	var netErr net.Error
+	return errors.Is(err, sarama.ErrOutOfBrokers) ||
+		errors.Is(err, sarama.ErrNotConnected) ||
+		errors.Is(err, sarama.ErrClosedClient) ||
+		errors.As(err, &netErr)
+}
+
+func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td ptrace.Traces) error {
+	topic := e.topic
+	if e.topicFromAttribute != "" {
+		value, found := tracesAttribute(td, e.topicFromAttribute)
+		var err error
+		if topic, err = batchTopic(topic, value, found); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
+	messages, err := e.tracesMessages(td, topic)
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	recordMessageBytes(e.name, messages)
+	if e.batchDeadline > 0 {
+		err = sendMessagesWithin(e.producer, messages, e.deadLetterQueue, e.batchDeadline, &e.abandonedSends)
+	} else {
+		err = sendMessages(e.producer, messages, e.deadLetterQueue)
+	}
+	recordSendResult(e.name, err)
+	return err
+}
+
+// tracesMessages marshals td into messages to topic.
+func (e *kafkaTracesProducer) tracesMessages(td ptrace.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	marshaler, schemaVersionHeader := e.marshaler, e.schemaVersionHeader
+	if m, ok := e.topicMarshalers[topic]; ok {
+		marshaler, schemaVersionHeader = m.marshaler, m.schemaVersionHeader
+	}
+	messages, err := marshaler.Marshal(td, topic)
+	if err != nil {
+		return nil, err
+	}
+	if e.partitionByTraceID {
+		addTraceIDKey(messages, td)
+	}
+	if schemaVersionHeader {
+		addSchemaVersionHeader(messages)
+	}
+	if e.keyHeader != "" {
+		if err = addKeyHeader(messages, e.keyHeader); err != nil {
+			return nil, err
+		}
+	}
+	if len(e.headersFromAttributes) > 0 {
+		resources := make([]pcommon.Resource, td.ResourceSpans().Len())
+		for i := range resources {
+			resources[i] = td.ResourceSpans().At(i).Resource()
+		}
+		addHeaders(messages, attributeHeaders(e.headersFromAttributes, resources))
+	}
+	if e.preCompressor != nil {
+		if err = preCompressMessages(messages, e.preCompressor); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
 func (e *kafkaTracesProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler MetricsMarshaler
-	logger    *zap.Logger
+	name                  string
+	producer              sarama.SyncProducer
+	topic                 string
+	topicFromAttribute    string
+	marshaler             MetricsMarshaler
+	schemaVersionHeader   bool
+	topicMarshalers       map[string]topicMarshaler[MetricsMarshaler]
+	preCompressor         *preCompressor
+	batchDeadline         time.Duration
+	abandonedSends        int32
+	deadLetterQueue       *deadLetterQueue
+	headersFromAttributes []string
+	logger                *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pmetric.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
+	topic := e.topic
+	if e.topicFromAttribute != "" {
+		value, found := metricsAttribute(md, e.topicFromAttribute)
+		var err error
+		if topic, err = batchTopic(topic, value, found); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
+	messages, err := e.metricsMessages(md, topic)
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	recordMessageBytes(e.name, messages)
+	if e.batchDeadline > 0 {
+		err = sendMessagesWithin(e.producer, messages, e.deadLetterQueue, e.batchDeadline, &e.abandonedSends)
+	} else {
+		err = sendMessages(e.producer, messages, e.deadLetterQueue)
+	}
+	recordSendResult(e.name, err)
+	return err
+}
+
+// metricsMessages marshals md into messages to topic.
+func (e *kafkaMetricsProducer) metricsMessages(md pmetric.Metrics, topic string) ([]*sarama.ProducerMessage, error) {
+	marshaler, schemaVersionHeader := e.marshaler, e.schemaVersionHeader
+	if m, ok := e.topicMarshalers[topic]; ok {
+		marshaler, schemaVersionHeader = m.marshaler, m.schemaVersionHeader
+	}
+	messages, err := marshaler.Marshal(md, topic)
 	if err != nil {
-		var prodErr sarama.ProducerErrors
-		if errors.As(err, &prodErr) {
-			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
-			}
+		return nil, err
+	}
+	if schemaVersionHeader {
+		addSchemaVersionHeader(messages)
+	}
+	if len(e.headersFromAttributes) > 0 {
+		resources := make([]pcommon.Resource, md.ResourceMetrics().Len())
+		for i := range resources {
+			resources[i] = md.ResourceMetrics().At(i).Resource()
 		}
-		return err
+		addHeaders(messages, attributeHeaders(e.headersFromAttributes, resources))
 	}
-	return nil
+	if e.preCompressor != nil {
+		if err = preCompressMessages(messages, e.preCompressor); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
 }
 
 func (e *kafkaMetricsProducer) Close(context.Context) error {
@@ -101,35 +512,99 @@ func (e *kafkaMetricsProducer) Close(context.Context) error {
 
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler LogsMarshaler
-	logger    *zap.Logger
+	name                  string
+	producer              sarama.SyncProducer
+	topic                 string
+	topicFromAttribute    string
+	marshaler             LogsMarshaler
+	schemaVersionHeader   bool
+	topicMarshalers       map[string]topicMarshaler[LogsMarshaler]
+	preCompressor         *preCompressor
+	batchDeadline         time.Duration
+	abandonedSends        int32
+	deadLetterQueue       *deadLetterQueue
+	headersFromAttributes []string
+	logger                *zap.Logger
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
+	topic := e.topic
+	if e.topicFromAttribute != "" {
+		value, found := logsAttribute(ld, e.topicFromAttribute)
+		var err error
+		if topic, err = batchTopic(topic, value, found); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
+	messages, err := e.logsMessages(ld, topic)
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	recordMessageBytes(e.name, messages)
+	if e.batchDeadline > 0 {
+		err = sendMessagesWithin(e.producer, messages, e.deadLetterQueue, e.batchDeadline, &e.abandonedSends)
+	} else {
+		err = sendMessages(e.producer, messages, e.deadLetterQueue)
+	}
+	recordSendResult(e.name, err)
+	return err
+}
+
+// logsMessages marshals ld into messages to topic.
+func (e *kafkaLogsProducer) logsMessages(ld plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	marshaler, schemaVersionHeader := e.marshaler, e.schemaVersionHeader
+	if m, ok := e.topicMarshalers[topic]; ok {
+		marshaler, schemaVersionHeader = m.marshaler, m.schemaVersionHeader
+	}
+	messages, err := marshaler.Marshal(ld, topic)
 	if err != nil {
-		var prodErr sarama.ProducerErrors
-		if errors.As(err, &prodErr) {
-			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
-			}
+		return nil, err
+	}
+	if schemaVersionHeader {
+		addSchemaVersionHeader(messages)
+	}
+	if len(e.headersFromAttributes) > 0 {
+		resources := make([]pcommon.Resource, ld.ResourceLogs().Len())
+		for i := range resources {
+			resources[i] = ld.ResourceLogs().At(i).Resource()
 		}
-		return err
+		addHeaders(messages, attributeHeaders(e.headersFromAttributes, resources))
 	}
-	return nil
+	if e.preCompressor != nil {
+		if err = preCompressMessages(messages, e.preCompressor); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
 }
 
 func (e *kafkaLogsProducer) Close(context.Context) error {
 	return e.producer.Close()
 }
 
-func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
+func newSaramaProducer(config Config, logger *zap.Logger) (sarama.SyncProducer, error) {
+	if config.SaramaDebugLogging {
+		configureSaramaLogger(logger)
+	}
+
+	c, err := newSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewSyncProducer(config.Brokers, c)
+	if err != nil {
+		if isConnectionError(err) {
+			recordConnectionError(config.ID().Name())
+		}
+		return nil, err
+	}
+	recordConnected(config.ID().Name())
+	return newLimitedProducer(producer, config.MaxInFlight), nil
+}
+
+// newSaramaConfig returns the configuration of the sarama producer.
+func newSaramaConfig(config Config) (*sarama.Config, error) {
 	c := sarama.NewConfig()
 	// These setting are required by the sarama.SyncProducer implementation.
 	c.Producer.Return.Successes = true
@@ -142,6 +617,10 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
 	c.Producer.MaxMessageBytes = config.Producer.MaxMessageBytes
 	c.Producer.Flush.MaxMessages = config.Producer.FlushMaxMessages
+	c.Producer.Partitioner = keyHashPartitioner(config.KeyHashAlgorithm)
+	if config.StickyPartitioning {
+		c.Producer.Partitioner = newStickyHashPartitioner(c.Producer.Partitioner)
+	}
 
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
@@ -160,66 +639,178 @@ func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 		return nil, err
 	}
 	c.Producer.Compression = compression
+	if compression == sarama.CompressionZSTD && config.Producer.CompressionParams.Zstd.Level != 0 {
+		c.Producer.CompressionLevel = config.Producer.CompressionParams.Zstd.Level
+	}
 
-	producer, err := sarama.NewSyncProducer(config.Brokers, c)
+	if config.Producer.Idempotent {
+		// required by sarama for the idempotent producer
+		c.Producer.Idempotent = true
+		c.Producer.RequiredAcks = sarama.WaitForAll
+		c.Net.MaxOpenRequests = 1
+	}
+	return c, nil
+}
+
+// configureSaramaLogger redirects the global Sarama logger, discarding logs by default,
+// to the given logger at debug level.
+func configureSaramaLogger(logger *zap.Logger) {
+	stdLogger, err := zap.NewStdLogAt(logger.Named("sarama"), zapcore.DebugLevel)
 	if err != nil {
-		return nil, err
+		logger.Warn("failed to redirect the sarama logger", zap.Error(err))
+		return
 	}
-	return producer, nil
+	sarama.Logger = stdLogger
 }
 
 func newMetricsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]MetricsMarshaler) (*kafkaMetricsProducer, error) {
+	if err := applyEncodingByTopic(&config, marshalers); err != nil {
+		return nil, err
+	}
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	topicMarshalers, err := newTopicMarshalers(config, marshalers, nil)
+	if err != nil {
+		return nil, err
+	}
+	compressor, err := newPreCompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaMetricsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		name:                  config.ID().Name(),
+		producer:              producer,
+		topic:                 config.Topic,
+		topicFromAttribute:    config.TopicFromAttribute,
+		marshaler:             marshaler,
+		schemaVersionHeader:   sendSchemaVersionHeader(config),
+		topicMarshalers:       topicMarshalers,
+		preCompressor:         compressor,
+		batchDeadline:         config.BatchDeadline,
+		deadLetterQueue:       newDeadLetterQueue(config),
+		headersFromAttributes: config.HeadersFromAttributes,
+		logger:                set.Logger,
 	}, nil
 
 }
 
+// configureTracesMarshaler adapts the marshaler of the encoding of the config to the other settings of the config.
+func configureTracesMarshaler(marshaler TracesMarshaler, config Config, logger *zap.Logger) (TracesMarshaler, error) {
+	if raw, ok := marshaler.(rawTracesMarshaler); ok {
+		if config.RawPayloadAttribute == "" {
+			return nil, errMissingRawPayloadAttribute
+		}
+		marshaler = raw.withPayloadAttribute(config.RawPayloadAttribute, config.ID().Name(), logger)
+	}
+	if config.CoalesceByKey {
+		if coalescing, ok := marshaler.(keyCoalescingMarshaler); ok {
+			marshaler = coalescing.withCoalesceByKey()
+		} else {
+			logger.Info("coalesce_by_key has no effect with this encoding since it produces a single message per batch", zap.String("encoding", config.Encoding))
+		}
+	}
+	if config.TombstoneAttribute != "" {
+		if tombstoning, ok := marshaler.(tombstoneMarshaler); ok {
+			marshaler = tombstoning.withTombstoneAttribute(config.TombstoneAttribute)
+		} else {
+			logger.Info("tombstone_attribute has no effect with this encoding since its messages are not keyed", zap.String("encoding", config.Encoding))
+		}
+	}
+	if config.EmitKeyAsHeader != "" && !config.PartitionTracesByID {
+		if _, ok := marshaler.(tombstoneMarshaler); !ok {
+			logger.Info("emit_key_as_header has no effect with this encoding since its messages are not keyed", zap.String("encoding", config.Encoding))
+		}
+	}
+	return marshaler, nil
+}
+
 // newTracesExporter creates Kafka exporter.
 func newTracesExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]TracesMarshaler) (*kafkaTracesProducer, error) {
+	if err := applyEncodingByTopic(&config, marshalers); err != nil {
+		return nil, err
+	}
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	configure := func(marshaler TracesMarshaler, config Config) (TracesMarshaler, error) {
+		return configureTracesMarshaler(marshaler, config, set.Logger)
+	}
+	marshaler, err := configure(marshaler, config)
+	if err != nil {
+		return nil, err
+	}
+	topicMarshalers, err := newTopicMarshalers(config, marshalers, configure)
+	if err != nil {
+		return nil, err
+	}
+	compressor, err := newPreCompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		name:                  config.ID().Name(),
+		producer:              producer,
+		topic:                 config.Topic,
+		topicFromAttribute:    config.TopicFromAttribute,
+		marshaler:             marshaler,
+		schemaVersionHeader:   sendSchemaVersionHeader(config),
+		topicMarshalers:       topicMarshalers,
+		partitionByTraceID:    config.PartitionTracesByID,
+		keyHeader:             config.EmitKeyAsHeader,
+		preCompressor:         compressor,
+		batchDeadline:         config.BatchDeadline,
+		deadLetterQueue:       newDeadLetterQueue(config),
+		headersFromAttributes: config.HeadersFromAttributes,
+		logger:                set.Logger,
 	}, nil
 }
 
 func newLogsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]LogsMarshaler) (*kafkaLogsProducer, error) {
+	if err := applyEncodingByTopic(&config, marshalers); err != nil {
+		return nil, err
+	}
 	marshaler := marshalers[config.Encoding]
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(config)
+	topicMarshalers, err := newTopicMarshalers(config, marshalers, nil)
+	if err != nil {
+		return nil, err
+	}
+	compressor, err := newPreCompressor(config)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := newSaramaProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaLogsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		name:                  config.ID().Name(),
+		producer:              producer,
+		topic:                 config.Topic,
+		topicFromAttribute:    config.TopicFromAttribute,
+		marshaler:             marshaler,
+		schemaVersionHeader:   sendSchemaVersionHeader(config),
+		topicMarshalers:       topicMarshalers,
+		preCompressor:         compressor,
+		batchDeadline:         config.BatchDeadline,
+		deadLetterQueue:       newDeadLetterQueue(config),
+		headersFromAttributes: config.HeadersFromAttributes,
+		logger:                set.Logger,
 	}, nil
 
 }
@@ -15,20 +15,34 @@
 package kafkaexporter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/Shopify/sarama/mocks"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
 )
@@ -40,6 +54,32 @@ func TestNewExporter_err_version(t *testing.T) {
 	assert.Nil(t, texp)
 }
 
+func TestNewExporter_sarama_debug_logging(t *testing.T) {
+	original := sarama.Logger
+	defer func() { sarama.Logger = original }()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	set := componenttest.NewNopExporterCreateSettings()
+	set.Logger = zap.New(core)
+
+	c := Config{ProtocolVersion: "0.0.0", Encoding: defaultEncoding}
+	_, err := newTracesExporter(c, set, tracesMarshalers())
+	assert.Error(t, err)
+	assert.Equal(t, original, sarama.Logger)
+
+	c.SaramaDebugLogging = true
+	_, err = newTracesExporter(c, set, tracesMarshalers())
+	assert.Error(t, err)
+	require.NotEqual(t, original, sarama.Logger)
+
+	sarama.Logger.Printf("connected to broker %s", "localhost:9092")
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.DebugLevel, entry.Level)
+	assert.Equal(t, "sarama", entry.LoggerName)
+	assert.Equal(t, "connected to broker localhost:9092", entry.Message)
+}
+
 func TestNewExporter_err_encoding(t *testing.T) {
 	c := Config{Encoding: "foo"}
 	texp, err := newTracesExporter(c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
@@ -47,6 +87,19 @@ func TestNewExporter_err_encoding(t *testing.T) {
 	assert.Nil(t, texp)
 }
 
+func TestNewExporter_err_encoding_by_topic(t *testing.T) {
+	c := Config{Encoding: defaultEncoding, Topic: "spans", EncodingByTopic: map[string]string{"other": "foo"}}
+	texp, err := newTracesExporter(c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.Nil(t, texp)
+
+	// jaeger encodings are only supported by traces
+	c = Config{Encoding: defaultEncoding, Topic: "metrics", EncodingByTopic: map[string]string{"spans": "jaeger_json"}}
+	mexp, err := newMetricsExporter(c, componenttest.NewNopExporterCreateSettings(), metricsMarshalers())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+	assert.Nil(t, mexp)
+}
+
 func TestNewMetricsExporter_err_version(t *testing.T) {
 	c := Config{ProtocolVersion: "0.0.0", Encoding: defaultEncoding}
 	mexp, err := newMetricsExporter(c, componenttest.NewNopExporterCreateSettings(), metricsMarshalers())
@@ -93,9 +146,11 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
 		Authentication: Authentication{
-			TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{
-					CAFile: "/doesnotexist",
+			TLS: &TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{
+						CAFile: "/doesnotexist",
+					},
 				},
 			},
 		},
@@ -122,6 +177,63 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 
 }
 
+func TestNewExporter_coalesceByKey(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Brokers = []string{"invalid:9092"}
+	c.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	c.Metadata.Full = false
+	c.Encoding = "jaeger_proto"
+	c.CoalesceByKey = true
+	texp, err := newTracesExporter(*c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, texp.Close(context.Background()))
+	})
+	assert.True(t, texp.marshaler.(jaegerMarshaler).coalesceByKey)
+}
+
+func TestNewExporter_tombstoneAttribute(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Brokers = []string{"invalid:9092"}
+	c.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	c.Metadata.Full = false
+	c.Encoding = "jaeger_proto"
+	c.CoalesceByKey = true
+	c.TombstoneAttribute = "deleted"
+	texp, err := newTracesExporter(*c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, texp.Close(context.Background()))
+	})
+	assert.True(t, texp.marshaler.(jaegerMarshaler).coalesceByKey)
+	assert.Equal(t, "deleted", texp.marshaler.(jaegerMarshaler).tombstoneAttribute)
+}
+
+func TestNewExporter_err_rawPayloadAttribute(t *testing.T) {
+	c := Config{Encoding: "raw"}
+	texp, err := newTracesExporter(c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
+	assert.EqualError(t, err, errMissingRawPayloadAttribute.Error())
+	assert.Nil(t, texp)
+}
+
+func TestNewExporter_rawPayloadAttribute(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	c.Brokers = []string{"invalid:9092"}
+	c.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	c.Metadata.Full = false
+	c.Encoding = "raw"
+	c.RawPayloadAttribute = "payload"
+	texp, err := newTracesExporter(*c, componenttest.NewNopExporterCreateSettings(), tracesMarshalers())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, texp.Close(context.Background()))
+	})
+	assert.Equal(t, "payload", texp.marshaler.(rawTracesMarshaler).payloadAttribute)
+}
+
 func TestNewExporter_err_compression(t *testing.T) {
 	c := Config{
 		Encoding: defaultEncoding,
@@ -151,6 +263,331 @@ func TestTracesPusher(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTracesPusher_encodingByTopic(t *testing.T) {
+	encodingByTopic := map[string]string{
+		"spans_json":  "otlp_json",
+		"spans_proto": "otlp_proto",
+	}
+	tests := []struct {
+		topic     string
+		unmarshal ptrace.Unmarshaler
+	}{
+		{topic: "spans_json", unmarshal: &ptrace.JSONUnmarshaler{}},
+		{topic: "spans_proto", unmarshal: &ptrace.ProtoUnmarshaler{}},
+		// topics without override use the global encoding
+		{topic: "spans", unmarshal: &ptrace.JSONUnmarshaler{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			config := Config{Topic: tt.topic, Encoding: "otlp_json", EncodingByTopic: encodingByTopic}
+			marshalers := tracesMarshalers()
+			require.NoError(t, applyEncodingByTopic(&config, marshalers))
+
+			td := testdata.GenerateTracesTwoSpansSameResource()
+			producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				assert.Equal(t, tt.topic, msg.Topic)
+				value, err := msg.Value.Encode()
+				require.NoError(t, err)
+				received, err := tt.unmarshal.UnmarshalTraces(value)
+				require.NoError(t, err)
+				assert.Equal(t, td, received)
+				return nil
+			})
+
+			p := kafkaTracesProducer{
+				producer:  producer,
+				topic:     config.Topic,
+				marshaler: marshalers[config.Encoding],
+			}
+			t.Cleanup(func() {
+				require.NoError(t, p.Close(context.Background()))
+			})
+			require.NoError(t, p.tracesPusher(context.Background(), td))
+		})
+	}
+}
+
+func TestTracesPusher_schemaVersionHeader(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		require.Len(t, msg.Headers, 1)
+		assert.Equal(t, otlpProtoVersionHeader, string(msg.Headers[0].Key))
+		assert.Equal(t, otlpProtoVersion, string(msg.Headers[0].Value))
+		return nil
+	})
+
+	p := kafkaTracesProducer{
+		producer:            producer,
+		marshaler:           newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		schemaVersionHeader: true,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	require.NoError(t, err)
+}
+
+func TestTracesPusher_preCompress(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	marshaler := newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding)
+	expected, err := marshaler.Marshal(td, "")
+	require.NoError(t, err)
+	expectedPayload, err := expected[0].Value.Encode()
+	require.NoError(t, err)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		require.Len(t, msg.Headers, 1)
+		assert.Equal(t, contentEncodingHeader, string(msg.Headers[0].Key))
+		assert.Equal(t, "gzip", string(msg.Headers[0].Value))
+		payload, err := msg.Value.Encode()
+		require.NoError(t, err)
+		reader, err := gzip.NewReader(bytes.NewReader(payload))
+		require.NoError(t, err)
+		decompressed, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, expectedPayload, decompressed)
+		return nil
+	})
+
+	p := kafkaTracesProducer{
+		producer:      producer,
+		marshaler:     marshaler,
+		preCompressor: &preCompressor{encoding: preCompressGzip, compress: gzipCompress},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err = p.tracesPusher(context.Background(), td)
+	require.NoError(t, err)
+}
+
+func TestPreCompressMessages_zstd(t *testing.T) {
+	dictionaryFile := filepath.Join("testdata", "spans.zstd.dict")
+	dict, err := os.ReadFile(dictionaryFile)
+	require.NoError(t, err)
+	payload := []byte(`{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"checkout"}}]}}]}`)
+
+	tests := []struct {
+		name   string
+		params ZstdCompressionParams
+	}{
+		{name: "default"},
+		{name: "level", params: ZstdCompressionParams{Level: 19}},
+		{name: "dictionary", params: ZstdCompressionParams{Level: 3, DictionaryFile: dictionaryFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{PreCompress: preCompressZstd}
+			config.Producer.CompressionParams.Zstd = tt.params
+			compressor, err := newPreCompressor(config)
+			require.NoError(t, err)
+
+			messages := []*sarama.ProducerMessage{{Topic: "otlp_spans", Value: sarama.ByteEncoder(payload)}}
+			require.NoError(t, preCompressMessages(messages, compressor))
+			require.Len(t, messages[0].Headers, 1)
+			assert.Equal(t, contentEncodingHeader, string(messages[0].Headers[0].Key))
+			assert.Equal(t, "zstd", string(messages[0].Headers[0].Value))
+
+			compressed, err := messages[0].Value.Encode()
+			require.NoError(t, err)
+			decoder, err := zstd.NewReader(nil, zstd.WithDecoderDicts(dict))
+			require.NoError(t, err)
+			defer decoder.Close()
+			decompressed, err := decoder.DecodeAll(compressed, nil)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+
+			if tt.params.DictionaryFile != "" {
+				// the frame requires the dictionary
+				plainDecoder, err := zstd.NewReader(nil)
+				require.NoError(t, err)
+				defer plainDecoder.Close()
+				_, err = plainDecoder.DecodeAll(compressed, nil)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewSaramaConfig_idempotent(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Producer.Idempotent = true
+
+	c, err := newSaramaConfig(*config)
+	require.NoError(t, err)
+	assert.True(t, c.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForAll, c.Producer.RequiredAcks)
+	assert.Equal(t, 1, c.Net.MaxOpenRequests)
+	assert.NoError(t, c.Validate())
+
+	config.Producer.Idempotent = false
+	c, err = newSaramaConfig(*config)
+	require.NoError(t, err)
+	assert.False(t, c.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForLocal, c.Producer.RequiredAcks)
+	assert.Equal(t, sarama.NewConfig().Net.MaxOpenRequests, c.Net.MaxOpenRequests)
+}
+
+func TestNewPreCompressor(t *testing.T) {
+	compressor, err := newPreCompressor(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, compressor)
+
+	config := Config{PreCompress: preCompressZstd}
+	config.Producer.CompressionParams.Zstd.DictionaryFile = filepath.Join("testdata", "missing.dict")
+	_, err = newPreCompressor(config)
+	assert.ErrorContains(t, err, "failed to read the zstd dictionary")
+}
+
+func TestTracesPusher_keyHeader(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	for i := 0; i < 2; i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			key, err := msg.Key.Encode()
+			require.NoError(t, err)
+			require.Len(t, msg.Headers, 1)
+			assert.Equal(t, "trace-id", string(msg.Headers[0].Key))
+			assert.Equal(t, key, msg.Headers[0].Value)
+			return nil
+		})
+	}
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		marshaler: jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}},
+		keyHeader: "trace-id",
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), td)
+	require.NoError(t, err)
+}
+
+func TestTracesPusher_partitionByTraceID(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetTraceID(traceID)
+	td.ResourceSpans().At(1).ScopeSpans().At(0).Spans().At(0).SetTraceID(pcommon.TraceID([16]byte{16, 15, 14}))
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			c := sarama.NewConfig()
+			producer := mocks.NewSyncProducer(t, c)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				if !enabled {
+					assert.Nil(t, msg.Key)
+					return nil
+				}
+				require.NotNil(t, msg.Key)
+				key, err := msg.Key.Encode()
+				require.NoError(t, err)
+				assert.Equal(t, traceID[:], key)
+				return nil
+			})
+
+			p := kafkaTracesProducer{
+				producer:           producer,
+				marshaler:          newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+				partitionByTraceID: enabled,
+			}
+			t.Cleanup(func() {
+				require.NoError(t, p.Close(context.Background()))
+			})
+			require.NoError(t, p.tracesPusher(context.Background(), td))
+		})
+	}
+}
+
+func TestAddTraceIDKey(t *testing.T) {
+	keyed := &sarama.ProducerMessage{Key: sarama.StringEncoder("key")}
+	unkeyed := &sarama.ProducerMessage{}
+	addTraceIDKey([]*sarama.ProducerMessage{keyed, unkeyed}, ptrace.NewTraces())
+	assert.Nil(t, unkeyed.Key, "traces without span")
+
+	td := testdata.GenerateTracesOneSpan()
+	traceID := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+	addTraceIDKey([]*sarama.ProducerMessage{keyed, unkeyed}, td)
+	assert.Equal(t, sarama.StringEncoder("key"), keyed.Key)
+	assert.Equal(t, sarama.ByteEncoder(traceID[:]), unkeyed.Key)
+}
+
+// expectAttributeHeaders returns a message checker expecting the headers created from the resource attributes
+func expectAttributeHeaders(t *testing.T, expected map[string]string) func(*sarama.ProducerMessage) error {
+	return func(msg *sarama.ProducerMessage) error {
+		headers := map[string]string{}
+		for _, header := range msg.Headers {
+			headers[string(header.Key)] = string(header.Value)
+		}
+		assert.Equal(t, expected, headers)
+		return nil
+	}
+}
+
+func TestTracesPusher_headersFromAttributes(t *testing.T) {
+	td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+	td.ResourceSpans().At(1).Resource().Attributes().PutStr("tenant", "acme")
+	td.ResourceSpans().At(1).Resource().Attributes().PutInt("shard", 3)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectAttributeHeaders(t, map[string]string{
+		"resource-attr": "resource-attr-val-1",
+		"tenant":        "acme",
+		"shard":         "3",
+	}))
+
+	p := kafkaTracesProducer{
+		producer:              producer,
+		marshaler:             newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		headersFromAttributes: []string{"resource-attr", "tenant", "missing", "shard"},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+}
+
+func TestAddKeyHeader_unkeyed(t *testing.T) {
+	messages := []*sarama.ProducerMessage{{Topic: "otlp_spans", Value: sarama.ByteEncoder("value")}}
+	require.NoError(t, addKeyHeader(messages, "trace-id"))
+	assert.Empty(t, messages[0].Headers)
+}
+
+func TestPreCompressMessages_tombstone(t *testing.T) {
+	messages := []*sarama.ProducerMessage{{Topic: "otlp_spans", Key: sarama.ByteEncoder("key")}}
+	require.NoError(t, preCompressMessages(messages, &preCompressor{encoding: preCompressGzip, compress: gzipCompress}))
+	assert.Nil(t, messages[0].Value)
+	assert.Empty(t, messages[0].Headers)
+}
+
+func TestSendSchemaVersionHeader(t *testing.T) {
+	tests := []struct {
+		encoding string
+		enabled  bool
+		expected bool
+	}{
+		{encoding: "otlp_proto", enabled: true, expected: true},
+		{encoding: "otlp_proto", enabled: false, expected: false},
+		{encoding: "otlp_json", enabled: true, expected: false},
+		{encoding: "jaeger_proto", enabled: true, expected: false},
+		{encoding: "raw", enabled: true, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s_%t", tt.encoding, tt.enabled), func(t *testing.T) {
+			c := Config{Encoding: tt.encoding, SendSchemaVersionHeader: tt.enabled}
+			assert.Equal(t, tt.expected, sendSchemaVersionHeader(c))
+		})
+	}
+}
+
 func TestTracesPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -170,6 +607,245 @@ func TestTracesPusher_err(t *testing.T) {
 	assert.EqualError(t, err, expErr.Error())
 }
 
+// connectionMetrics returns the connection errors and connected state recorded for the exporter instance
+func connectionMetrics(t *testing.T, name string) (float64, float64) {
+	var connectionErrors, connected float64
+	rows, err := view.RetrieveData(statConnectionErrors.Name())
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == name {
+			connectionErrors = row.Data.(*view.SumData).Value
+		}
+	}
+	rows, err = view.RetrieveData(statConnected.Name())
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == name {
+			connected = row.Data.(*view.LastValueData).Value
+		}
+	}
+	return connectionErrors, connected
+}
+
+func TestTracesPusher_connectionMetrics(t *testing.T) {
+	// the views may already be registered by the factory
+	_ = view.Register(MetricViews()...)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	producer.ExpectSendMessageAndFail(sarama.ErrMessageSizeTooLarge)
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		name:      t.Name(),
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	td := testdata.GenerateTracesTwoSpansSameResource()
+
+	assert.Error(t, p.tracesPusher(context.Background(), td))
+	connectionErrors, connected := connectionMetrics(t, t.Name())
+	assert.Equal(t, 1.0, connectionErrors)
+	assert.Equal(t, 0.0, connected)
+
+	// a rejected message is not a connection error
+	assert.Error(t, p.tracesPusher(context.Background(), td))
+	connectionErrors, connected = connectionMetrics(t, t.Name())
+	assert.Equal(t, 1.0, connectionErrors)
+	assert.Equal(t, 0.0, connected)
+
+	assert.NoError(t, p.tracesPusher(context.Background(), td))
+	connectionErrors, connected = connectionMetrics(t, t.Name())
+	assert.Equal(t, 1.0, connectionErrors)
+	assert.Equal(t, 1.0, connected)
+}
+
+func TestTracesPusher_messageBytes(t *testing.T) {
+	// the views may already be registered by the factory
+	_ = view.Register(MetricViews()...)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		name:      t.Name(),
+		producer:  producer,
+		marshaler: newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		logger:    zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+
+	expected, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(td)
+	require.NoError(t, err)
+	rows, err := view.RetrieveData(statMessageBytes.Name())
+	require.NoError(t, err)
+	var distribution *view.DistributionData
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == t.Name() {
+			distribution = row.Data.(*view.DistributionData)
+		}
+	}
+	require.NotNil(t, distribution)
+	assert.Equal(t, int64(1), distribution.Count)
+	assert.Equal(t, float64(len(expected)), distribution.Sum())
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(sarama.ErrOutOfBrokers))
+	assert.True(t, isConnectionError(fmt.Errorf("produced 0 of 1 messages: %w", sarama.ErrNotConnected)))
+	assert.True(t, isConnectionError(kafkaErrors{count: 1, err: "closed", connection: true}))
+	assert.False(t, isConnectionError(kafkaErrors{count: 1, err: "too large"}))
+	assert.False(t, isConnectionError(sarama.ErrMessageSizeTooLarge))
+	assert.False(t, isConnectionError(errBatchDeadlineExceeded))
+}
+
+// slowSyncProducer delays every call producing messages and records the produced messages
+type slowSyncProducer struct {
+	sarama.SyncProducer
+	delay time.Duration
+
+	mu       sync.Mutex
+	produced []*sarama.ProducerMessage
+}
+
+func (p *slowSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	time.Sleep(p.delay)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.produced = append(p.produced, msgs...)
+	return nil
+}
+
+func (p *slowSyncProducer) producedCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.produced)
+}
+
+func TestTracesPusher_batchDeadline(t *testing.T) {
+	producer := &slowSyncProducer{delay: 100 * time.Millisecond}
+	p := kafkaTracesProducer{
+		producer:      producer,
+		marshaler:     jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}},
+		batchDeadline: 150 * time.Millisecond,
+		logger:        zap.NewNop(),
+	}
+
+	// a message is produced per span, the second one is abandoned at the deadline
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 3; i++ {
+		spans.AppendEmpty().SetName(fmt.Sprintf("span-%d", i))
+	}
+	err := p.tracesPusher(context.Background(), td)
+	assert.ErrorIs(t, err, errBatchDeadlineExceeded)
+	assert.EqualError(t, err, "produced 1 of 3 messages: batch deadline exceeded")
+	assert.False(t, consumererror.IsPermanent(err))
+
+	// no message is produced while the abandoned one is in flight
+	err = p.tracesPusher(context.Background(), td)
+	assert.ErrorIs(t, err, errAbandonedSendInFlight)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, producer.producedCount())
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&p.abandonedSends) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, producer.producedCount())
+}
+
+func TestTracesPusher_batchDeadline_slowSend(t *testing.T) {
+	producer := &slowSyncProducer{delay: time.Second}
+	p := kafkaTracesProducer{
+		producer:      producer,
+		marshaler:     newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		batchDeadline: 50 * time.Millisecond,
+		logger:        zap.NewNop(),
+	}
+
+	// a single send is bounded by the deadline as well
+	start := time.Now()
+	err := p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.ErrorIs(t, err, errBatchDeadlineExceeded)
+	assert.EqualError(t, err, "produced 0 of 1 messages: batch deadline exceeded")
+}
+
+func TestTracesPusher_batchDeadline_messageLayout(t *testing.T) {
+	producer := &slowSyncProducer{}
+	p := kafkaTracesProducer{
+		producer:      producer,
+		marshaler:     newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+		batchDeadline: time.Minute,
+		logger:        zap.NewNop(),
+	}
+
+	// the batch is still produced as a single message
+	td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+	require.Equal(t, 1, producer.producedCount())
+	value, err := producer.produced[0].Value.Encode()
+	require.NoError(t, err)
+	received, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(value)
+	require.NoError(t, err)
+	assert.Equal(t, td, received)
+}
+
+func TestMetricsDataPusher_batchDeadline(t *testing.T) {
+	producer := &slowSyncProducer{delay: time.Second}
+	p := kafkaMetricsProducer{
+		producer:      producer,
+		marshaler:     newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding),
+		batchDeadline: 50 * time.Millisecond,
+		logger:        zap.NewNop(),
+	}
+	err := p.metricsDataPusher(context.Background(), testdata.GenerateMetricsTwoMetrics())
+	assert.ErrorIs(t, err, errBatchDeadlineExceeded)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestLogsDataPusher_batchDeadline(t *testing.T) {
+	producer := &slowSyncProducer{delay: time.Second}
+	p := kafkaLogsProducer{
+		producer:      producer,
+		marshaler:     newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding),
+		batchDeadline: 50 * time.Millisecond,
+		logger:        zap.NewNop(),
+	}
+	err := p.logsDataPusher(context.Background(), testdata.GenerateLogsOneLogRecord())
+	assert.ErrorIs(t, err, errBatchDeadlineExceeded)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestTracesPusher_batchDeadline_met(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:      producer,
+		marshaler:     jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}},
+		batchDeadline: time.Minute,
+		logger:        zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResource())
+	require.NoError(t, err)
+}
+
 func TestTracesPusher_marshal_error(t *testing.T) {
 	expErr := fmt.Errorf("failed to marshal")
 	p := kafkaTracesProducer{
@@ -198,6 +874,24 @@ func TestMetricsDataPusher(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMetricsDataPusher_headersFromAttributes(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectAttributeHeaders(t, map[string]string{
+		"resource-attr": "resource-attr-val-1",
+	}))
+
+	p := kafkaMetricsProducer{
+		producer:              producer,
+		marshaler:             newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding),
+		headersFromAttributes: []string{"resource-attr", "missing"},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.metricsDataPusher(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+}
+
 func TestMetricsDataPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -245,6 +939,24 @@ func TestLogsDataPusher(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLogsDataPusher_headersFromAttributes(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectAttributeHeaders(t, map[string]string{
+		"resource-attr": "resource-attr-val-1",
+	}))
+
+	p := kafkaLogsProducer{
+		producer:              producer,
+		marshaler:             newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding),
+		headersFromAttributes: []string{"resource-attr", "missing"},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.logsDataPusher(context.Background(), testdata.GenerateLogsOneLogRecord()))
+}
+
 func TestLogsDataPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/Shopify/sarama"
+)
+
+const (
+	// keyHashFNV hashes message keys with FNV-1a, as the default Sarama hash partitioner.
+	keyHashFNV = "fnv"
+	// keyHashMurmur2 hashes message keys with murmur2, as the default partitioner of the Java client.
+	keyHashMurmur2 = "murmur2"
+)
+
+// keyHashPartitioner returns the constructor of the hash partitioner of the given key hash algorithm.
+// Empty selects the default Sarama hash partitioner.
+func keyHashPartitioner(algorithm string) sarama.PartitionerConstructor {
+	if algorithm == keyHashMurmur2 {
+		// the Java client takes the positive value of the hash before its modulo
+		return sarama.NewCustomPartitioner(sarama.WithAbsFirst(), sarama.WithCustomHashFunction(newMurmur2))
+	}
+	return sarama.NewHashPartitioner
+}
+
+// murmur2 is the 32-bit murmur2 hash as implemented by the Java client, which hashes the whole key at once.
+// The written bytes are buffered until Sum32 is called.
+type murmur2 struct {
+	data []byte
+}
+
+var _ hash.Hash32 = (*murmur2)(nil)
+
+func newMurmur2() hash.Hash32 {
+	return &murmur2{}
+}
+
+func (m *murmur2) Write(p []byte) (int, error) {
+	m.data = append(m.data, p...)
+	return len(p), nil
+}
+
+func (m *murmur2) Sum(b []byte) []byte {
+	sum := m.Sum32()
+	return append(b, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
+func (m *murmur2) Reset() {
+	m.data = m.data[:0]
+}
+
+func (m *murmur2) Size() int {
+	return 4
+}
+
+func (m *murmur2) BlockSize() int {
+	return 4
+}
+
+func (m *murmur2) Sum32() uint32 {
+	const (
+		seed = 0x9747b28c
+		mult = 0x5bd1e995
+		r    = 24
+	)
+	length := len(m.data)
+	h := uint32(seed) ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(m.data[i:])
+		k *= mult
+		k ^= k >> r
+		k *= mult
+		h *= mult
+		h ^= k
+	}
+	tail := m.data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= mult
+	}
+	h ^= h >> 13
+	h *= mult
+	h ^= h >> 15
+	return h
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMurmur2(t *testing.T) {
+	// the expected hashes are those of the Java client, as signed integers
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for key, expected := range tests {
+		t.Run(key, func(t *testing.T) {
+			h := newMurmur2()
+			_, err := h.Write([]byte(key))
+			require.NoError(t, err)
+			assert.Equal(t, expected, int32(h.Sum32()))
+
+			h.Reset()
+			_, err = h.Write([]byte(key))
+			require.NoError(t, err)
+			assert.Equal(t, expected, int32(h.Sum32()))
+		})
+	}
+}
+
+func TestKeyHashPartitioner(t *testing.T) {
+	for _, algorithm := range []string{"", keyHashFNV, keyHashMurmur2} {
+		t.Run(algorithm, func(t *testing.T) {
+			partitioner := keyHashPartitioner(algorithm)("otlp_spans")
+			assert.True(t, partitioner.RequiresConsistency())
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("trace-%d", i)
+				first, err := partitioner.Partition(keyedMessage(key), 16)
+				require.NoError(t, err)
+				second, err := keyHashPartitioner(algorithm)("otlp_spans").Partition(keyedMessage(key), 16)
+				require.NoError(t, err)
+				assert.Equal(t, first, second, "key %s changed partition", key)
+			}
+		})
+	}
+}
+
+func TestKeyHashPartitioner_fnv(t *testing.T) {
+	partitioner := keyHashPartitioner(keyHashFNV)("otlp_spans")
+	hash := sarama.NewHashPartitioner("otlp_spans")
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("trace-%d", i)
+		partition, err := partitioner.Partition(keyedMessage(key), 16)
+		require.NoError(t, err)
+		expected, err := hash.Partition(keyedMessage(key), 16)
+		require.NoError(t, err)
+		assert.Equal(t, expected, partition)
+	}
+}
+
+func TestKeyHashPartitioner_murmur2(t *testing.T) {
+	// the partitions assigned by the Java client, which takes the positive value of the hash before its modulo
+	tests := map[string]int32{
+		"21":     (-973932308 & 0x7fffffff) % 10,
+		"foobar": 6,
+		"abc":    479470107 % 10,
+	}
+	partitioner := keyHashPartitioner(keyHashMurmur2)("otlp_spans")
+	for key, expected := range tests {
+		partition, err := partitioner.Partition(keyedMessage(key), 10)
+		require.NoError(t, err)
+		assert.Equal(t, expected, partition, "key %s", key)
+	}
+}
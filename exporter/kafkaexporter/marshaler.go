@@ -15,10 +15,16 @@
 package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
 	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
 )
 
 // TracesMarshaler marshals traces into Message array.
@@ -48,28 +54,146 @@ type LogsMarshaler interface {
 	Encoding() string
 }
 
+// keyCoalescingMarshaler is implemented by TracesMarshalers that can coalesce
+// records sharing the same message key into a single message.
+type keyCoalescingMarshaler interface {
+	// withCoalesceByKey returns a copy of the marshaler producing one message per key
+	withCoalesceByKey() TracesMarshaler
+}
+
+// tombstoneMarshaler is implemented by TracesMarshalers producing keyed messages,
+// which can produce a tombstone, a message with a nil value, for records marked as deleted.
+type tombstoneMarshaler interface {
+	// withTombstoneAttribute returns a copy of the marshaler producing a tombstone for
+	// every record with a truthy attribute of the given name
+	withTombstoneAttribute(attribute string) TracesMarshaler
+}
+
+// marshalerRegistry holds the marshalers added with RegisterMarshaler, next to the built-in ones.
+type marshalerRegistry struct {
+	mu      sync.RWMutex
+	traces  map[string]TracesMarshaler
+	metrics map[string]MetricsMarshaler
+	logs    map[string]LogsMarshaler
+}
+
+var registry = &marshalerRegistry{
+	traces:  map[string]TracesMarshaler{},
+	metrics: map[string]MetricsMarshaler{},
+	logs:    map[string]LogsMarshaler{},
+}
+
+// RegisterMarshaler makes marshaler available as encoding name to the exporters of all the signals it
+// supports, i.e. of the TracesMarshaler, MetricsMarshaler and LogsMarshaler interfaces it implements.
+// It is meant to be called at init time by distributions adding custom encodings, and returns an error
+// if name is empty, already taken by a built-in or registered encoding, or marshaler supports no signal.
+func RegisterMarshaler(name string, marshaler interface{}) error {
+	if name == "" {
+		return errors.New("encoding name must not be empty")
+	}
+	if isKnownEncoding(name) {
+		return fmt.Errorf("encoding %q is already registered", name)
+	}
+	tracesMarshaler, isTraces := marshaler.(TracesMarshaler)
+	metricsMarshaler, isMetrics := marshaler.(MetricsMarshaler)
+	logsMarshaler, isLogs := marshaler.(LogsMarshaler)
+	if !isTraces && !isMetrics && !isLogs {
+		return fmt.Errorf("marshaler %T of encoding %q implements none of TracesMarshaler, MetricsMarshaler and LogsMarshaler", marshaler, name)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	_, tracesTaken := registry.traces[name]
+	_, metricsTaken := registry.metrics[name]
+	_, logsTaken := registry.logs[name]
+	if tracesTaken || metricsTaken || logsTaken {
+		// registered concurrently since the check above
+		return fmt.Errorf("encoding %q is already registered", name)
+	}
+	if isTraces {
+		registry.traces[name] = tracesMarshaler
+	}
+	if isMetrics {
+		registry.metrics[name] = metricsMarshaler
+	}
+	if isLogs {
+		registry.logs[name] = logsMarshaler
+	}
+	return nil
+}
+
+// registerTracesMarshaler registers marshaler for its encoding, replacing any marshaler of the same encoding.
+func registerTracesMarshaler(marshaler TracesMarshaler) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.traces[marshaler.Encoding()] = marshaler
+}
+
+// knownEncodings returns the sorted names of the built-in and registered encodings of all signals.
+func knownEncodings() []string {
+	names := map[string]struct{}{}
+	for name := range tracesMarshalers() {
+		names[name] = struct{}{}
+	}
+	for name := range metricsMarshalers() {
+		names[name] = struct{}{}
+	}
+	for name := range logsMarshalers() {
+		names[name] = struct{}{}
+	}
+	encodings := make([]string, 0, len(names))
+	for name := range names {
+		encodings = append(encodings, name)
+	}
+	sort.Strings(encodings)
+	return encodings
+}
+
+func isKnownEncoding(name string) bool {
+	for _, encoding := range knownEncodings() {
+		if encoding == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withRegistered adds the registered marshalers to the built-in marshalers, which take precedence.
+func withRegistered[M any](builtIn map[string]M, registered map[string]M) map[string]M {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for name, marshaler := range registered {
+		if _, ok := builtIn[name]; !ok {
+			builtIn[name] = marshaler
+		}
+	}
+	return builtIn
+}
+
 // tracesMarshalers returns map of supported encodings with TracesMarshaler.
 func tracesMarshalers() map[string]TracesMarshaler {
 	otlpPb := newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding)
 	otlpJSON := newPdataTracesMarshaler(&ptrace.JSONMarshaler{}, "otlp_json")
 	jaegerProto := jaegerMarshaler{marshaler: jaegerProtoSpanMarshaler{}}
 	jaegerJSON := jaegerMarshaler{marshaler: newJaegerJSONMarshaler()}
-	return map[string]TracesMarshaler{
+	raw := newRawTracesMarshaler("", "", zap.NewNop())
+	return withRegistered(map[string]TracesMarshaler{
 		otlpPb.Encoding():      otlpPb,
 		otlpJSON.Encoding():    otlpJSON,
 		jaegerProto.Encoding(): jaegerProto,
 		jaegerJSON.Encoding():  jaegerJSON,
-	}
+		raw.Encoding():         raw,
+	}, registry.traces)
 }
 
 // metricsMarshalers returns map of supported encodings and MetricsMarshaler
 func metricsMarshalers() map[string]MetricsMarshaler {
 	otlpPb := newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding)
 	otlpJSON := newPdataMetricsMarshaler(&pmetric.JSONMarshaler{}, "otlp_json")
-	return map[string]MetricsMarshaler{
+	return withRegistered(map[string]MetricsMarshaler{
 		otlpPb.Encoding():   otlpPb,
 		otlpJSON.Encoding(): otlpJSON,
-	}
+	}, registry.metrics)
 }
 
 // logsMarshalers returns map of supported encodings and LogsMarshaler
@@ -77,9 +201,9 @@ func logsMarshalers() map[string]LogsMarshaler {
 	otlpPb := newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding)
 	otlpJSON := newPdataLogsMarshaler(&plog.JSONMarshaler{}, "otlp_json")
 	raw := newRawMarshaler()
-	return map[string]LogsMarshaler{
+	return withRegistered(map[string]LogsMarshaler{
 		otlpPb.Encoding():   otlpPb,
 		otlpJSON.Encoding(): otlpJSON,
 		raw.Encoding():      raw,
-	}
+	}, registry.logs)
 }
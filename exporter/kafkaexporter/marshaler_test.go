@@ -14,14 +14,18 @@
 package kafkaexporter
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 )
@@ -32,6 +36,7 @@ func TestDefaultTracesMarshalers(t *testing.T) {
 		"otlp_json",
 		"jaeger_proto",
 		"jaeger_json",
+		"raw",
 	}
 	marshalers := tracesMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -44,6 +49,85 @@ func TestDefaultTracesMarshalers(t *testing.T) {
 	}
 }
 
+// unregisterMarshalers removes the marshalers registered under the given encodings at the end of the test
+func unregisterMarshalers(t *testing.T, encodings ...string) {
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		for _, encoding := range encodings {
+			delete(registry.traces, encoding)
+			delete(registry.metrics, encoding)
+			delete(registry.logs, encoding)
+		}
+	})
+}
+
+// customLogsMarshaler is a LogsMarshaler producing a single message with the log count as value
+type customLogsMarshaler struct{}
+
+func (customLogsMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	return []*sarama.ProducerMessage{{Topic: topic, Value: sarama.StringEncoder(fmt.Sprint(logs.LogRecordCount()))}}, nil
+}
+
+func (customLogsMarshaler) Encoding() string {
+	return "log_count"
+}
+
+func TestRegisterMarshaler(t *testing.T) {
+	unregisterMarshalers(t, "log_count", "custom_traces")
+
+	require.NoError(t, RegisterMarshaler("log_count", customLogsMarshaler{}))
+	require.NoError(t, RegisterMarshaler("custom_traces", &customMarshaler{}))
+
+	assert.Contains(t, logsMarshalers(), "log_count")
+	assert.NotContains(t, tracesMarshalers(), "log_count")
+	assert.NotContains(t, metricsMarshalers(), "log_count")
+	assert.Contains(t, tracesMarshalers(), "custom_traces")
+	assert.NotContains(t, logsMarshalers(), "custom_traces")
+
+	assert.EqualError(t, RegisterMarshaler("log_count", customLogsMarshaler{}), `encoding "log_count" is already registered`)
+	assert.EqualError(t, RegisterMarshaler("otlp_proto", customLogsMarshaler{}), `encoding "otlp_proto" is already registered`)
+	assert.EqualError(t, RegisterMarshaler("", customLogsMarshaler{}), "encoding name must not be empty")
+	assert.EqualError(t, RegisterMarshaler("string", "not a marshaler"), `marshaler string of encoding "string" implements none of TracesMarshaler, MetricsMarshaler and LogsMarshaler`)
+}
+
+func TestRegisterMarshaler_selectedByEncoding(t *testing.T) {
+	unregisterMarshalers(t, "log_count")
+	require.NoError(t, RegisterMarshaler("log_count", customLogsMarshaler{}))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = "log_count"
+	require.NoError(t, cfg.Validate())
+
+	marshaler, ok := logsMarshalers()[cfg.Encoding]
+	require.True(t, ok)
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, sarama.StringEncoder("2"), msg.Value)
+		return nil
+	})
+	p := kafkaLogsProducer{producer: producer, marshaler: marshaler, topic: "logs"}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+
+	logs := plog.NewLogs()
+	logRecords := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	logRecords.AppendEmpty()
+	logRecords.AppendEmpty()
+	require.NoError(t, p.logsDataPusher(context.Background(), logs))
+}
+
+func TestValidate_unregisteredEncoding(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = "avro"
+	assert.EqualError(t, cfg.Validate(), `encoding "avro" is not registered, registered encodings are: jaeger_json, jaeger_proto, otlp_json, otlp_proto, raw`)
+
+	cfg.Encoding = defaultEncoding
+	cfg.EncodingByTopic = map[string]string{"spans": "avro"}
+	assert.Error(t, cfg.Validate())
+}
+
 func TestDefaultMetricsMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
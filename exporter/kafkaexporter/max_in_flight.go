@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+// limitedProducer bounds the number of concurrent produce calls of a producer, independently of the
+// number of queue consumers exporting batches.
+type limitedProducer struct {
+	sarama.SyncProducer
+	// sem holds a token per produce call in flight
+	sem chan struct{}
+}
+
+// newLimitedProducer returns a producer allowing at most maxInFlight concurrent produce calls,
+// or producer itself if maxInFlight is not positive.
+func newLimitedProducer(producer sarama.SyncProducer, maxInFlight int) sarama.SyncProducer {
+	if maxInFlight <= 0 {
+		return producer
+	}
+	return &limitedProducer{SyncProducer: producer, sem: make(chan struct{}, maxInFlight)}
+}
+
+func (p *limitedProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func (p *limitedProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+	return p.SyncProducer.SendMessages(msgs)
+}
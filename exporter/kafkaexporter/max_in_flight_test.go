@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyProducer records the maximum number of concurrent produce calls
+type concurrencyProducer struct {
+	sarama.SyncProducer
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (p *concurrencyProducer) SendMessage(*sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, p.SendMessages(nil)
+}
+
+func (p *concurrencyProducer) SendMessages([]*sarama.ProducerMessage) error {
+	p.mu.Lock()
+	p.inFlight++
+	if p.inFlight > p.max {
+		p.max = p.inFlight
+	}
+	p.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	p.mu.Lock()
+	p.inFlight--
+	p.mu.Unlock()
+	return nil
+}
+
+func TestLimitedProducer(t *testing.T) {
+	producer := &concurrencyProducer{}
+	limited := newLimitedProducer(producer, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				assert.NoError(t, limited.SendMessages([]*sarama.ProducerMessage{{Topic: "test"}}))
+			} else {
+				_, _, err := limited.SendMessage(&sarama.ProducerMessage{Topic: "test"})
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, producer.max, 3)
+	assert.Greater(t, producer.max, 1)
+}
+
+func TestLimitedProducer_unlimited(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	assert.Same(t, producer, newLimitedProducer(producer, 0))
+}
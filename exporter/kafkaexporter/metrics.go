@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+
+	"github.com/Shopify/sarama"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	tagInstanceName, _ = tag.NewKey("name")
+
+	statConnectionErrors = stats.Int64("kafka_exporter_connection_errors", "Number of errors connecting to the Kafka brokers", stats.UnitDimensionless)
+	statConnected        = stats.Int64("kafka_exporter_connected", "Indicates with value 1 that the producer is connected to the Kafka brokers", stats.UnitDimensionless)
+	statMessageBytes     = stats.Int64("kafka_exporter_message_bytes", "Size of the serialized value of produced messages", stats.UnitBytes)
+	statDroppedSpans     = stats.Int64("kafka_exporter_dropped_spans", "Number of spans dropped by the raw encoding because they have no payload attribute", stats.UnitDimensionless)
+)
+
+// MetricViews return metric views for Kafka exporter.
+func MetricViews() []*view.View {
+	tagKeys := []tag.Key{tagInstanceName}
+
+	countConnectionErrors := &view.View{
+		Name:        statConnectionErrors.Name(),
+		Measure:     statConnectionErrors,
+		Description: statConnectionErrors.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	lastValueConnected := &view.View{
+		Name:        statConnected.Name(),
+		Measure:     statConnected,
+		Description: statConnected.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	distributionMessageBytes := &view.View{
+		Name:        statMessageBytes.Name(),
+		Measure:     statMessageBytes,
+		Description: statMessageBytes.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Distribution(0, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304),
+	}
+
+	countDroppedSpans := &view.View{
+		Name:        statDroppedSpans.Name(),
+		Measure:     statDroppedSpans,
+		Description: statDroppedSpans.Description(),
+		TagKeys:     tagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	return []*view.View{
+		countConnectionErrors,
+		lastValueConnected,
+		distributionMessageBytes,
+		countDroppedSpans,
+	}
+}
+
+// recordConnectionError counts a connection error and marks the producer of the exporter instance as disconnected.
+func recordConnectionError(name string) {
+	statsTags := []tag.Mutator{tag.Upsert(tagInstanceName, name)}
+	_ = stats.RecordWithTags(context.Background(), statsTags, statConnectionErrors.M(1), statConnected.M(0))
+}
+
+// recordConnected marks the producer of the exporter instance as connected.
+func recordConnected(name string) {
+	statsTags := []tag.Mutator{tag.Upsert(tagInstanceName, name)}
+	_ = stats.RecordWithTags(context.Background(), statsTags, statConnected.M(1))
+}
+
+// recordSendResult updates the connection metrics of the exporter instance after producing messages.
+// Errors not caused by the connection, such as rejected messages, leave the metrics untouched.
+func recordSendResult(name string, err error) {
+	switch {
+	case err == nil:
+		recordConnected(name)
+	case isConnectionError(err):
+		recordConnectionError(name)
+	}
+}
+
+// recordMessageBytes records the value size of the messages about to be produced.
+// Messages without value, such as tombstones, are not recorded.
+func recordMessageBytes(name string, messages []*sarama.ProducerMessage) {
+	statsTags := []tag.Mutator{tag.Upsert(tagInstanceName, name)}
+	measurements := make([]stats.Measurement, 0, len(messages))
+	for _, message := range messages {
+		if message.Value != nil {
+			measurements = append(measurements, statMessageBytes.M(int64(message.Value.Length())))
+		}
+	}
+	if len(measurements) > 0 {
+		_ = stats.RecordWithTags(context.Background(), statsTags, measurements...)
+	}
+}
+
+// recordDroppedSpans counts the spans dropped by the exporter instance.
+func recordDroppedSpans(name string, count int) {
+	statsTags := []tag.Mutator{tag.Upsert(tagInstanceName, name)}
+	_ = stats.RecordWithTags(context.Background(), statsTags, statDroppedSpans.M(int64(count)))
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
 )
 
 var errUnsupported = errors.New("unsupported serialization")
@@ -93,3 +95,72 @@ func (r rawMarshaler) interfaceAsBytes(value interface{}) ([]byte, error) {
 func (r rawMarshaler) Encoding() string {
 	return "raw"
 }
+
+// rawTracesMarshaler produces the pre-serialized payload held by a span attribute as message value.
+type rawTracesMarshaler struct {
+	// payloadAttribute is the span attribute holding the payload
+	payloadAttribute string
+	// name is the name of the exporter instance the dropped spans are counted for
+	name   string
+	logger *zap.Logger
+}
+
+var _ TracesMarshaler = (*rawTracesMarshaler)(nil)
+
+func newRawTracesMarshaler(payloadAttribute string, name string, logger *zap.Logger) rawTracesMarshaler {
+	return rawTracesMarshaler{payloadAttribute: payloadAttribute, name: name, logger: logger}
+}
+
+// withPayloadAttribute returns a copy of the marshaler reading the payload from the given span attribute,
+// counting the dropped spans for the exporter instance of the given name
+func (r rawTracesMarshaler) withPayloadAttribute(attribute string, name string, logger *zap.Logger) TracesMarshaler {
+	return newRawTracesMarshaler(attribute, name, logger)
+}
+
+// Marshal produces a message per span holding a bytes or string payload attribute.
+// Spans without payload are dropped and counted.
+func (r rawTracesMarshaler) Marshal(traces ptrace.Traces, topic string) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	dropped := 0
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rs := traces.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				payload, ok := r.payload(ss.Spans().At(k).Attributes())
+				if !ok {
+					dropped++
+					continue
+				}
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(payload),
+				})
+			}
+		}
+	}
+	if dropped > 0 {
+		recordDroppedSpans(r.name, dropped)
+		r.logger.Debug("Dropped spans without raw payload", zap.String("attribute", r.payloadAttribute), zap.Int("dropped_spans", dropped))
+	}
+	return messages, nil
+}
+
+func (r rawTracesMarshaler) payload(attributes pcommon.Map) ([]byte, bool) {
+	value, ok := attributes.Get(r.payloadAttribute)
+	if !ok {
+		return nil, false
+	}
+	switch value.Type() {
+	case pcommon.ValueTypeBytes:
+		return value.Bytes().AsRaw(), true
+	case pcommon.ValueTypeStr:
+		return []byte(value.Str()), true
+	default:
+		return nil, false
+	}
+}
+
+func (r rawTracesMarshaler) Encoding() string {
+	return "raw"
+}
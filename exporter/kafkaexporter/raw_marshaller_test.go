@@ -20,7 +20,11 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func ptr(i int) *int {
@@ -148,3 +152,38 @@ func Test_RawMarshaler(t *testing.T) {
 		})
 	}
 }
+
+func Test_RawTracesMarshaler(t *testing.T) {
+	// the views may already be registered by the factory
+	_ = view.Register(MetricViews()...)
+
+	core, logs := observer.New(zap.DebugLevel)
+	r := newRawTracesMarshaler("payload", t.Name(), zap.New(core))
+	traces := ptrace.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().Attributes().PutEmptyBytes("payload").FromRaw([]byte{0x01, 0x02})
+	spans.AppendEmpty().Attributes().PutStr("payload", "foo")
+	spans.AppendEmpty().Attributes().PutStr("other", "bar")
+	spans.AppendEmpty().Attributes().PutInt("payload", 1)
+
+	messages, err := r.Marshal(traces, "foo")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, sarama.ByteEncoder{0x01, 0x02}, messages[0].Value)
+	assert.Equal(t, sarama.ByteEncoder("foo"), messages[1].Value)
+	assert.Equal(t, "foo", messages[0].Topic)
+	assert.Nil(t, messages[0].Key)
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, int64(2), logs.All()[0].ContextMap()["dropped_spans"])
+
+	rows, err := view.RetrieveData(statDroppedSpans.Name())
+	require.NoError(t, err)
+	var dropped float64
+	for _, row := range rows {
+		if len(row.Tags) == 1 && row.Tags[0].Value == t.Name() {
+			dropped = row.Data.(*view.SumData).Value
+		}
+	}
+	assert.Equal(t, 2.0, dropped)
+}
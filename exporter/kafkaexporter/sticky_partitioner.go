@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// stickyPartitionerCacheSize bounds the number of keys of a topic whose partition is remembered
+const stickyPartitionerCacheSize = 10000
+
+// stickyPartitioner assigns keyed messages to the partition chosen by the hash partitioner the first time the key
+// is seen, and keeps producing the key to that partition while it exists, even if the number of partitions changes.
+// This avoids reordering messages of a key when partitions are added. The partitions of the least recently used
+// keys are forgotten once more than stickyPartitionerCacheSize keys are known.
+type stickyPartitioner struct {
+	hash     sarama.Partitioner
+	capacity int
+
+	mu         sync.Mutex
+	partitions map[string]*list.Element
+	// lru orders the keys from most to least recently used
+	lru *list.List
+}
+
+type stickyPartition struct {
+	key       string
+	partition int32
+}
+
+var _ sarama.DynamicConsistencyPartitioner = (*stickyPartitioner)(nil)
+
+func newStickyPartitioner(topic string) sarama.Partitioner {
+	return newStickyHashPartitioner(sarama.NewHashPartitioner)(topic)
+}
+
+// newStickyHashPartitioner returns the constructor of sticky partitioners first assigning keys with the hash
+// partitioners of hash.
+func newStickyHashPartitioner(hash sarama.PartitionerConstructor) sarama.PartitionerConstructor {
+	return func(topic string) sarama.Partitioner {
+		return &stickyPartitioner{
+			hash:       hash(topic),
+			capacity:   stickyPartitionerCacheSize,
+			partitions: make(map[string]*list.Element),
+			lru:        list.New(),
+		}
+	}
+}
+
+func (p *stickyPartitioner) Partition(message *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if message.Key == nil {
+		return p.hash.Partition(message, numPartitions)
+	}
+	bytes, err := message.Key.Encode()
+	if err != nil {
+		return -1, err
+	}
+	key := string(bytes)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if elem, ok := p.partitions[key]; ok {
+		assigned := elem.Value.(*stickyPartition)
+		if assigned.partition < numPartitions {
+			p.lru.MoveToFront(elem)
+			return assigned.partition, nil
+		}
+		// the partition no longer exists, the key is assigned again
+		p.lru.Remove(elem)
+		delete(p.partitions, key)
+	}
+
+	partition, err := p.hash.Partition(message, numPartitions)
+	if err != nil {
+		return -1, err
+	}
+	p.partitions[key] = p.lru.PushFront(&stickyPartition{key: key, partition: partition})
+	if p.lru.Len() > p.capacity {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.partitions, oldest.Value.(*stickyPartition).key)
+	}
+	return partition, nil
+}
+
+func (p *stickyPartitioner) RequiresConsistency() bool {
+	return true
+}
+
+func (p *stickyPartitioner) MessageRequiresConsistency(message *sarama.ProducerMessage) bool {
+	return message.Key != nil
+}
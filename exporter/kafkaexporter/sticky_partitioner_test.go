@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyedMessage(key string) *sarama.ProducerMessage {
+	return &sarama.ProducerMessage{Topic: "otlp_spans", Key: sarama.StringEncoder(key)}
+}
+
+func TestStickyPartitioner_keepsPartitionWhenPartitionsAreAdded(t *testing.T) {
+	partitioner := newStickyPartitioner("otlp_spans")
+	hash := sarama.NewHashPartitioner("otlp_spans")
+
+	assigned := map[string]int32{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("trace-%d", i)
+		partition, err := partitioner.Partition(keyedMessage(key), 4)
+		require.NoError(t, err)
+		expected, err := hash.Partition(keyedMessage(key), 4)
+		require.NoError(t, err)
+		assert.Equal(t, expected, partition)
+		assigned[key] = partition
+	}
+
+	// simulate metadata refreshes adding partitions, which changes the hash partition of most keys
+	moved := 0
+	for _, numPartitions := range []int32{8, 16} {
+		for key, partition := range assigned {
+			got, err := partitioner.Partition(keyedMessage(key), numPartitions)
+			require.NoError(t, err)
+			assert.Equal(t, partition, got, "key %s moved partition", key)
+
+			hashed, err := hash.Partition(keyedMessage(key), numPartitions)
+			require.NoError(t, err)
+			if hashed != partition {
+				moved++
+			}
+		}
+	}
+	assert.Greater(t, moved, 0, "expected the hash partitioner to move some keys")
+}
+
+func TestStickyPartitioner_reassignsRemovedPartition(t *testing.T) {
+	partitioner := newStickyPartitioner("otlp_spans")
+
+	var key string
+	var partition int32
+	for i := 0; ; i++ {
+		key = fmt.Sprintf("trace-%d", i)
+		var err error
+		partition, err = partitioner.Partition(keyedMessage(key), 8)
+		require.NoError(t, err)
+		if partition >= 2 {
+			break
+		}
+	}
+
+	reassigned, err := partitioner.Partition(keyedMessage(key), 2)
+	require.NoError(t, err)
+	assert.Less(t, reassigned, int32(2))
+
+	// the new assignment is sticky as well
+	got, err := partitioner.Partition(keyedMessage(key), 8)
+	require.NoError(t, err)
+	assert.Equal(t, reassigned, got)
+}
+
+func TestStickyPartitioner_evictsLeastRecentlyUsedKeys(t *testing.T) {
+	partitioner := newStickyPartitioner("otlp_spans").(*stickyPartitioner)
+	partitioner.capacity = 2
+
+	for _, key := range []string{"a", "b", "a", "c"} {
+		_, err := partitioner.Partition(keyedMessage(key), 4)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, partitioner.lru.Len())
+	assert.Contains(t, partitioner.partitions, "a")
+	assert.Contains(t, partitioner.partitions, "c")
+	assert.NotContains(t, partitioner.partitions, "b")
+}
+
+func TestStickyPartitioner_unkeyedMessages(t *testing.T) {
+	partitioner := newStickyPartitioner("otlp_spans").(*stickyPartitioner)
+	message := &sarama.ProducerMessage{Topic: "otlp_spans"}
+
+	partition, err := partitioner.Partition(message, 4)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, partition, int32(0))
+	assert.Less(t, partition, int32(4))
+	assert.Equal(t, 0, partitioner.lru.Len())
+	assert.False(t, partitioner.MessageRequiresConsistency(message))
+	assert.True(t, partitioner.MessageRequiresConsistency(keyedMessage("a")))
+}
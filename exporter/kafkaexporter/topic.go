@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var errMissingTopic = errors.New("no topic to produce the batch to: topic_from_attribute is absent and the topic is empty")
+
+// batchTopic returns the string value of the attribute looked up for topic_from_attribute if it was found,
+// or topic if the attribute was not found or its value is not a non-empty string. It fails if no topic results.
+func batchTopic(topic string, value pcommon.Value, found bool) (string, error) {
+	if found && value.Type() == pcommon.ValueTypeStr && value.Str() != "" {
+		topic = value.Str()
+	}
+	if topic == "" {
+		return "", errMissingTopic
+	}
+	return topic, nil
+}
+
+// tracesAttribute returns the value of the attribute key in the first resource of td having it,
+// or else in the first span having it.
+func tracesAttribute(td ptrace.Traces, key string) (pcommon.Value, bool) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		if value, ok := rss.At(i).Resource().Attributes().Get(key); ok {
+			return value, true
+		}
+	}
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			if value, ok := attributesSliceGet[ptrace.Span](ilss.At(j).Spans(), key); ok {
+				return value, true
+			}
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+// metricsAttribute returns the value of the attribute key in the first resource of md having it,
+// or else in the first data point having it.
+func metricsAttribute(md pmetric.Metrics, key string) (pcommon.Value, bool) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		if value, ok := rms.At(i).Resource().Attributes().Get(key); ok {
+			return value, true
+		}
+	}
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).ScopeMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if value, ok := dataPointsAttribute(metrics.At(k), key); ok {
+					return value, true
+				}
+			}
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+func dataPointsAttribute(metric pmetric.Metric, key string) (pcommon.Value, bool) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return attributesSliceGet[pmetric.NumberDataPoint](metric.Gauge().DataPoints(), key)
+	case pmetric.MetricTypeSum:
+		return attributesSliceGet[pmetric.NumberDataPoint](metric.Sum().DataPoints(), key)
+	case pmetric.MetricTypeHistogram:
+		return attributesSliceGet[pmetric.HistogramDataPoint](metric.Histogram().DataPoints(), key)
+	case pmetric.MetricTypeExponentialHistogram:
+		return attributesSliceGet[pmetric.ExponentialHistogramDataPoint](metric.ExponentialHistogram().DataPoints(), key)
+	case pmetric.MetricTypeSummary:
+		return attributesSliceGet[pmetric.SummaryDataPoint](metric.Summary().DataPoints(), key)
+	}
+	return pcommon.Value{}, false
+}
+
+// logsAttribute returns the value of the attribute key in the first resource of ld having it,
+// or else in the first log record having it.
+func logsAttribute(ld plog.Logs, key string) (pcommon.Value, bool) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		if value, ok := rls.At(i).Resource().Attributes().Get(key); ok {
+			return value, true
+		}
+	}
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).ScopeLogs()
+		for j := 0; j < ills.Len(); j++ {
+			if value, ok := attributesSliceGet[plog.LogRecord](ills.At(j).LogRecords(), key); ok {
+				return value, true
+			}
+		}
+	}
+	return pcommon.Value{}, false
+}
+
+// attributesSliceGet returns the value of the attribute key in the first element of slice having it.
+func attributesSliceGet[E interface{ Attributes() pcommon.Map }, S interface {
+	Len() int
+	At(int) E
+}](slice S, key string) (pcommon.Value, bool) {
+	for i := 0; i < slice.Len(); i++ {
+		if value, ok := slice.At(i).Attributes().Get(key); ok {
+			return value, true
+		}
+	}
+	return pcommon.Value{}, false
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func expectTopic(t *testing.T, expected string) func(*sarama.ProducerMessage) error {
+	return func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, expected, msg.Topic)
+		return nil
+	}
+}
+
+func TestTracesPusher_topicFromAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		traces   func() ptrace.Traces
+		expected string
+	}{
+		{
+			name: "resource attribute",
+			traces: func() ptrace.Traces {
+				td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().PutStr("tenant", "span-tenant")
+				td.ResourceSpans().At(1).Resource().Attributes().PutStr("tenant", "acme")
+				return td
+			},
+			expected: "acme",
+		},
+		{
+			name: "span attribute",
+			traces: func() ptrace.Traces {
+				td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).Attributes().PutStr("tenant", "acme")
+				return td
+			},
+			expected: "acme",
+		},
+		{
+			name:     "missing attribute",
+			traces:   testdata.GenerateTracesTwoSpansSameResourceOneDifferent,
+			expected: "otlp_spans",
+		},
+		{
+			name: "non-string attribute",
+			traces: func() ptrace.Traces {
+				td := testdata.GenerateTracesTwoSpansSameResourceOneDifferent()
+				td.ResourceSpans().At(0).Resource().Attributes().PutInt("tenant", 42)
+				return td
+			},
+			expected: "otlp_spans",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := sarama.NewConfig()
+			producer := mocks.NewSyncProducer(t, c)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic(t, tt.expected))
+
+			p := kafkaTracesProducer{
+				producer:           producer,
+				topic:              "otlp_spans",
+				topicFromAttribute: "tenant",
+				marshaler:          newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+			}
+			t.Cleanup(func() {
+				require.NoError(t, p.Close(context.Background()))
+			})
+			require.NoError(t, p.tracesPusher(context.Background(), tt.traces()))
+		})
+	}
+}
+
+func TestTracesPusher_topicFromAttribute_encodingByTopic(t *testing.T) {
+	config := Config{
+		Topic:                   "otlp_spans",
+		TopicFromAttribute:      "tenant",
+		Encoding:                "otlp_json",
+		EncodingByTopic:         map[string]string{"acme": defaultEncoding},
+		SendSchemaVersionHeader: true,
+	}
+	marshalers := tracesMarshalers()
+	require.NoError(t, applyEncodingByTopic(&config, marshalers))
+	topicMarshalers, err := newTopicMarshalers(config, marshalers, nil)
+	require.NoError(t, err)
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	td.ResourceSpans().At(0).Resource().Attributes().PutStr("tenant", "acme")
+	producer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, "acme", msg.Topic)
+		value, err := msg.Value.Encode()
+		require.NoError(t, err)
+		received, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(value)
+		require.NoError(t, err)
+		assert.Equal(t, td, received)
+		require.Len(t, msg.Headers, 1)
+		assert.Equal(t, otlpProtoVersionHeader, string(msg.Headers[0].Key))
+		return nil
+	})
+
+	p := kafkaTracesProducer{
+		producer:            producer,
+		topic:               config.Topic,
+		topicFromAttribute:  config.TopicFromAttribute,
+		marshaler:           marshalers[config.Encoding],
+		schemaVersionHeader: sendSchemaVersionHeader(config),
+		topicMarshalers:     topicMarshalers,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+}
+
+func TestTracesPusher_topicFromAttribute_noTopic(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+
+	p := kafkaTracesProducer{
+		producer:           producer,
+		topicFromAttribute: "tenant",
+		marshaler:          newPdataTracesMarshaler(&ptrace.ProtoMarshaler{}, defaultEncoding),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.tracesPusher(context.Background(), testdata.GenerateTracesTwoSpansSameResourceOneDifferent())
+	assert.ErrorIs(t, err, errMissingTopic)
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
+func TestMetricsDataPusher_topicFromAttribute(t *testing.T) {
+	md := testdata.GenerateMetricsAllTypesEmptyDataPoint()
+	dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(4).Histogram().DataPoints()
+	dps.At(dps.Len()-1).Attributes().PutStr("tenant", "acme")
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic(t, "acme"))
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic(t, "otlp_metrics"))
+
+	p := kafkaMetricsProducer{
+		producer:           producer,
+		topic:              "otlp_metrics",
+		topicFromAttribute: "tenant",
+		marshaler:          newPdataMetricsMarshaler(&pmetric.ProtoMarshaler{}, defaultEncoding),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.metricsDataPusher(context.Background(), md))
+	require.NoError(t, p.metricsDataPusher(context.Background(), testdata.GenerateMetricsAllTypesEmptyDataPoint()))
+}
+
+func TestLogsDataPusher_topicFromAttribute(t *testing.T) {
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Attributes().PutStr("tenant", "acme")
+	nonString := testdata.GenerateLogsOneLogRecord()
+	nonString.ResourceLogs().At(0).Resource().Attributes().PutBool("tenant", true)
+
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic(t, "acme"))
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTopic(t, "otlp_logs"))
+
+	p := kafkaLogsProducer{
+		producer:           producer,
+		topic:              "otlp_logs",
+		topicFromAttribute: "tenant",
+		marshaler:          newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	require.NoError(t, p.logsDataPusher(context.Background(), ld))
+	require.NoError(t, p.logsDataPusher(context.Background(), nonString))
+}
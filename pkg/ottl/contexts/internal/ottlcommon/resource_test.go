@@ -146,6 +146,41 @@ func TestResourcePathGetSetter(t *testing.T) {
 				resource.Attributes().PutEmptySlice("arr_str").AppendEmpty().SetStr("new")
 			},
 		},
+		{
+			name: "attributes pcommon.Slice",
+			path: []ottl.Field{
+				{
+					Name:   "attributes",
+					MapKey: ottltest.Strp("arr_str"),
+				},
+			},
+			orig: func() pcommon.Slice {
+				val, _ := refResource.Attributes().Get("arr_str")
+				return val.Slice()
+			}(),
+			newVal: func() pcommon.Slice {
+				newSlice := pcommon.NewSlice()
+				newSlice.AppendEmpty().SetStr("new")
+				return newSlice
+			}(),
+			modified: func(resource pcommon.Resource) {
+				resource.Attributes().PutEmptySlice("arr_str").AppendEmpty().SetStr("new")
+			},
+		},
+		{
+			name: "attributes pcommon.Map",
+			path: []ottl.Field{
+				{
+					Name:   "attributes",
+					MapKey: ottltest.Strp("str"),
+				},
+			},
+			orig:   "val",
+			newVal: newAttrs,
+			modified: func(resource pcommon.Resource) {
+				newAttrs.CopyTo(resource.Attributes().PutEmptyMap("str"))
+			},
+		},
 		{
 			name: "attributes array bool",
 			path: []ottl.Field{
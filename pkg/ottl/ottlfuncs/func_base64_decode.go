@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Base64Decode[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		encoded, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		encoding := base64.StdEncoding
+		if !strings.HasSuffix(encoded, "=") && len(encoded)%4 != 0 {
+			// the padding is often stripped, the unpadded length tells the number of missing padding characters
+			encoding = base64.RawStdEncoding
+		}
+		decoded, err := encoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("Base64Decode failed to decode %q: %w", encoded, err)
+		}
+		return string(decoded), nil
+	}, nil
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_base64Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{
+			name:     "valid",
+			value:    "eyJsZXZlbCI6ImVycm9yIn0=",
+			expected: `{"level":"error"}`,
+		},
+		{
+			name:     "padded",
+			value:    "aGVsbG8gd29ybGQ=",
+			expected: "hello world",
+		},
+		{
+			name:     "unpadded",
+			value:    "aGVsbG8gd29ybGQ",
+			expected: "hello world",
+		},
+		{
+			name:     "two padding characters",
+			value:    "aGk=",
+			expected: "hi",
+		},
+		{
+			name:     "two padding characters unpadded",
+			value:    "aGVsbG8",
+			expected: "hello",
+		},
+		{
+			name:     "empty",
+			value:    "",
+			expected: "",
+		},
+		{
+			name:     "non string",
+			value:    int64(1),
+			expected: nil,
+		},
+		{
+			name:     "nil",
+			value:    nil,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.value, nil
+				},
+			}
+			exprFunc, err := Base64Decode[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_base64Decode_error(t *testing.T) {
+	for _, value := range []string{"not base64!", "aGVsbG8=d29ybGQ=", "aGVsbG8gd29ybGQ=="} {
+		t.Run(value, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return value, nil
+				},
+			}
+			exprFunc, err := Base64Decode[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.ErrorContains(t, err, "Base64Decode failed to decode")
+			assert.Nil(t, result)
+		})
+	}
+}
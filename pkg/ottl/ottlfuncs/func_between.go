@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Between[K any](target ottl.Getter[K], low float64, high float64) (ottl.ExprFunc[K], error) {
+	if low > high {
+		return nil, fmt.Errorf("invalid bounds for Between function, low %v is greater than high %v", low, high)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch v := val.(type) {
+		case int64:
+			return float64(v) >= low && float64(v) <= high, nil
+		case float64:
+			return v >= low && v <= high, nil
+		default:
+			return false, nil
+		}
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_between(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		low      float64
+		high     float64
+		expected bool
+	}{
+		{
+			name:     "int in range",
+			value:    int64(250),
+			low:      200,
+			high:     299,
+			expected: true,
+		},
+		{
+			name:     "int at low bound",
+			value:    int64(200),
+			low:      200,
+			high:     299,
+			expected: true,
+		},
+		{
+			name:     "int at high bound",
+			value:    int64(299),
+			low:      200,
+			high:     299,
+			expected: true,
+		},
+		{
+			name:     "int below range",
+			value:    int64(199),
+			low:      200,
+			high:     299,
+			expected: false,
+		},
+		{
+			name:     "int above range",
+			value:    int64(300),
+			low:      200,
+			high:     299,
+			expected: false,
+		},
+		{
+			name:     "float in range",
+			value:    0.5,
+			low:      0,
+			high:     1,
+			expected: true,
+		},
+		{
+			name:     "float just above range",
+			value:    1.0001,
+			low:      0,
+			high:     1,
+			expected: false,
+		},
+		{
+			name:     "single value range",
+			value:    int64(5),
+			low:      5,
+			high:     5,
+			expected: true,
+		},
+		{
+			name:     "NaN",
+			value:    math.NaN(),
+			low:      0,
+			high:     1,
+			expected: false,
+		},
+		{
+			name:     "string",
+			value:    "250",
+			low:      200,
+			high:     299,
+			expected: false,
+		},
+		{
+			name:     "nil",
+			value:    nil,
+			low:      200,
+			high:     299,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := Between[interface{}](literalGetter(tt.value), tt.low, tt.high)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_between_validation(t *testing.T) {
+	_, err := Between[interface{}](literalGetter(int64(1)), 2, 1)
+	assert.Error(t, err)
+}
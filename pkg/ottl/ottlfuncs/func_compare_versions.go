@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func CompareVersions[K any](left ottl.Getter[K], right ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		leftVal, err := left.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rightVal, err := right.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if leftVal == nil || rightVal == nil {
+			return nil, nil
+		}
+		leftStr, ok := leftVal.(string)
+		if !ok {
+			return nil, fmt.Errorf("CompareVersions requires string values, got %T", leftVal)
+		}
+		rightStr, ok := rightVal.(string)
+		if !ok {
+			return nil, fmt.Errorf("CompareVersions requires string values, got %T", rightVal)
+		}
+		leftVersion, err := parseVersion(leftStr)
+		if err != nil {
+			return nil, err
+		}
+		rightVersion, err := parseVersion(rightStr)
+		if err != nil {
+			return nil, err
+		}
+		for i := range leftVersion {
+			switch {
+			case leftVersion[i] < rightVersion[i]:
+				return int64(-1), nil
+			case leftVersion[i] > rightVersion[i]:
+				return int64(1), nil
+			}
+		}
+		return int64(0), nil
+	}, nil
+}
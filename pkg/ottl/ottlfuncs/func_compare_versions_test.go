@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_compareVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		left     interface{}
+		right    interface{}
+		expected interface{}
+	}{
+		{
+			name:     "equal",
+			left:     "1.2.3",
+			right:    "v1.2.3",
+			expected: int64(0),
+		},
+		{
+			name:     "partial equals full",
+			left:     "1.2",
+			right:    "1.2.0",
+			expected: int64(0),
+		},
+		{
+			name:     "less by minor",
+			left:     "1.2.9",
+			right:    "1.10",
+			expected: int64(-1),
+		},
+		{
+			name:     "greater by patch",
+			left:     "1.2.3",
+			right:    "1.2",
+			expected: int64(1),
+		},
+		{
+			name:     "greater by major",
+			left:     "2",
+			right:    "1.99.99",
+			expected: int64(1),
+		},
+		{
+			name:     "missing value",
+			left:     nil,
+			right:    "1.2.3",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := CompareVersions[interface{}](literalGetter(tt.left), literalGetter(tt.right))
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_compareVersions_invalid(t *testing.T) {
+	exprFunc, err := CompareVersions[interface{}](literalGetter("1.2.3"), literalGetter("not.a.version"))
+	require.NoError(t, err)
+	_, err = exprFunc(nil)
+	assert.Error(t, err)
+}
+
+func literalGetter(val interface{}) ottl.Getter[interface{}] {
+	return &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return val, nil
+		},
+	}
+}
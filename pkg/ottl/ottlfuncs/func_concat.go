@@ -15,6 +15,7 @@
 package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -23,31 +24,37 @@ import (
 
 func Concat[K any](vals []ottl.Getter[K], delimiter string) (ottl.ExprFunc[K], error) {
 	return func(ctx K) (interface{}, error) {
-		builder := strings.Builder{}
-		for i, rv := range vals {
+		parts := make([]string, 0, len(vals))
+		for _, rv := range vals {
 			val, err := rv.Get(ctx)
 			if err != nil {
 				return nil, err
 			}
-			switch v := val.(type) {
-			case string:
-				builder.WriteString(v)
-			case []byte:
-				builder.WriteString(fmt.Sprintf("%x", v))
-			case int64:
-				builder.WriteString(fmt.Sprint(v))
-			case float64:
-				builder.WriteString(fmt.Sprint(v))
-			case bool:
-				builder.WriteString(fmt.Sprint(v))
-			case nil:
-				builder.WriteString(fmt.Sprint(v))
-			}
-
-			if i != len(vals)-1 {
-				builder.WriteString(delimiter)
+			raw := toRaw(val)
+			if raw == nil {
+				// missing values are skipped along with their delimiter
+				continue
 			}
+			parts = append(parts, toString(raw))
 		}
-		return builder.String(), nil
+		return strings.Join(parts, delimiter), nil
 	}, nil
 }
+
+// toString returns the string form of val used by the functions working on strings. Byte slices are
+// hex-encoded, and maps and slices are JSON-encoded, values that cannot be encoded are left empty.
+func toString(val interface{}) string {
+	switch v := toRaw(val).(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return fmt.Sprintf("%x", v)
+	case int64, float64, bool:
+		return fmt.Sprint(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
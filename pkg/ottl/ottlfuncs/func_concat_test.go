@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
@@ -66,7 +67,7 @@ func Test_concat(t *testing.T) {
 				},
 			},
 			delimiter: "",
-			expected:  "hello<nil>world",
+			expected:  "helloworld",
 		},
 		{
 			name: "integers",
@@ -141,7 +142,7 @@ func Test_concat(t *testing.T) {
 				},
 			},
 			delimiter: "",
-			expected:  "",
+			expected:  "[1,2,3,4,5,6,7,8,9,0]",
 		},
 		{
 			name: "maps",
@@ -153,7 +154,7 @@ func Test_concat(t *testing.T) {
 				},
 			},
 			delimiter: "",
-			expected:  "",
+			expected:  `{"key":"value"}`,
 		},
 		{
 			name: "unprintable value in the middle",
@@ -165,7 +166,7 @@ func Test_concat(t *testing.T) {
 				},
 				{
 					Getter: func(ctx interface{}) (interface{}, error) {
-						return map[string]string{"key": "value"}, nil
+						return make(chan int), nil
 					},
 				},
 				{
@@ -177,6 +178,56 @@ func Test_concat(t *testing.T) {
 			delimiter: "-",
 			expected:  "hello--world",
 		},
+		{
+			name: "pdata map and slice",
+			vals: []ottl.StandardGetSetter[interface{}]{
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						m := pcommon.NewMap()
+						m.PutStr("region", "eu")
+						m.PutInt("zone", 2)
+						return m, nil
+					},
+				},
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						s := pcommon.NewSlice()
+						s.AppendEmpty().SetStr("a")
+						s.AppendEmpty().SetBool(true)
+						return s, nil
+					},
+				},
+			},
+			delimiter: " ",
+			expected:  `{"region":"eu","zone":2} ["a",true]`,
+		},
+		{
+			name: "strings, ints and nil",
+			vals: []ottl.StandardGetSetter[interface{}]{
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						return "tenant", nil
+					},
+				},
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						return nil, nil
+					},
+				},
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						return int64(42), nil
+					},
+				},
+				{
+					Getter: func(ctx interface{}) (interface{}, error) {
+						return pcommon.NewValueInt(7), nil
+					},
+				},
+			},
+			delimiter: "/",
+			expected:  "tenant/42/7",
+		},
 		{
 			name: "empty string values",
 			vals: []ottl.StandardGetSetter[interface{}]{
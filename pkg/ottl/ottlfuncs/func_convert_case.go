@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var caseConversions = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"snake": toSnakeCase,
+	"camel": toCamelCase,
+}
+
+func ConvertCase[K any](target ottl.GetSetter[K], toCase string) (ottl.ExprFunc[K], error) {
+	convert, ok := caseConversions[toCase]
+	if !ok {
+		return nil, fmt.Errorf("invalid case for convert_case function, %q is not one of \"lower\", \"upper\", \"snake\" or \"camel\"", toCase)
+	}
+
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		if converted := convert(str); converted != str {
+			if err = target.Set(ctx, converted); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// toSnakeCase joins the lowercased words of s with underscores, e.g. HTTP-Request_ID becomes http_request_id.
+func toSnakeCase(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// toCamelCase joins the words of s capitalized, but for the first one which is lowercased,
+// e.g. HTTP-Request_ID becomes httpRequestId.
+func toCamelCase(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits s into words at any character that is neither a letter nor a digit, and at case
+// changes: before an upper case letter following a lower case letter or a digit, and before the last
+// letter of a run of upper case letters followed by a lower case letter, so that HTTPRequest is split
+// into HTTP and Request.
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_convertCase(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Value]{
+		Getter: func(ctx pcommon.Value) (interface{}, error) {
+			return ctx.Str(), nil
+		},
+		Setter: func(ctx pcommon.Value, val interface{}) error {
+			ctx.SetStr(val.(string))
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		toCase   string
+		expected string
+	}{
+		{
+			name:     "lower",
+			input:    "HTTP-Request_ID",
+			toCase:   "lower",
+			expected: "http-request_id",
+		},
+		{
+			name:     "upper",
+			input:    "HTTP-Request_ID",
+			toCase:   "upper",
+			expected: "HTTP-REQUEST_ID",
+		},
+		{
+			name:     "snake mixed separators",
+			input:    "HTTP-Request_ID",
+			toCase:   "snake",
+			expected: "http_request_id",
+		},
+		{
+			name:     "snake from camel",
+			input:    "httpRequestID",
+			toCase:   "snake",
+			expected: "http_request_id",
+		},
+		{
+			name:     "snake acronym",
+			input:    "HTTPRequest.duration",
+			toCase:   "snake",
+			expected: "http_request_duration",
+		},
+		{
+			name:     "snake digits",
+			input:    "http2 Status",
+			toCase:   "snake",
+			expected: "http2_status",
+		},
+		{
+			name:     "snake unchanged",
+			input:    "http_request_id",
+			toCase:   "snake",
+			expected: "http_request_id",
+		},
+		{
+			name:     "camel mixed separators",
+			input:    "HTTP-Request_ID",
+			toCase:   "camel",
+			expected: "httpRequestId",
+		},
+		{
+			name:     "camel from snake",
+			input:    "__http_request__id_",
+			toCase:   "camel",
+			expected: "httpRequestId",
+		},
+		{
+			name:     "camel unicode",
+			input:    "état-civil",
+			toCase:   "camel",
+			expected: "étatCivil",
+		},
+		{
+			name:     "empty",
+			input:    "",
+			toCase:   "camel",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioValue := pcommon.NewValueStr(tt.input)
+
+			exprFunc, err := ConvertCase[pcommon.Value](target, tt.toCase)
+			require.NoError(t, err)
+
+			result, err := exprFunc(scenarioValue)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, pcommon.NewValueStr(tt.expected), scenarioValue)
+		})
+	}
+}
+
+func Test_convertCase_validation(t *testing.T) {
+	_, err := ConvertCase[interface{}](&ottl.StandardGetSetter[interface{}]{}, "kebab")
+	assert.EqualError(t, err, `invalid case for convert_case function, "kebab" is not one of "lower", "upper", "snake" or "camel"`)
+}
+
+func Test_convertCase_bad_input(t *testing.T) {
+	input := pcommon.NewValueInt(1)
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx interface{}, val interface{}) error {
+			t.Errorf("nothing should be set in this scenario")
+			return nil
+		},
+	}
+
+	exprFunc, err := ConvertCase[interface{}](target, "snake")
+	require.NoError(t, err)
+
+	result, err := exprFunc(input)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, pcommon.NewValueInt(1), input)
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_countMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   ottl.Getter[interface{}]
+		pattern  string
+		expected int64
+	}{
+		{
+			name: "multiple matches",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "ERROR: disk full\nINFO: retrying\nERROR: disk full\nERROR: giving up", nil
+				},
+			},
+			pattern:  "ERROR:",
+			expected: 3,
+		},
+		{
+			name: "non-overlapping matches",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "aaaaa", nil
+				},
+			},
+			pattern:  "aa",
+			expected: 2,
+		},
+		{
+			name: "no match",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "INFO: all good", nil
+				},
+			},
+			pattern:  "ERROR:",
+			expected: 0,
+		},
+		{
+			name: "target not a string",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return 1, nil
+				},
+			},
+			pattern:  "1",
+			expected: 0,
+		},
+		{
+			name: "target nil",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return nil, nil
+				},
+			},
+			pattern:  ".*",
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := CountMatches(tt.target, tt.pattern)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_countMatches_validation(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return "anything", nil
+		},
+	}
+	_, err := CountMatches[interface{}](target, "\\K")
+	require.Error(t, err)
+}
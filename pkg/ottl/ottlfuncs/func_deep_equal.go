@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"reflect"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func DeepEqual[K any](left ottl.Getter[K], right ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		leftVal, err := left.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rightVal, err := right.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.DeepEqual(toRaw(leftVal), toRaw(rightVal)), nil
+	}, nil
+}
+
+// toRaw converts pdata values to their raw Go representation so that a
+// pcommon.Map and a map[string]interface{} holding the same data compare equal.
+func toRaw(val interface{}) interface{} {
+	switch v := val.(type) {
+	case pcommon.Map:
+		return v.AsRaw()
+	case pcommon.Slice:
+		return v.AsRaw()
+	case pcommon.Value:
+		return v.AsRaw()
+	case pcommon.ByteSlice:
+		return v.AsRaw()
+	case map[string]interface{}:
+		raw := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			raw[k] = toRaw(e)
+		}
+		return raw
+	case []interface{}:
+		raw := make([]interface{}, len(v))
+		for i, e := range v {
+			raw[i] = toRaw(e)
+		}
+		return raw
+	default:
+		return val
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_deepEqual(t *testing.T) {
+	nested := func() pcommon.Map {
+		m := pcommon.NewMap()
+		m.PutStr("name", "checkout")
+		inner := m.PutEmptyMap("labels")
+		inner.PutInt("replicas", 3)
+		s := inner.PutEmptySlice("zones")
+		s.AppendEmpty().SetStr("a")
+		s.AppendEmpty().SetStr("b")
+		return m
+	}
+	changed := nested()
+	zones, _ := changed.Get("labels")
+	z, _ := zones.Map().Get("zones")
+	z.Slice().At(1).SetStr("c")
+
+	tests := []struct {
+		name     string
+		left     interface{}
+		right    interface{}
+		expected bool
+	}{
+		{
+			name:     "equal nested maps",
+			left:     nested(),
+			right:    nested(),
+			expected: true,
+		},
+		{
+			name:     "unequal nested maps",
+			left:     nested(),
+			right:    changed,
+			expected: false,
+		},
+		{
+			name:     "map equals raw map",
+			left:     nested(),
+			right:    map[string]interface{}{"name": "checkout", "labels": map[string]interface{}{"replicas": int64(3), "zones": []interface{}{"a", "b"}}},
+			expected: true,
+		},
+		{
+			name:     "equal slices",
+			left:     []interface{}{"a", int64(1), []interface{}{true}},
+			right:    []interface{}{"a", int64(1), []interface{}{true}},
+			expected: true,
+		},
+		{
+			name:     "unequal slice order",
+			left:     []interface{}{"a", "b"},
+			right:    []interface{}{"b", "a"},
+			expected: false,
+		},
+		{
+			name:     "type mismatch",
+			left:     nested(),
+			right:    "checkout",
+			expected: false,
+		},
+		{
+			name:     "numeric type mismatch",
+			left:     int64(1),
+			right:    float64(1),
+			expected: false,
+		},
+		{
+			name:     "both nil",
+			left:     nil,
+			right:    nil,
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := DeepEqual[interface{}](literalGetter(tt.left), literalGetter(tt.right))
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_dropNullValues(t *testing.T) {
+	input := pcommon.NewMap()
+	input.PutStr("test", "hello world")
+	input.PutEmpty("test2")
+	input.PutStr("test3", "")
+	input.PutInt("test4", 0)
+	input.PutEmptyMap("test5")
+
+	target := &ottl.StandardGetSetter[pcommon.Map]{
+		Getter: func(ctx pcommon.Map) (interface{}, error) {
+			return ctx, nil
+		},
+	}
+
+	tests := []struct {
+		name             string
+		dropEmptyStrings bool
+		want             func(pcommon.Map)
+	}{
+		{
+			name:             "keep empty strings",
+			dropEmptyStrings: false,
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.PutStr("test", "hello world")
+				expectedMap.PutStr("test3", "")
+				expectedMap.PutInt("test4", 0)
+				expectedMap.PutEmptyMap("test5")
+			},
+		},
+		{
+			name:             "drop empty strings",
+			dropEmptyStrings: true,
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.PutStr("test", "hello world")
+				expectedMap.PutInt("test4", 0)
+				expectedMap.PutEmptyMap("test5")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioMap := pcommon.NewMap()
+			input.CopyTo(scenarioMap)
+
+			exprFunc, err := DropNullValues[pcommon.Map](target, tt.dropEmptyStrings)
+			assert.NoError(t, err)
+
+			_, err = exprFunc(scenarioMap)
+			assert.Nil(t, err)
+
+			expected := pcommon.NewMap()
+			tt.want(expected)
+
+			assert.Equal(t, expected.AsRaw(), scenarioMap.AsRaw())
+		})
+	}
+}
+
+func Test_dropNullValues_bad_input(t *testing.T) {
+	input := pcommon.NewValueStr("")
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+	}
+
+	exprFunc, err := DropNullValues[interface{}](target, true)
+	assert.NoError(t, err)
+
+	_, err = exprFunc(input)
+	assert.Nil(t, err)
+
+	assert.Equal(t, pcommon.NewValueStr(""), input)
+}
+
+func Test_dropNullValues_get_nil(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+	}
+
+	exprFunc, err := DropNullValues[interface{}](target, true)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
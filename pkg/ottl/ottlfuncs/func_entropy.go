@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Entropy[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Entropy requires a string, got %T", val)
+		}
+		return entropy(str), nil
+	}, nil
+}
+
+// entropy returns the Shannon entropy of str in bits per character
+func entropy(str string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range str {
+		counts[r]++
+		total++
+	}
+	result := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		result -= p * math.Log2(p)
+	}
+	return result
+}
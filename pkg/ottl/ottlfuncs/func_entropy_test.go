@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_entropy(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected float64
+	}{
+		{
+			name:     "empty",
+			target:   "",
+			expected: 0,
+		},
+		{
+			name:     "single character",
+			target:   "aaaaaaaa",
+			expected: 0,
+		},
+		{
+			name:     "two characters",
+			target:   "abababab",
+			expected: 1,
+		},
+		{
+			name:     "distinct characters",
+			target:   "abcdefgh",
+			expected: 3,
+		},
+		{
+			name:     "multi-byte characters",
+			target:   "日本日本",
+			expected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := Entropy[interface{}](&ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			})
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+}
+
+func Test_entropy_randomness(t *testing.T) {
+	low := entropy("passwordpassword")
+	high := entropy("xK9#qL2$vB7!mZ4&")
+	assert.Less(t, low, 3.0)
+	assert.Greater(t, high, 3.9)
+
+	domain := entropy("google")
+	dga := entropy("q8x3kz7vfj2m")
+	assert.Greater(t, dga, domain)
+}
+
+func Test_entropy_bad_input(t *testing.T) {
+	exprFunc, err := Entropy[interface{}](&ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	})
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.EqualError(t, err, "Entropy requires a string, got int64")
+	assert.Nil(t, result)
+}
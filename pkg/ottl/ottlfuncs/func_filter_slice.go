@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const filterSliceMatches = "matches"
+
+var filterSliceComparisons = map[string]func(float64, float64) bool{
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+}
+
+func FilterSlice[K any](target ottl.Getter[K], condition string, value ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	compare, ok := filterSliceComparisons[condition]
+	if !ok && condition != filterSliceMatches {
+		return nil, fmt.Errorf("invalid condition for FilterSlice function, %q is not one of \"matches\", \"==\", \"!=\", \"<\", \"<=\", \">\" or \">=\"", condition)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		slice, ok := val.(pcommon.Slice)
+		if !ok {
+			return nil, nil
+		}
+		if err = checkSliceType(slice); err != nil {
+			return nil, err
+		}
+
+		cmpVal, err := value.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var keep func(pcommon.Value) (bool, error)
+		if condition == filterSliceMatches {
+			pattern, ok := cmpVal.(string)
+			if !ok {
+				return nil, fmt.Errorf("FilterSlice requires a string pattern for the %q condition, got %T", condition, cmpVal)
+			}
+			compiledPattern, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("the pattern supplied to FilterSlice is not a valid regexp pattern: %w", err)
+			}
+			keep = func(elem pcommon.Value) (bool, error) {
+				if elem.Type() != pcommon.ValueTypeStr {
+					return false, fmt.Errorf("FilterSlice requires a slice of strings for the %q condition, got %s", condition, elem.Type())
+				}
+				return compiledPattern.MatchString(elem.Str()), nil
+			}
+		} else {
+			threshold, ok := toFloat64(cmpVal)
+			if !ok {
+				return nil, fmt.Errorf("FilterSlice requires a numeric value for the %q condition, got %T", condition, cmpVal)
+			}
+			keep = func(elem pcommon.Value) (bool, error) {
+				switch elem.Type() {
+				case pcommon.ValueTypeInt:
+					return compare(float64(elem.Int()), threshold), nil
+				case pcommon.ValueTypeDouble:
+					return compare(elem.Double(), threshold), nil
+				default:
+					return false, fmt.Errorf("FilterSlice requires a slice of numbers for the %q condition, got %s", condition, elem.Type())
+				}
+			}
+		}
+
+		result := pcommon.NewSlice()
+		for i := 0; i < slice.Len(); i++ {
+			elem := slice.At(i)
+			matched, err := keep(elem)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				elem.CopyTo(result.AppendEmpty())
+			}
+		}
+		return result, nil
+	}, nil
+}
+
+// checkSliceType returns an error if the elements of slice are not all of the same type.
+func checkSliceType(slice pcommon.Slice) error {
+	for i := 1; i < slice.Len(); i++ {
+		if slice.At(i).Type() != slice.At(0).Type() {
+			return fmt.Errorf("FilterSlice does not support mixed-type slices, got %s and %s", slice.At(0).Type(), slice.At(i).Type())
+		}
+	}
+	return nil
+}
+
+func toFloat64(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
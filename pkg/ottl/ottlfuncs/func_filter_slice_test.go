@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_filterSlice(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    interface{}
+		condition string
+		value     interface{}
+		expected  interface{}
+	}{
+		{
+			name:      "strings by regex",
+			target:    []interface{}{"error.timeout", "debug.trace", "error.refused"},
+			condition: "matches",
+			value:     "^error\\.",
+			expected:  []interface{}{"error.timeout", "error.refused"},
+		},
+		{
+			name:      "ints above threshold",
+			target:    []interface{}{int64(1), int64(50), int64(200), int64(7)},
+			condition: ">",
+			value:     int64(10),
+			expected:  []interface{}{int64(50), int64(200)},
+		},
+		{
+			name:      "doubles at or below threshold",
+			target:    []interface{}{0.5, 1.5, 2.5},
+			condition: "<=",
+			value:     1.5,
+			expected:  []interface{}{0.5, 1.5},
+		},
+		{
+			name:      "ints equal to float threshold",
+			target:    []interface{}{int64(1), int64(2), int64(2)},
+			condition: "==",
+			value:     2.0,
+			expected:  []interface{}{int64(2), int64(2)},
+		},
+		{
+			name:      "nothing matches",
+			target:    []interface{}{"a", "b"},
+			condition: "matches",
+			value:     "c",
+			expected:  []interface{}{},
+		},
+		{
+			name:      "empty slice",
+			target:    []interface{}{},
+			condition: "!=",
+			value:     int64(0),
+			expected:  []interface{}{},
+		},
+		{
+			name:      "not a slice",
+			target:    "a,b,c",
+			condition: "matches",
+			value:     "a",
+			expected:  nil,
+		},
+		{
+			name:      "nil target",
+			target:    nil,
+			condition: "matches",
+			value:     "a",
+			expected:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			if raw, ok := tt.target.([]interface{}); ok {
+				slice := pcommon.NewSlice()
+				slice.FromRaw(raw)
+				target = slice
+			}
+			exprFunc, err := FilterSlice[interface{}](literalGetter(target), tt.condition, literalGetter(tt.value))
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_filterSlice_validation(t *testing.T) {
+	_, err := FilterSlice[interface{}](literalGetter(nil), "~=", literalGetter("a"))
+	assert.Error(t, err)
+}
+
+func Test_filterSlice_bad_input(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    []interface{}
+		condition string
+		value     interface{}
+	}{
+		{
+			name:      "mixed-type slice",
+			target:    []interface{}{"a", int64(1)},
+			condition: "matches",
+			value:     "a",
+		},
+		{
+			name:      "mixed numeric slice",
+			target:    []interface{}{int64(1), 2.5},
+			condition: ">",
+			value:     int64(0),
+		},
+		{
+			name:      "regex on numbers",
+			target:    []interface{}{int64(1), int64(2)},
+			condition: "matches",
+			value:     "1",
+		},
+		{
+			name:      "comparison on strings",
+			target:    []interface{}{"a", "b"},
+			condition: ">",
+			value:     int64(1),
+		},
+		{
+			name:      "non-string pattern",
+			target:    []interface{}{"a", "b"},
+			condition: "matches",
+			value:     int64(1),
+		},
+		{
+			name:      "non-numeric threshold",
+			target:    []interface{}{int64(1), int64(2)},
+			condition: "<",
+			value:     "2",
+		},
+		{
+			name:      "invalid regex",
+			target:    []interface{}{"a", "b"},
+			condition: "matches",
+			value:     "(",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slice := pcommon.NewSlice()
+			slice.FromRaw(tt.target)
+			exprFunc, err := FilterSlice[interface{}](literalGetter(slice), tt.condition, literalGetter(tt.value))
+			assert.NoError(t, err)
+			_, err = exprFunc(nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func FlattenSlice[K any](target ottl.Getter[K], depth int64) (ottl.ExprFunc[K], error) {
+	if depth <= 0 {
+		// a negative depth is never decremented to 0, flattening all levels
+		depth = -1
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		slice, ok := val.(pcommon.Slice)
+		if !ok {
+			return nil, nil
+		}
+		result := pcommon.NewSlice()
+		flattenSlice(slice, result, depth)
+		return result, nil
+	}, nil
+}
+
+// flattenSlice appends the elements of src to dst, replacing nested slices by their elements
+// up to depth levels of nesting.
+func flattenSlice(src pcommon.Slice, dst pcommon.Slice, depth int64) {
+	for i := 0; i < src.Len(); i++ {
+		elem := src.At(i)
+		if elem.Type() == pcommon.ValueTypeSlice && depth != 0 {
+			flattenSlice(elem.Slice(), dst, depth-1)
+			continue
+		}
+		elem.CopyTo(dst.AppendEmpty())
+	}
+}
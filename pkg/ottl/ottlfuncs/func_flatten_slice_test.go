@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_flattenSlice(t *testing.T) {
+	// [1, [2, [3, [4]]], "five"]
+	nested := func() pcommon.Slice {
+		s := pcommon.NewSlice()
+		s.FromRaw([]interface{}{int64(1), []interface{}{int64(2), []interface{}{int64(3), []interface{}{int64(4)}}}, "five"})
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		depth    int64
+		expected []interface{}
+	}{
+		{
+			name:     "depth 1",
+			depth:    1,
+			expected: []interface{}{int64(1), int64(2), []interface{}{int64(3), []interface{}{int64(4)}}, "five"},
+		},
+		{
+			name:     "depth 2",
+			depth:    2,
+			expected: []interface{}{int64(1), int64(2), int64(3), []interface{}{int64(4)}, "five"},
+		},
+		{
+			name:     "full",
+			depth:    0,
+			expected: []interface{}{int64(1), int64(2), int64(3), int64(4), "five"},
+		},
+		{
+			name:     "negative depth flattens all levels",
+			depth:    -1,
+			expected: []interface{}{int64(1), int64(2), int64(3), int64(4), "five"},
+		},
+		{
+			name:     "depth exceeding nesting",
+			depth:    10,
+			expected: []interface{}{int64(1), int64(2), int64(3), int64(4), "five"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := nested()
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return original, nil
+				},
+			}
+
+			exprFunc, err := FlattenSlice[interface{}](target, tt.depth)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+			assert.Equal(t, nested(), original)
+		})
+	}
+}
+
+func Test_flattenSlice_empty(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return pcommon.NewSlice(), nil
+		},
+	}
+
+	exprFunc, err := FlattenSlice[interface{}](target, 0)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, pcommon.NewSlice(), result)
+}
+
+func Test_flattenSlice_bad_input(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return "not a slice", nil
+		},
+	}
+
+	exprFunc, err := FlattenSlice[interface{}](target, 0)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
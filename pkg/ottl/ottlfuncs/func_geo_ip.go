@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/oschwald/maxminddb-golang"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// geoIPRecord holds the fields of a MaxMind city database record used by GeoIP.
+type geoIPRecord struct {
+	Country struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+func GeoIP[K any](target ottl.Getter[K], database string) (ottl.ExprFunc[K], error) {
+	// the database is read into memory rather than memory-mapped: functions have no close hook, so a mapped
+	// file would stay mapped after a configuration reload, while the memory is released with the function
+	data, err := os.ReadFile(database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database supplied to GeoIP: %w", err)
+	}
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the database supplied to GeoIP: %w", err)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		ip := net.ParseIP(valStr)
+		if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+			return nil, nil
+		}
+
+		var record geoIPRecord
+		_, found, err := reader.LookupNetwork(ip, &record)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, nil
+		}
+
+		result := pcommon.NewMap()
+		result.PutStr("country", record.Country.Names["en"])
+		result.PutStr("city", record.City.Names["en"])
+		result.PutDouble("latitude", record.Location.Latitude)
+		result.PutDouble("longitude", record.Location.Longitude)
+		return result, nil
+	}, nil
+}
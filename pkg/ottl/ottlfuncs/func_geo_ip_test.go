@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// testdata/geoip/city-test.mmdb, generated by testdata/geoip/main.go, holds the networks
+// 81.2.69.0/24 (London, United Kingdom), 2001:480::/32 (San Diego, United States) and 89.160.20.112/28 (Sweden, without a city).
+var geoIPTestDatabase = filepath.Join("testdata", "geoip", "city-test.mmdb")
+
+func Test_geoIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		ip       interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "ipv4",
+			ip:   "81.2.69.142",
+			expected: map[string]interface{}{
+				"country":   "United Kingdom",
+				"city":      "London",
+				"latitude":  51.5142,
+				"longitude": -0.0931,
+			},
+		},
+		{
+			name: "ipv6",
+			ip:   "2001:480::1",
+			expected: map[string]interface{}{
+				"country":   "United States",
+				"city":      "San Diego",
+				"latitude":  32.7203,
+				"longitude": -117.1552,
+			},
+		},
+		{
+			name: "without city",
+			ip:   "89.160.20.115",
+			expected: map[string]interface{}{
+				"country":   "Sweden",
+				"city":      "",
+				"latitude":  59.3247,
+				"longitude": 18.056,
+			},
+		},
+		{
+			name: "not in database",
+			ip:   "8.8.8.8",
+		},
+		{
+			name: "private ipv4",
+			ip:   "192.168.1.10",
+		},
+		{
+			name: "private ipv6",
+			ip:   "fd12:3456:789a:1::1",
+		},
+		{
+			name: "loopback",
+			ip:   "127.0.0.1",
+		},
+		{
+			name: "not an ip",
+			ip:   "localhost",
+		},
+		{
+			name: "not a string",
+			ip:   int64(1),
+		},
+		{
+			name: "nil",
+			ip:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := GeoIP[interface{}](literalGetter(tt.ip), geoIPTestDatabase)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_geoIP_validation(t *testing.T) {
+	_, err := GeoIP[interface{}](literalGetter("81.2.69.142"), filepath.Join("testdata", "does-not-exist.mmdb"))
+	assert.Error(t, err)
+}
@@ -31,11 +31,10 @@ func IsMatch[K any](target ottl.Getter[K], pattern string) (ottl.ExprFunc[K], er
 		if err != nil {
 			return nil, err
 		}
-		if val != nil {
-			if valStr, ok := val.(string); ok {
-				return compiledPattern.MatchString(valStr), nil
-			}
+		if val == nil {
+			return false, nil
 		}
-		return false, nil
+		// targets that are not strings are matched in the string form Concat uses
+		return compiledPattern.MatchString(toString(val)), nil
 	}, nil
 }
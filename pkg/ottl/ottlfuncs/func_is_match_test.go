@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
@@ -61,15 +62,77 @@ func Test_isMatch(t *testing.T) {
 			expected: true,
 		},
 		{
-			name: "target not a string",
+			name: "target int",
 			target: &ottl.StandardGetSetter[interface{}]{
 				Getter: func(ctx interface{}) (interface{}, error) {
-					return 1, nil
+					return int64(404), nil
 				},
 			},
-			pattern:  "doesnt matter will be false",
+			pattern:  "^4\\d\\d$",
+			expected: true,
+		},
+		{
+			name: "target int not matching",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return int64(200), nil
+				},
+			},
+			pattern:  "^4\\d\\d$",
 			expected: false,
 		},
+		{
+			name: "target float",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return 1.5, nil
+				},
+			},
+			pattern:  "^1\\.5$",
+			expected: true,
+		},
+		{
+			name: "target bool",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return true, nil
+				},
+			},
+			pattern:  "^true$",
+			expected: true,
+		},
+		{
+			name: "target pdata int value",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return pcommon.NewValueInt(8080), nil
+				},
+			},
+			pattern:  "^80",
+			expected: true,
+		},
+		{
+			name: "target map",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					m := pcommon.NewMap()
+					m.PutStr("user", "admin")
+					return m, nil
+				},
+			},
+			pattern:  `"user":"admin"`,
+			expected: true,
+		},
+		{
+			name: "target bytes",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return []byte{0x0e, 0xd2, 0xe6, 0x3c}, nil
+				},
+			},
+			pattern:  "^0ed2e63c$",
+			expected: true,
+		},
 		{
 			name: "target nil",
 			target: &ottl.StandardGetSetter[interface{}]{
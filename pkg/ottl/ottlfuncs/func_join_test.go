@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_join(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    interface{}
+		separator string
+		expected  string
+	}{
+		{
+			name: "strings",
+			target: func() pcommon.Slice {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("A")
+				s.AppendEmpty().SetStr("B")
+				s.AppendEmpty().SetStr("C")
+				return s
+			}(),
+			separator: "|",
+			expected:  "A|B|C",
+		},
+		{
+			name: "numbers and bools",
+			target: func() pcommon.Slice {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetInt(1)
+				s.AppendEmpty().SetDouble(2.5)
+				s.AppendEmpty().SetBool(true)
+				return s
+			}(),
+			separator: ", ",
+			expected:  "1, 2.5, true",
+		},
+		{
+			name: "nested slice and map",
+			target: func() pcommon.Slice {
+				s := pcommon.NewSlice()
+				s.AppendEmpty().SetStr("a")
+				nested := s.AppendEmpty().SetEmptySlice()
+				nested.AppendEmpty().SetInt(1)
+				nested.AppendEmpty().SetStr("b")
+				s.AppendEmpty().SetEmptyMap().PutStr("k", "v")
+				return s
+			}(),
+			separator: " ",
+			expected:  `a [1,"b"] {"k":"v"}`,
+		},
+		{
+			name:      "empty slice",
+			target:    pcommon.NewSlice(),
+			separator: ",",
+			expected:  "",
+		},
+		{
+			name:      "split result",
+			target:    []string{"A", "B", "C"},
+			separator: "-",
+			expected:  "A-B-C",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := Join[interface{}](target, tt.separator)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_join_bad_input(t *testing.T) {
+	for _, input := range []interface{}{"A|B|C", int64(1), nil} {
+		target := &ottl.StandardGetSetter[interface{}]{
+			Getter: func(ctx interface{}) (interface{}, error) {
+				return input, nil
+			},
+		}
+		exprFunc, err := Join[interface{}](target, "|")
+		assert.NoError(t, err)
+		result, err := exprFunc(nil)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_mapAnyKeyMatches(t *testing.T) {
+	input := pcommon.NewMap()
+	input.PutStr("service.name", "frontend")
+	input.PutStr("k8s.pod.name", "payments-7d9f")
+	input.PutInt("http.status_code", 200)
+
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return input, nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		target   ottl.Getter[interface{}]
+		pattern  string
+		expected bool
+	}{
+		{
+			name:     "match",
+			target:   target,
+			pattern:  "^k8s\\.",
+			expected: true,
+		},
+		{
+			name:     "no match",
+			target:   target,
+			pattern:  "^db\\.",
+			expected: false,
+		},
+		{
+			name: "empty map",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return pcommon.NewMap(), nil
+				},
+			},
+			pattern:  ".*",
+			expected: false,
+		},
+		{
+			name: "target not a map",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "k8s.pod.name", nil
+				},
+			},
+			pattern:  ".*",
+			expected: false,
+		},
+		{
+			name: "target nil",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return nil, nil
+				},
+			},
+			pattern:  ".*",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := MapAnyKeyMatches(tt.target, tt.pattern)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_mapAnyKeyMatches_validation(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := MapAnyKeyMatches[interface{}](target, "\\K")
+	require.Error(t, err)
+}
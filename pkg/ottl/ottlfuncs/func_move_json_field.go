@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func MoveJSONField[K any](source ottl.GetSetter[K], path string, target ottl.GetSetter[K]) (ottl.ExprFunc[K], error) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("the path supplied to move_json_field is not a valid path: %q", path)
+		}
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := source.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		decoder := json.NewDecoder(strings.NewReader(str))
+		decoder.UseNumber()
+		var doc map[string]interface{}
+		if err = decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("move_json_field requires a JSON object: %w", err)
+		}
+
+		parent := doc
+		for _, key := range keys[:len(keys)-1] {
+			if parent, ok = parent[key].(map[string]interface{}); !ok {
+				return nil, nil
+			}
+		}
+		field, ok := parent[keys[len(keys)-1]]
+		if !ok {
+			return nil, nil
+		}
+
+		if value := jsonToOTTLValue(field); value != nil {
+			if err = target.Set(ctx, value); err != nil {
+				return nil, err
+			}
+		}
+		delete(parent, keys[len(keys)-1])
+		// encode without escaping the HTML characters, which json.Marshal would rewrite in the untouched fields
+		var updated strings.Builder
+		encoder := json.NewEncoder(&updated)
+		encoder.SetEscapeHTML(false)
+		if err = encoder.Encode(doc); err != nil {
+			return nil, err
+		}
+		return nil, source.Set(ctx, strings.TrimSuffix(updated.String(), "\n"))
+	}, nil
+}
+
+// jsonToOTTLValue converts a decoded JSON value to the type expected by setters,
+// integral numbers become int64 and objects and arrays become a pcommon.Map and pcommon.Slice.
+// Numbers out of range of a float64 keep their text.
+func jsonToOTTLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		m := pcommon.NewMap()
+		m.FromRaw(normalizeJSONNumbers(v).(map[string]interface{}))
+		return m
+	case []interface{}:
+		s := pcommon.NewSlice()
+		s.FromRaw(normalizeJSONNumbers(v).([]interface{}))
+		return s
+	default:
+		return v
+	}
+}
+
+// normalizeJSONNumbers replaces the json.Number values of nested objects and arrays with int64 or float64.
+func normalizeJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return jsonToOTTLValue(v)
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = normalizeJSONNumbers(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = normalizeJSONNumbers(nested)
+		}
+	}
+	return value
+}
@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_moveJSONField(t *testing.T) {
+	nestedMap := pcommon.NewMap()
+	nestedMap.PutStr("id", "abc")
+	nestedMap.PutInt("retries", 2)
+
+	tests := []struct {
+		name           string
+		source         interface{}
+		path           string
+		expectedSource interface{}
+		expected       interface{}
+	}{
+		{
+			name:           "nested string field",
+			source:         `{"user":{"name":"alice","id":42},"msg":"login"}`,
+			path:           "user.name",
+			expectedSource: `{"msg":"login","user":{"id":42}}`,
+			expected:       "alice",
+		},
+		{
+			name:           "int field",
+			source:         `{"status":404,"msg":"not found"}`,
+			path:           "status",
+			expectedSource: `{"msg":"not found"}`,
+			expected:       int64(404),
+		},
+		{
+			name:           "html characters are kept",
+			source:         `{"query":"a<b && c>d","status":200}`,
+			path:           "status",
+			expectedSource: `{"query":"a<b && c>d"}`,
+			expected:       int64(200),
+		},
+		{
+			name:           "float field",
+			source:         `{"ratio":0.5}`,
+			path:           "ratio",
+			expectedSource: `{}`,
+			expected:       0.5,
+		},
+		{
+			name:           "object field",
+			source:         `{"request":{"id":"abc","retries":2}}`,
+			path:           "request",
+			expectedSource: `{}`,
+			expected:       nestedMap,
+		},
+		{
+			name:           "null field",
+			source:         `{"user":null,"msg":"login"}`,
+			path:           "user",
+			expectedSource: `{"msg":"login"}`,
+			expected:       nil,
+		},
+		{
+			name:           "absent field",
+			source:         `{"user":{"id":42}}`,
+			path:           "user.name",
+			expectedSource: `{"user":{"id":42}}`,
+			expected:       nil,
+		},
+		{
+			name:           "absent parent",
+			source:         `{"user":"alice"}`,
+			path:           "user.name",
+			expectedSource: `{"user":"alice"}`,
+			expected:       nil,
+		},
+		{
+			name:           "non string source",
+			source:         int64(1),
+			path:           "user",
+			expectedSource: int64(1),
+			expected:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			var target interface{}
+			sourceGetSetter := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return source, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					source = val
+					return nil
+				},
+			}
+			targetGetSetter := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return target, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					target = val
+					return nil
+				},
+			}
+
+			exprFunc, err := MoveJSONField[interface{}](sourceGetSetter, tt.path, targetGetSetter)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			// the order of the keys of a map unmarshalled from JSON is not defined
+			if expectedMap, ok := tt.expected.(pcommon.Map); ok {
+				require.IsType(t, pcommon.Map{}, target)
+				assert.Equal(t, expectedMap.AsRaw(), target.(pcommon.Map).AsRaw())
+			} else {
+				assert.Equal(t, tt.expected, target)
+			}
+			assert.Equal(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func Test_moveJSONField_invalid_path(t *testing.T) {
+	for _, path := range []string{"", "user.", "user..name"} {
+		_, err := MoveJSONField[interface{}](&ottl.StandardGetSetter[interface{}]{}, path, &ottl.StandardGetSetter[interface{}]{})
+		assert.Error(t, err)
+	}
+}
+
+func Test_moveJSONField_invalid_json(t *testing.T) {
+	source := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return `["not","an","object"]`, nil
+		},
+	}
+	exprFunc, err := MoveJSONField[interface{}](source, "user", &ottl.StandardGetSetter[interface{}]{})
+	require.NoError(t, err)
+	_, err = exprFunc(nil)
+	assert.Error(t, err)
+}
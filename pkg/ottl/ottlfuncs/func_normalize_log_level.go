@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// logLevels maps lower case level names and abbreviations to their canonical level
+var logLevels = map[string]string{
+	"trace":         "TRACE",
+	"trc":           "TRACE",
+	"t":             "TRACE",
+	"finest":        "TRACE",
+	"finer":         "TRACE",
+	"debug":         "DEBUG",
+	"dbg":           "DEBUG",
+	"d":             "DEBUG",
+	"fine":          "DEBUG",
+	"info":          "INFO",
+	"inf":           "INFO",
+	"i":             "INFO",
+	"information":   "INFO",
+	"informational": "INFO",
+	"notice":        "INFO",
+	"warn":          "WARN",
+	"warning":       "WARN",
+	"wrn":           "WARN",
+	"w":             "WARN",
+	"error":         "ERROR",
+	"err":           "ERROR",
+	"e":             "ERROR",
+	"severe":        "ERROR",
+	"fatal":         "FATAL",
+	"ftl":           "FATAL",
+	"f":             "FATAL",
+	"critical":      "FATAL",
+	"crit":          "FATAL",
+	"alert":         "FATAL",
+	"emerg":         "FATAL",
+	"emergency":     "FATAL",
+	"panic":         "FATAL",
+}
+
+func NormalizeLogLevel[K any](target ottl.GetSetter[K], defaultLevel string) (ottl.ExprFunc[K], error) {
+	switch defaultLevel {
+	case "", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL":
+	default:
+		return nil, fmt.Errorf("invalid default level for normalize_log_level function, %q is not one of \"TRACE\", \"DEBUG\", \"INFO\", \"WARN\", \"ERROR\" or \"FATAL\"", defaultLevel)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		level, ok := logLevels[strings.ToLower(strings.TrimSpace(valStr))]
+		if !ok {
+			if defaultLevel == "" {
+				return nil, nil
+			}
+			level = defaultLevel
+		}
+		if level != valStr {
+			err = target.Set(ctx, level)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}, nil
+}
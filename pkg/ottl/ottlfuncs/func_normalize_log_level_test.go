@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_normalizeLogLevel(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Value]{
+		Getter: func(ctx pcommon.Value) (interface{}, error) {
+			return ctx.Str(), nil
+		},
+		Setter: func(ctx pcommon.Value, val interface{}) error {
+			ctx.SetStr(val.(string))
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name         string
+		input        string
+		defaultLevel string
+		expected     string
+	}{
+		{
+			name:     "warn",
+			input:    "warn",
+			expected: "WARN",
+		},
+		{
+			name:     "warning",
+			input:    "warning",
+			expected: "WARN",
+		},
+		{
+			name:     "upper case",
+			input:    "WARNING",
+			expected: "WARN",
+		},
+		{
+			name:     "abbreviation",
+			input:    "W",
+			expected: "WARN",
+		},
+		{
+			name:     "surrounding whitespace",
+			input:    " Info ",
+			expected: "INFO",
+		},
+		{
+			name:     "trace",
+			input:    "trc",
+			expected: "TRACE",
+		},
+		{
+			name:     "debug",
+			input:    "Debug",
+			expected: "DEBUG",
+		},
+		{
+			name:     "error",
+			input:    "err",
+			expected: "ERROR",
+		},
+		{
+			name:     "fatal",
+			input:    "CRITICAL",
+			expected: "FATAL",
+		},
+		{
+			name:     "already canonical",
+			input:    "ERROR",
+			expected: "ERROR",
+		},
+		{
+			name:     "unknown unchanged",
+			input:    "verbose-ish",
+			expected: "verbose-ish",
+		},
+		{
+			name:         "unknown with default",
+			input:        "verbose-ish",
+			defaultLevel: "INFO",
+			expected:     "INFO",
+		},
+		{
+			name:         "known with default",
+			input:        "warning",
+			defaultLevel: "INFO",
+			expected:     "WARN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioValue := pcommon.NewValueStr(tt.input)
+
+			exprFunc, err := NormalizeLogLevel[pcommon.Value](target, tt.defaultLevel)
+			assert.NoError(t, err)
+
+			result, err := exprFunc(scenarioValue)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, pcommon.NewValueStr(tt.expected), scenarioValue)
+		})
+	}
+}
+
+func Test_normalizeLogLevel_validation(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+	}
+	for _, defaultLevel := range []string{"info", "WARNING", "unknown"} {
+		_, err := NormalizeLogLevel[interface{}](target, defaultLevel)
+		assert.Error(t, err)
+	}
+}
+
+func Test_normalizeLogLevel_bad_input(t *testing.T) {
+	input := pcommon.NewValueInt(1)
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx interface{}, val interface{}) error {
+			t.Errorf("nothing should be set in this scenario")
+			return nil
+		},
+	}
+
+	exprFunc, err := NormalizeLogLevel[interface{}](target, "INFO")
+	assert.NoError(t, err)
+
+	result, err := exprFunc(input)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, pcommon.NewValueInt(1), input)
+}
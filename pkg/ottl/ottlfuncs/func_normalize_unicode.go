@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var normalizationForms = map[string]norm.Form{
+	"NFC":  norm.NFC,
+	"NFD":  norm.NFD,
+	"NFKC": norm.NFKC,
+	"NFKD": norm.NFKD,
+}
+
+func NormalizeUnicode[K any](target ottl.GetSetter[K], form string) (ottl.ExprFunc[K], error) {
+	normalizationForm, ok := normalizationForms[form]
+	if !ok {
+		return nil, fmt.Errorf("invalid form for normalize_unicode function, %q is not one of \"NFC\", \"NFD\", \"NFKC\" or \"NFKD\"", form)
+	}
+
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		if normalizationForm.IsNormalString(str) {
+			return nil, nil
+		}
+		if err = target.Set(ctx, normalizationForm.String(str)); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_normalizeUnicode(t *testing.T) {
+	// "cafe" with a precomposed e with acute accent, and with an e followed by a combining acute accent
+	composed := "caf\u00e9"
+	decomposed := "cafe\u0301"
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		form     string
+		expected interface{}
+	}{
+		{
+			name:     "NFC composes",
+			value:    decomposed,
+			form:     "NFC",
+			expected: composed,
+		},
+		{
+			name:     "NFD decomposes",
+			value:    composed,
+			form:     "NFD",
+			expected: decomposed,
+		},
+		{
+			name:     "already normalized",
+			value:    composed,
+			form:     "NFC",
+			expected: composed,
+		},
+		{
+			name:     "NFKC replaces compatibility characters",
+			value:    "\ufb01le \u2460",
+			form:     "NFKC",
+			expected: "file 1",
+		},
+		{
+			name:     "NFKD decomposes compatibility characters",
+			value:    "\ufb01anc\u00e9",
+			form:     "NFKD",
+			expected: "fiance\u0301",
+		},
+		{
+			name:     "non string",
+			value:    int64(1),
+			form:     "NFC",
+			expected: int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return value, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					value = val
+					return nil
+				},
+			}
+			exprFunc, err := NormalizeUnicode[interface{}](target, tt.form)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func Test_normalizeUnicode_composed_equals_decomposed(t *testing.T) {
+	values := []interface{}{"caf\u00e9", "cafe\u0301"}
+	for i := range values {
+		target := &ottl.StandardGetSetter[interface{}]{
+			Getter: func(ctx interface{}) (interface{}, error) {
+				return values[i], nil
+			},
+			Setter: func(ctx interface{}, val interface{}) error {
+				values[i] = val
+				return nil
+			},
+		}
+		exprFunc, err := NormalizeUnicode[interface{}](target, "NFC")
+		require.NoError(t, err)
+		_, err = exprFunc(nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, values[0], values[1])
+}
+
+func Test_normalizeUnicode_bad_form(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := NormalizeUnicode[interface{}](target, "nfc")
+	assert.ErrorContains(t, err, "invalid form for normalize_unicode function")
+}
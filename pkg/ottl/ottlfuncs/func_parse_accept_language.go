@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseAcceptLanguage[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result := pcommon.NewSlice()
+		for _, tag := range parseAcceptLanguage(header) {
+			result.AppendEmpty().SetStr(tag)
+		}
+		return result, nil
+	}, nil
+}
+
+type weightedLanguage struct {
+	tag     string
+	quality float64
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header ordered by decreasing quality,
+// keeping the header order for tags of equal quality. Parsing is best effort: entries without tag are skipped,
+// and a missing or malformed quality is treated as the default quality of 1.
+func parseAcceptLanguage(header string) []string {
+	var languages []weightedLanguage
+	for _, entry := range strings.Split(header, ",") {
+		params := strings.Split(entry, ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				quality = q
+			}
+		}
+		// a quality of 0 marks the language as not acceptable
+		if quality == 0 {
+			continue
+		}
+		languages = append(languages, weightedLanguage{tag: tag, quality: quality})
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
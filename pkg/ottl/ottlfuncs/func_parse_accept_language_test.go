@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected []interface{}
+	}{
+		{
+			name:     "single language",
+			header:   "en-US",
+			expected: []interface{}{"en-US"},
+		},
+		{
+			name:     "ordered by q-value",
+			header:   "fr;q=0.5, en-US, de;q=0.9, en;q=0.8",
+			expected: []interface{}{"en-US", "de", "en", "fr"},
+		},
+		{
+			name:     "equal q-values keep header order",
+			header:   "nl;q=0.7,fr-CH, fr;q=0.7, *;q=0.5",
+			expected: []interface{}{"fr-CH", "nl", "fr", "*"},
+		},
+		{
+			name:     "q-value of 0 is excluded",
+			header:   "en, de;q=0",
+			expected: []interface{}{"en"},
+		},
+		{
+			name:     "malformed entries",
+			header:   "en;q=abc, , ;q=0.5, de;q=2, fr;level=1;q=0.3,",
+			expected: []interface{}{"en", "de", "fr"},
+		},
+		{
+			name:     "empty header",
+			header:   "",
+			expected: []interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.header, nil
+				},
+			}
+
+			exprFunc, err := ParseAcceptLanguage[interface{}](target)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_parseAcceptLanguage_bad_input(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return 1, nil
+		},
+	}
+
+	exprFunc, err := ParseAcceptLanguage[interface{}](target)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
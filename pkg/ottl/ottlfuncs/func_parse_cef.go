@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// cefHeaderFields are the names of the pipe separated CEF header fields, in order
+var cefHeaderFields = []string{"version", "device_vendor", "device_product", "device_version", "signature_id", "name", "severity"}
+
+// cefExtensionKeyPattern matches the start of a CEF extension pair, a key preceded by a space and followed by "="
+var cefExtensionKeyPattern = regexp.MustCompile(`(?:^| )([A-Za-z0-9_.\[\]-]+)=`)
+
+var cefHeaderReplacer = strings.NewReplacer(`\|`, `|`, `\\`, `\`)
+var cefExtensionReplacer = strings.NewReplacer(`\=`, `=`, `\\`, `\`, `\n`, "\n", `\r`, "\r")
+
+func ParseCEF[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseCEF(source)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+func parseCEF(source string) (pcommon.Map, error) {
+	// the CEF message may be preceded by a syslog header
+	start := strings.Index(source, "CEF:")
+	if start < 0 {
+		return pcommon.Map{}, errors.New("invalid CEF message, missing \"CEF:\" prefix")
+	}
+	fields := splitCEFHeader(source[start+len("CEF:"):])
+	if len(fields) != len(cefHeaderFields)+1 {
+		return pcommon.Map{}, fmt.Errorf("invalid CEF header, expected %d fields but got %d", len(cefHeaderFields), len(fields)-1)
+	}
+
+	result := pcommon.NewMap()
+	for i, name := range cefHeaderFields {
+		result.PutStr(name, cefHeaderReplacer.Replace(fields[i]))
+	}
+
+	extensions := result.PutEmptyMap("extensions")
+	extension := strings.TrimLeft(fields[len(fields)-1], " ")
+	matches := cefExtensionKeyPattern.FindAllStringSubmatchIndex(extension, -1)
+	if extension != "" && (len(matches) == 0 || matches[0][0] != 0) {
+		return pcommon.Map{}, fmt.Errorf("invalid CEF extension %q, expected key=value pairs", extension)
+	}
+	for i, match := range matches {
+		end := len(extension)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		key := extension[match[2]:match[3]]
+		extensions.PutStr(key, cefExtensionReplacer.Replace(strings.TrimRight(extension[match[1]:end], " ")))
+	}
+	return result, nil
+}
+
+// splitCEFHeader splits s on the pipes not escaped by a backslash. The header fields are
+// returned escaped, the last element is the remainder of s after the last header field.
+func splitCEFHeader(s string) []string {
+	var fields []string
+	fieldStart := 0
+	for i := 0; i < len(s) && len(fields) < len(cefHeaderFields); i++ {
+		switch s[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case '|':
+			fields = append(fields, s[fieldStart:i])
+			fieldStart = i + 1
+		}
+	}
+	return append(fields, s[fieldStart:])
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseCEF(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]interface{}
+	}{
+		{
+			name:   "standard message",
+			target: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232",
+			expected: map[string]interface{}{
+				"version":        "0",
+				"device_vendor":  "Security",
+				"device_product": "threatmanager",
+				"device_version": "1.0",
+				"signature_id":   "100",
+				"name":           "worm successfully stopped",
+				"severity":       "10",
+				"extensions": map[string]interface{}{
+					"src": "10.0.0.1",
+					"dst": "2.1.2.2",
+					"spt": "1232",
+				},
+			},
+		},
+		{
+			name:   "escaped values",
+			target: `CEF:0|security|threat\|manager|1.0|100|detected a \\ in message|10|act=blocked a \= msg=line one\nline two with spaces cs1Label=C:\\Windows`,
+			expected: map[string]interface{}{
+				"version":        "0",
+				"device_vendor":  "security",
+				"device_product": "threat|manager",
+				"device_version": "1.0",
+				"signature_id":   "100",
+				"name":           `detected a \ in message`,
+				"severity":       "10",
+				"extensions": map[string]interface{}{
+					"act":      "blocked a =",
+					"msg":      "line one\nline two with spaces",
+					"cs1Label": `C:\Windows`,
+				},
+			},
+		},
+		{
+			name:   "syslog prefix and no extension",
+			target: "Sep 19 08:26:10 host CEF:1|Vendor|Product|2.3|signature|name|Low|",
+			expected: map[string]interface{}{
+				"version":        "1",
+				"device_vendor":  "Vendor",
+				"device_product": "Product",
+				"device_version": "2.3",
+				"signature_id":   "signature",
+				"name":           "name",
+				"severity":       "Low",
+				"extensions":     map[string]interface{}{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseCEF[interface{}](target)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			resultMap, ok := result.(pcommon.Map)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, resultMap.AsRaw())
+		})
+	}
+}
+
+func Test_parseCEF_bad_input(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{
+			name:   "missing prefix",
+			target: "0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1",
+		},
+		{
+			name:   "missing header fields",
+			target: "CEF:0|Security|threatmanager|1.0|100|10|src=10.0.0.1",
+		},
+		{
+			name:   "escaped pipe is not a separator",
+			target: `CEF:0|Security|threatmanager|1.0|100|worm\|10\|src=10.0.0.1`,
+		},
+		{
+			name:   "extension without key",
+			target: "CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|not an extension",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseCEF[interface{}](target)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func Test_parseCEF_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseCEF[interface{}](target)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// setCookieAttributes are the attributes of a Set-Cookie header, by lowercase name.
+// The flags have no value.
+var setCookieAttributes = map[string]bool{
+	"domain":      false,
+	"expires":     false,
+	"max-age":     false,
+	"path":        false,
+	"samesite":    false,
+	"secure":      true,
+	"httponly":    true,
+	"partitioned": true,
+}
+
+func ParseCookies[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		header, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		return parseCookies(header), nil
+	}, nil
+}
+
+func parseCookies(header string) pcommon.Map {
+	result := pcommon.NewMap()
+	parts := strings.Split(header, ";")
+	if isSetCookie(parts) {
+		parseSetCookie(parts, result)
+		return result
+	}
+	for _, part := range parts {
+		name, value, ok := cookiePair(part)
+		if !ok {
+			continue
+		}
+		result.PutStr(name, value)
+	}
+	return result
+}
+
+// isSetCookie returns whether the parts of a header are a cookie followed by Set-Cookie attributes,
+// rather than the cookies of a Cookie header.
+func isSetCookie(parts []string) bool {
+	for _, part := range parts[1:] {
+		name, _, _ := strings.Cut(part, "=")
+		if _, ok := setCookieAttributes[strings.ToLower(strings.TrimSpace(name))]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSetCookie puts the cookie of a Set-Cookie header in result, as a map holding its value and attributes.
+func parseSetCookie(parts []string, result pcommon.Map) {
+	name, value, ok := cookiePair(parts[0])
+	if !ok {
+		return
+	}
+	cookie := result.PutEmptyMap(name)
+	cookie.PutStr("value", value)
+	for _, part := range parts[1:] {
+		attrName, attrValue, _ := strings.Cut(part, "=")
+		attrName = strings.ToLower(strings.TrimSpace(attrName))
+		flag, ok := setCookieAttributes[attrName]
+		if !ok {
+			continue
+		}
+		if flag {
+			cookie.PutBool(attrName, true)
+			continue
+		}
+		attrValue = strings.TrimSpace(attrValue)
+		if attrName == "max-age" {
+			if maxAge, err := strconv.ParseInt(attrValue, 10, 64); err == nil {
+				cookie.PutInt(attrName, maxAge)
+			}
+			continue
+		}
+		cookie.PutStr(attrName, attrValue)
+	}
+}
+
+// cookiePair parses a name=value pair, removing the quotes around the value.
+func cookiePair(part string) (string, string, bool) {
+	name, value, found := strings.Cut(part, "=")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return "", "", false
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	return name, value, true
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseCookies(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]interface{}
+	}{
+		{
+			name:   "cookie header",
+			target: `session=abc123; theme=dark; lang="en-US"`,
+			expected: map[string]interface{}{
+				"session": "abc123",
+				"theme":   "dark",
+				"lang":    "en-US",
+			},
+		},
+		{
+			name:   "malformed pairs",
+			target: `session=abc123; flag; =orphan; ;empty=`,
+			expected: map[string]interface{}{
+				"session": "abc123",
+				"empty":   "",
+			},
+		},
+		{
+			name:   "set-cookie header",
+			target: `id=a3fWa; Expires=Thu, 21 Oct 2021 07:28:00 GMT; Max-Age=3600; Domain=example.com; Path=/docs; Secure; HttpOnly; SameSite=Lax`,
+			expected: map[string]interface{}{
+				"id": map[string]interface{}{
+					"value":    "a3fWa",
+					"expires":  "Thu, 21 Oct 2021 07:28:00 GMT",
+					"max-age":  int64(3600),
+					"domain":   "example.com",
+					"path":     "/docs",
+					"secure":   true,
+					"httponly": true,
+					"samesite": "Lax",
+				},
+			},
+		},
+		{
+			name:   "set-cookie header with unknown and malformed attributes",
+			target: `id=a3fWa; path=/; Priority=High; Max-Age=soon`,
+			expected: map[string]interface{}{
+				"id": map[string]interface{}{
+					"value": "a3fWa",
+					"path":  "/",
+				},
+			},
+		},
+		{
+			name:     "set-cookie header with malformed cookie",
+			target:   `a3fWa; Path=/`,
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "empty",
+			target:   "",
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseCookies[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseCookies_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseCookies[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseDN[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseDN(source)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+// parseDN parses a distinguished name as described in RFC 4514, putting the values of repeated attributes in a slice.
+// Multi-valued RDNs, joined with '+', are flattened with the other components.
+func parseDN(source string) (pcommon.Map, error) {
+	if strings.TrimSpace(source) == "" {
+		return pcommon.Map{}, fmt.Errorf("invalid DN, empty DN")
+	}
+	result := pcommon.NewMap()
+	for i := 0; ; {
+		eq := strings.IndexByte(source[i:], '=')
+		if eq < 0 {
+			return pcommon.Map{}, fmt.Errorf("invalid DN at position %d, expected attribute=value: %q", i, source[i:])
+		}
+		attrType := strings.TrimSpace(source[i : i+eq])
+		if attrType == "" || strings.ContainsAny(attrType, ",+\\\"") {
+			return pcommon.Map{}, fmt.Errorf("invalid DN at position %d, invalid attribute type: %q", i, source[i:i+eq])
+		}
+		value, next, err := parseDNValue(source, i+eq+1)
+		if err != nil {
+			return pcommon.Map{}, err
+		}
+		putDNAttribute(result, attrType, value)
+		if next == len(source) {
+			return result, nil
+		}
+		// skip the separator, which must be followed by another component
+		i = next + 1
+		if i == len(source) {
+			return pcommon.Map{}, fmt.Errorf("invalid DN at position %d, trailing separator", next)
+		}
+	}
+}
+
+// parseDNValue parses the attribute value starting at start, returning it unescaped with the
+// position of the separator ending it, or the length of source for the last value.
+func parseDNValue(source string, start int) (string, int, error) {
+	i := start
+	for i < len(source) && source[i] == ' ' {
+		i++
+	}
+	if i < len(source) && source[i] == '"' {
+		return parseQuotedDNValue(source, i)
+	}
+
+	var value []byte
+	// trailing spaces are not part of the value unless escaped
+	keep := 0
+	for i < len(source) && source[i] != ',' && source[i] != '+' {
+		switch c := source[i]; c {
+		case '\\':
+			if i+1 == len(source) {
+				return "", 0, fmt.Errorf("invalid DN at position %d, trailing escape character", i)
+			}
+			if i+2 < len(source) {
+				// an escaped pair of hex digits encodes a single byte
+				if decoded, err := hex.DecodeString(source[i+1 : i+3]); err == nil {
+					value = append(value, decoded[0])
+					i += 3
+					keep = len(value)
+					continue
+				}
+			}
+			value = append(value, source[i+1])
+			i += 2
+			keep = len(value)
+		case '"':
+			return "", 0, fmt.Errorf("invalid DN at position %d, unescaped quote in value", i)
+		default:
+			value = append(value, c)
+			if c != ' ' {
+				keep = len(value)
+			}
+			i++
+		}
+	}
+	return string(value[:keep]), i, nil
+}
+
+// parseQuotedDNValue parses a value enclosed in double quotes starting at start, as accepted by RFC 2253.
+func parseQuotedDNValue(source string, start int) (string, int, error) {
+	var value []byte
+	i := start + 1
+	for ; i < len(source) && source[i] != '"'; i++ {
+		if source[i] == '\\' {
+			i++
+			if i == len(source) {
+				break
+			}
+		}
+		value = append(value, source[i])
+	}
+	if i >= len(source) {
+		return "", 0, fmt.Errorf("invalid DN at position %d, unterminated quoted value", start)
+	}
+	i++
+	for i < len(source) && source[i] == ' ' {
+		i++
+	}
+	if i < len(source) && source[i] != ',' && source[i] != '+' {
+		return "", 0, fmt.Errorf("invalid DN at position %d, unexpected characters after quoted value", i)
+	}
+	return string(value), i, nil
+}
+
+func putDNAttribute(result pcommon.Map, attrType string, value string) {
+	existing, ok := result.Get(attrType)
+	if !ok {
+		result.PutStr(attrType, value)
+		return
+	}
+	if existing.Type() != pcommon.ValueTypeSlice {
+		first := existing.Str()
+		values := existing.SetEmptySlice()
+		values.AppendEmpty().SetStr(first)
+	}
+	existing.Slice().AppendEmpty().SetStr(value)
+}
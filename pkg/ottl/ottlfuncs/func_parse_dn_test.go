@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "repeated attributes",
+			input: "CN=foo,OU=bar,DC=example,DC=com",
+			expected: map[string]interface{}{
+				"CN": "foo",
+				"OU": "bar",
+				"DC": []interface{}{"example", "com"},
+			},
+		},
+		{
+			name:  "attribute repeated three times",
+			input: "CN=jdoe, OU=Engineering, OU=Platform, OU=Observability, O=Example Corp",
+			expected: map[string]interface{}{
+				"CN": "jdoe",
+				"OU": []interface{}{"Engineering", "Platform", "Observability"},
+				"O":  "Example Corp",
+			},
+		},
+		{
+			name:  "escaped characters",
+			input: `CN=Doe\, John,OU=Sales\+Marketing,O=\23Hash\20,L=Caf\C3\A9`,
+			expected: map[string]interface{}{
+				"CN": "Doe, John",
+				"OU": "Sales+Marketing",
+				"O":  "#Hash ",
+				"L":  "Café",
+			},
+		},
+		{
+			name:  "quoted value",
+			input: `CN="Doe, John" ,O=Example`,
+			expected: map[string]interface{}{
+				"CN": "Doe, John",
+				"O":  "Example",
+			},
+		},
+		{
+			name:  "multi-valued RDN",
+			input: "CN=jdoe+UID=1234,DC=example",
+			expected: map[string]interface{}{
+				"CN":  "jdoe",
+				"UID": "1234",
+				"DC":  "example",
+			},
+		},
+		{
+			name:  "empty value",
+			input: "CN=,O=Example",
+			expected: map[string]interface{}{
+				"CN": "",
+				"O":  "Example",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.input, nil
+				},
+			}
+
+			exprFunc, err := ParseDN[interface{}](target)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseDN_error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+		},
+		{
+			name:  "missing equal sign",
+			input: "CN=foo,example",
+		},
+		{
+			name:  "empty attribute type",
+			input: "=foo,O=Example",
+		},
+		{
+			name:  "trailing separator",
+			input: "CN=foo,",
+		},
+		{
+			name:  "trailing escape character",
+			input: `CN=foo\`,
+		},
+		{
+			name:  "unescaped quote",
+			input: `CN=fo"o`,
+		},
+		{
+			name:  "unterminated quoted value",
+			input: `CN="foo,O=Example`,
+		},
+		{
+			name:  "characters after quoted value",
+			input: `CN="foo"bar,O=Example`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.input, nil
+				},
+			}
+
+			exprFunc, err := ParseDN[interface{}](target)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func Test_parseDN_bad_input(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return 1, nil
+		},
+	}
+
+	exprFunc, err := ParseDN[interface{}](target)
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
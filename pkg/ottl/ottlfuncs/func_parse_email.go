@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseEmail[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		address, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		local, domain, err := parseEmail(address)
+		if err != nil {
+			return nil, err
+		}
+		result := pcommon.NewMap()
+		result.PutStr("local", local)
+		result.PutStr("domain", domain)
+		return result, nil
+	}, nil
+}
+
+// parseEmail splits a bare email address such as "jane.doe+tag@example.com" into its local part and its
+// lowercased domain. Addresses with a display name or angle brackets are rejected.
+func parseEmail(address string) (string, string, error) {
+	address = strings.TrimSpace(address)
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid email address %q: %w", address, err)
+	}
+	if parsed.Name != "" || parsed.Address != address {
+		return "", "", fmt.Errorf("invalid email address %q: expected a bare address", address)
+	}
+	at := strings.LastIndex(address, "@")
+	return address[:at], strings.ToLower(address[at+1:]), nil
+}
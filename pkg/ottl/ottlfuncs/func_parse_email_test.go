@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_parseEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "address",
+			target:   "jane.doe@example.com",
+			expected: map[string]interface{}{"local": "jane.doe", "domain": "example.com"},
+		},
+		{
+			name:     "subaddress",
+			target:   "jane.doe+newsletter@Example.COM",
+			expected: map[string]interface{}{"local": "jane.doe+newsletter", "domain": "example.com"},
+		},
+		{
+			name:     "whitespace",
+			target:   " ops@mail.example.org ",
+			expected: map[string]interface{}{"local": "ops", "domain": "mail.example.org"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseEmail[interface{}](literalGetter(tt.target))
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseEmail_invalid(t *testing.T) {
+	for _, address := range []string{"jane.doe", "jane@", "@example.com", "jane doe@example.com", "Jane <jane@example.com>", ""} {
+		t.Run(address, func(t *testing.T) {
+			exprFunc, err := ParseEmail[interface{}](literalGetter(address))
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func Test_parseEmail_not_a_string(t *testing.T) {
+	exprFunc, err := ParseEmail[interface{}](literalGetter(int64(1)))
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
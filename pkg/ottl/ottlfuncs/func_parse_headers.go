@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseHeaders[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		headers, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		return parseHeaders(headers), nil
+	}, nil
+}
+
+func parseHeaders(headers string) pcommon.Map {
+	result := pcommon.NewMap()
+	// previous is the name of the last parsed header, continued by lines starting with whitespace
+	previous := ""
+	for _, line := range strings.Split(headers, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			previous = ""
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if value, ok := result.Get(previous); ok && previous != "" {
+				value.SetStr(strings.TrimSpace(value.Str() + " " + strings.TrimSpace(line)))
+			}
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found || name == "" || strings.ContainsAny(name, " \t") {
+			previous = ""
+			continue
+		}
+		name = strings.ToLower(name)
+		value = strings.TrimSpace(value)
+		if existing, ok := result.Get(name); ok {
+			existing.SetStr(existing.Str() + ", " + value)
+		} else {
+			result.PutStr(name, value)
+		}
+		previous = name
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]interface{}
+	}{
+		{
+			name:   "headers",
+			target: "Host: example.com\r\nContent-Type: application/json\r\nContent-Length:  42 \r\n",
+			expected: map[string]interface{}{
+				"host":           "example.com",
+				"content-type":   "application/json",
+				"content-length": "42",
+			},
+		},
+		{
+			name:   "duplicate headers",
+			target: "Accept: text/html\r\nX-Forwarded-For: 10.0.0.1\r\naccept: application/json\r\nX-Forwarded-For: 10.0.0.2, 10.0.0.3",
+			expected: map[string]interface{}{
+				"accept":          "text/html, application/json",
+				"x-forwarded-for": "10.0.0.1, 10.0.0.2, 10.0.0.3",
+			},
+		},
+		{
+			name:   "malformed lines",
+			target: "GET /index.html HTTP/1.1\r\nHost: example.com\r\nnot a header\r\n: no name\r\nBad Name: value\r\nEmpty:\r\n",
+			expected: map[string]interface{}{
+				"host":  "example.com",
+				"empty": "",
+			},
+		},
+		{
+			name:   "folded lines",
+			target: "X-Long: first part\r\n  second part\r\n\tthird part\r\nHost: example.com",
+			expected: map[string]interface{}{
+				"x-long": "first part second part third part",
+				"host":   "example.com",
+			},
+		},
+		{
+			name:   "line feeds",
+			target: "Host: example.com\nAccept: */*",
+			expected: map[string]interface{}{
+				"host":   "example.com",
+				"accept": "*/*",
+			},
+		},
+		{
+			name:     "empty",
+			target:   "",
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseHeaders[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			require.IsType(t, pcommon.Map{}, result)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseHeaders_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseHeaders[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
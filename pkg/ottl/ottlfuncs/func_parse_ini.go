@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// iniDefaultSection holds the keys preceding the first section header
+const iniDefaultSection = "default"
+
+func ParseINI[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseINI(source)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+func parseINI(source string) (pcommon.Map, error) {
+	result := pcommon.NewMap()
+	var section pcommon.Map
+	inSection := false
+	sectionFor := func(name string) pcommon.Map {
+		if existing, ok := result.Get(name); ok {
+			return existing.Map()
+		}
+		return result.PutEmptyMap(name)
+	}
+
+	for i, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return pcommon.Map{}, fmt.Errorf("invalid INI line %d, unterminated section header: %q", i+1, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return pcommon.Map{}, fmt.Errorf("invalid INI line %d, empty section name", i+1)
+			}
+			section = sectionFor(name)
+			inSection = true
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return pcommon.Map{}, fmt.Errorf("invalid INI line %d, expected key=value: %q", i+1, line)
+		}
+		if !inSection {
+			section = sectionFor(iniDefaultSection)
+			inSection = true
+		}
+		section.PutStr(key, strings.TrimSpace(value))
+	}
+	return result, nil
+}
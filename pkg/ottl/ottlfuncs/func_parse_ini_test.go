@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseINI(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]interface{}
+	}{
+		{
+			name: "sections and comments",
+			target: `; service configuration
+name = checkout
+
+[database]
+# primary only
+host = db.local
+port=5432
+
+[cache]
+url = redis://cache:6379/0?timeout=5s
+`,
+			expected: map[string]interface{}{
+				"default": map[string]interface{}{
+					"name": "checkout",
+				},
+				"database": map[string]interface{}{
+					"host": "db.local",
+					"port": "5432",
+				},
+				"cache": map[string]interface{}{
+					"url": "redis://cache:6379/0?timeout=5s",
+				},
+			},
+		},
+		{
+			name:   "repeated section and key",
+			target: "[a]\nx=1\n[b]\ny=2\n[a]\nx=3\nz=",
+			expected: map[string]interface{}{
+				"a": map[string]interface{}{
+					"x": "3",
+					"z": "",
+				},
+				"b": map[string]interface{}{
+					"y": "2",
+				},
+			},
+		},
+		{
+			name:     "empty",
+			target:   "",
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseINI[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseINI_error(t *testing.T) {
+	for _, source := range []string{
+		"[database\nhost=db",
+		"[]\nhost=db",
+		"[database]\nhost",
+		"[database]\n=db",
+	} {
+		target := &ottl.StandardGetSetter[interface{}]{
+			Getter: func(ctx interface{}) (interface{}, error) {
+				return source, nil
+			},
+		}
+		exprFunc, err := ParseINI[interface{}](target)
+		require.NoError(t, err)
+		result, err := exprFunc(nil)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	}
+}
+
+func Test_parseINI_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseINI[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
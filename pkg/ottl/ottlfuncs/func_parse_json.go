@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseJSON[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseJSON(source)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+func parseJSON(source string) (pcommon.Map, error) {
+	decoder := json.NewDecoder(strings.NewReader(source))
+	// keep the numbers as written so that integers are not turned into floats
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return pcommon.Map{}, fmt.Errorf("ParseJSON failed to parse the target: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return pcommon.Map{}, fmt.Errorf("ParseJSON failed to parse the target: unexpected data after the top-level value")
+	}
+	object, ok := parsed.(map[string]interface{})
+	if !ok {
+		return pcommon.Map{}, fmt.Errorf("ParseJSON requires a JSON object, got %s", jsonTypeName(parsed))
+	}
+
+	return jsonToOTTLValue(object).(pcommon.Map), nil
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	default:
+		return "a number"
+	}
+}
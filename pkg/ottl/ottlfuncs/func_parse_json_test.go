@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected map[string]interface{}
+	}{
+		{
+			name:   "nested objects",
+			target: `{"http":{"request":{"method":"GET","path":"/checkout"},"status":200},"ok":true}`,
+			expected: map[string]interface{}{
+				"http": map[string]interface{}{
+					"request": map[string]interface{}{
+						"method": "GET",
+						"path":   "/checkout",
+					},
+					"status": int64(200),
+				},
+				"ok": true,
+			},
+		},
+		{
+			name:   "array of mixed types",
+			target: `{"items":["a",1,2.5,false,null,{"b":"c"},["d"]]}`,
+			expected: map[string]interface{}{
+				"items": []interface{}{
+					"a",
+					int64(1),
+					2.5,
+					false,
+					nil,
+					map[string]interface{}{"b": "c"},
+					[]interface{}{"d"},
+				},
+			},
+		},
+		{
+			name:   "numeric precision",
+			target: `{"id":9007199254740993,"negative":-42,"ratio":0.1,"exponent":1e3,"huge":1e400}`,
+			expected: map[string]interface{}{
+				"id":       int64(9007199254740993),
+				"negative": int64(-42),
+				"ratio":    0.1,
+				"exponent": 1000.0,
+				"huge":     "1e400",
+			},
+		},
+		{
+			name:   "null value",
+			target: `{"user":null}`,
+			expected: map[string]interface{}{
+				"user": nil,
+			},
+		},
+		{
+			name:     "empty object",
+			target:   ` {} `,
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseJSON[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseJSON_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		errMsg string
+	}{
+		{
+			name:   "invalid JSON",
+			target: `{"user":`,
+			errMsg: "ParseJSON failed to parse the target",
+		},
+		{
+			name:   "trailing data",
+			target: `{"user":"a"} {"user":"b"}`,
+			errMsg: "unexpected data after the top-level value",
+		},
+		{
+			name:   "array",
+			target: `["a","b"]`,
+			errMsg: "ParseJSON requires a JSON object, got an array",
+		},
+		{
+			name:   "string",
+			target: `"a"`,
+			errMsg: "ParseJSON requires a JSON object, got a string",
+		},
+		{
+			name:   "number",
+			target: `1`,
+			errMsg: "ParseJSON requires a JSON object, got a number",
+		},
+		{
+			name:   "null",
+			target: `null`,
+			errMsg: "ParseJSON requires a JSON object, got null",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseJSON[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.ErrorContains(t, err, tt.errMsg)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func Test_parseJSON_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseJSON[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
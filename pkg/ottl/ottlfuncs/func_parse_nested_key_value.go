@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseNestedKeyValue[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+
+		result := pcommon.NewMap()
+		for _, pair := range strings.Fields(source) {
+			key, value, found := strings.Cut(pair, "=")
+			if !found {
+				return nil, fmt.Errorf("cannot parse %q, expected key=value", pair)
+			}
+			if err := putNested(result, key, value); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}, nil
+}
+
+// putNested sets value at the dotted path key, creating intermediate maps as needed.
+// It fails if the path crosses a scalar or ends on a map created by a previous path.
+func putNested(m pcommon.Map, key string, value string) error {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid key %q, empty path segment", key)
+		}
+		existing, ok := m.Get(part)
+		if i == len(parts)-1 {
+			if ok && existing.Type() == pcommon.ValueTypeMap {
+				return fmt.Errorf("conflicting paths, %q is already a map", key)
+			}
+			m.PutStr(part, value)
+			return nil
+		}
+		if !ok {
+			m = m.PutEmptyMap(part)
+			continue
+		}
+		if existing.Type() != pcommon.ValueTypeMap {
+			return fmt.Errorf("conflicting paths, %q is already a value", strings.Join(parts[:i+1], "."))
+		}
+		m = existing.Map()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_parseNestedKeyValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected func() interface{}
+	}{
+		{
+			name:  "flat keys",
+			value: "a=1 b=two",
+			expected: func() interface{} {
+				m := pcommon.NewMap()
+				m.PutStr("a", "1")
+				m.PutStr("b", "two")
+				return m
+			},
+		},
+		{
+			name:  "nested keys",
+			value: "a.b=1 a.c=2 a.d.e=3 f=4",
+			expected: func() interface{} {
+				m := pcommon.NewMap()
+				a := m.PutEmptyMap("a")
+				a.PutStr("b", "1")
+				a.PutStr("c", "2")
+				a.PutEmptyMap("d").PutStr("e", "3")
+				m.PutStr("f", "4")
+				return m
+			},
+		},
+		{
+			name:  "repeated key",
+			value: "a.b=1 a.b=2",
+			expected: func() interface{} {
+				m := pcommon.NewMap()
+				m.PutEmptyMap("a").PutStr("b", "2")
+				return m
+			},
+		},
+		{
+			name:  "empty value",
+			value: "a= b=1=2",
+			expected: func() interface{} {
+				m := pcommon.NewMap()
+				m.PutStr("a", "")
+				m.PutStr("b", "1=2")
+				return m
+			},
+		},
+		{
+			name:  "empty string",
+			value: "",
+			expected: func() interface{} {
+				return pcommon.NewMap()
+			},
+		},
+		{
+			name:  "non-string",
+			value: int64(1),
+			expected: func() interface{} {
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseNestedKeyValue[interface{}](literalGetter(tt.value))
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected(), result)
+		})
+	}
+}
+
+func Test_parseNestedKeyValue_bad_input(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "nested below scalar",
+			value: "a=1 a.b=2",
+		},
+		{
+			name:  "scalar over nested",
+			value: "a.b=1 a=2",
+		},
+		{
+			name:  "missing separator",
+			value: "a=1 b",
+		},
+		{
+			name:  "empty path segment",
+			value: "a..b=1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseNestedKeyValue[interface{}](literalGetter(tt.value))
+			assert.NoError(t, err)
+			_, err = exprFunc(nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseRatio[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ratio, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseRatio(ratio)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+// parseRatio parses a percentage such as "45%" or a fraction such as "3/4"
+func parseRatio(ratio string) (float64, error) {
+	ratio = strings.TrimSpace(ratio)
+	if strings.HasSuffix(ratio, "%") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(ratio, "%")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage %q: %w", ratio, err)
+		}
+		return value / 100, nil
+	}
+	numerator, denominator, found := strings.Cut(ratio, "/")
+	if !found {
+		return 0, fmt.Errorf("invalid ratio %q, expected a percentage or a fraction", ratio)
+	}
+	num, err := strconv.ParseFloat(strings.TrimSpace(numerator), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fraction %q: %w", ratio, err)
+	}
+	den, err := strconv.ParseFloat(strings.TrimSpace(denominator), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fraction %q: %w", ratio, err)
+	}
+	if den == 0 {
+		return 0, fmt.Errorf("invalid fraction %q: division by zero", ratio)
+	}
+	return num / den, nil
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   interface{}
+		expected interface{}
+	}{
+		{
+			name:     "percentage",
+			target:   "45%",
+			expected: 0.45,
+		},
+		{
+			name:     "decimal percentage",
+			target:   " 12.5 % ",
+			expected: 0.125,
+		},
+		{
+			name:     "fraction",
+			target:   "3/4",
+			expected: 0.75,
+		},
+		{
+			name:     "fraction with whitespace",
+			target:   "1 / 8",
+			expected: 0.125,
+		},
+		{
+			name:     "not a string",
+			target:   int64(1),
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseRatio[interface{}](&ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			})
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.InDelta(t, tt.expected, result, 1e-9)
+		})
+	}
+}
+
+func Test_parseRatio_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{
+			name:   "invalid percentage",
+			target: "abc%",
+		},
+		{
+			name:   "invalid numerator",
+			target: "x/4",
+		},
+		{
+			name:   "invalid denominator",
+			target: "3/",
+		},
+		{
+			name:   "division by zero",
+			target: "3/0",
+		},
+		{
+			name:   "neither percentage nor fraction",
+			target: "0.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseRatio[interface{}](&ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			})
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var stacktraceParsers = map[string]func(lines []string, result pcommon.Map){
+	"java": parseJavaStacktrace,
+	"go":   parseGoStacktrace,
+}
+
+var (
+	// javaFrame matches a frame such as `at com.example.Foo.bar(Foo.java:42)`.
+	javaFrame = regexp.MustCompile(`^at\s+(\S+?)\((.*)\)$`)
+	// goFrameLocation matches the location of a frame such as `/src/main.go:8 +0x1d`.
+	goFrameLocation = regexp.MustCompile(`^(.+):(\d+)(?:\s+\+0x[0-9a-f]+)?$`)
+)
+
+func ParseStacktrace[K any](target ottl.Getter[K], language string) (ottl.ExprFunc[K], error) {
+	parse, ok := stacktraceParsers[language]
+	if !ok {
+		return nil, fmt.Errorf("invalid language for ParseStacktrace function, %q is not one of \"java\" or \"go\"", language)
+	}
+
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stacktrace, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result := pcommon.NewMap()
+		parse(strings.Split(strings.ReplaceAll(stacktrace, "\r\n", "\n"), "\n"), result)
+		return result, nil
+	}, nil
+}
+
+// parseJavaStacktrace parses the exception and frames of a Java stacktrace.
+// The causes of the exception, following `Caused by:`, are ignored.
+func parseJavaStacktrace(lines []string, result pcommon.Map) {
+	frames := result.PutEmptySlice("frames")
+	header := true
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if header {
+			header = false
+			exceptionType, message, found := strings.Cut(line, ":")
+			result.PutStr("exception.type", strings.TrimSpace(exceptionType))
+			if found {
+				result.PutStr("exception.message", strings.TrimSpace(message))
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "Caused by:") {
+			break
+		}
+		match := javaFrame.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		frame := frames.AppendEmpty().SetEmptyMap()
+		frame.PutStr("function", match[1])
+		file, lineNumber, found := strings.Cut(match[2], ":")
+		frame.PutStr("file", file)
+		if n, err := strconv.ParseInt(lineNumber, 10, 64); found && err == nil {
+			frame.PutInt("line", n)
+		}
+	}
+}
+
+// parseGoStacktrace parses the panic and frames of the first goroutine of a Go stacktrace.
+func parseGoStacktrace(lines []string, result pcommon.Map) {
+	frames := result.PutEmptySlice("frames")
+	inGoroutine := false
+	var function string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inGoroutine && strings.HasPrefix(trimmed, "panic: "):
+			if _, ok := result.Get("exception.type"); !ok {
+				result.PutStr("exception.type", "panic")
+				result.PutStr("exception.message", strings.TrimPrefix(trimmed, "panic: "))
+			}
+		case !inGoroutine && strings.HasPrefix(trimmed, "goroutine "):
+			inGoroutine = true
+		case !inGoroutine:
+		case trimmed == "" && frames.Len() > 0, strings.HasPrefix(trimmed, "created by "):
+			// the frames of the first goroutine are over
+			return
+		case trimmed == "":
+		case strings.HasPrefix(line, "\t") || strings.HasPrefix(line, " "):
+			match := goFrameLocation.FindStringSubmatch(trimmed)
+			if match == nil || function == "" {
+				continue
+			}
+			frame := frames.AppendEmpty().SetEmptyMap()
+			frame.PutStr("function", function)
+			frame.PutStr("file", match[1])
+			if n, err := strconv.ParseInt(match[2], 10, 64); err == nil {
+				frame.PutInt("line", n)
+			}
+			function = ""
+		default:
+			function = goFunctionName(trimmed)
+		}
+	}
+}
+
+// goFunctionName removes the arguments from a function line such as `main.(*T).run(0xc000012345, 0x3)`.
+func goFunctionName(line string) string {
+	if i := strings.LastIndex(line, "("); i > 0 && strings.HasSuffix(line, ")") {
+		return line[:i]
+	}
+	return line
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseStacktrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		language string
+		expected map[string]interface{}
+	}{
+		{
+			name: "java",
+			target: "java.lang.IllegalStateException: could not process order: 42\n" +
+				"\tat com.example.OrderService.process(OrderService.java:87)\n" +
+				"\tat com.example.Main.main(Main.java:12)\n" +
+				"\tat java.base/jdk.internal.reflect.NativeMethodAccessorImpl.invoke0(Native Method)\n" +
+				"Caused by: java.io.IOException: connection reset\n" +
+				"\tat com.example.Client.read(Client.java:30)\n" +
+				"\t... 2 more\n",
+			language: "java",
+			expected: map[string]interface{}{
+				"exception.type":    "java.lang.IllegalStateException",
+				"exception.message": "could not process order: 42",
+				"frames": []interface{}{
+					map[string]interface{}{"function": "com.example.OrderService.process", "file": "OrderService.java", "line": int64(87)},
+					map[string]interface{}{"function": "com.example.Main.main", "file": "Main.java", "line": int64(12)},
+					map[string]interface{}{"function": "java.base/jdk.internal.reflect.NativeMethodAccessorImpl.invoke0", "file": "Native Method"},
+				},
+			},
+		},
+		{
+			name:     "java without message",
+			target:   "java.lang.NullPointerException\n    at com.example.Foo.bar(Foo.java:5)",
+			language: "java",
+			expected: map[string]interface{}{
+				"exception.type": "java.lang.NullPointerException",
+				"frames": []interface{}{
+					map[string]interface{}{"function": "com.example.Foo.bar", "file": "Foo.java", "line": int64(5)},
+				},
+			},
+		},
+		{
+			name: "go",
+			target: "panic: runtime error: index out of range [3] with length 3\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.(*handler).serve(0xc000012345, {0x4b8f20, 0x3})\n" +
+				"\t/src/app/handler.go:27 +0x1d\n" +
+				"main.main()\n" +
+				"\t/src/app/main.go:8 +0x45\n" +
+				"\n" +
+				"goroutine 6 [chan receive]:\n" +
+				"main.worker()\n" +
+				"\t/src/app/worker.go:14 +0x2a\n" +
+				"exit status 2\n",
+			language: "go",
+			expected: map[string]interface{}{
+				"exception.type":    "panic",
+				"exception.message": "runtime error: index out of range [3] with length 3",
+				"frames": []interface{}{
+					map[string]interface{}{"function": "main.(*handler).serve", "file": "/src/app/handler.go", "line": int64(27)},
+					map[string]interface{}{"function": "main.main", "file": "/src/app/main.go", "line": int64(8)},
+				},
+			},
+		},
+		{
+			name: "go without panic",
+			target: "goroutine 1 [running]:\n" +
+				"main.main()\n" +
+				"\t/src/app/main.go:8 +0x45\n" +
+				"created by main.init in goroutine 1\n" +
+				"\t/src/app/main.go:3 +0x10\n",
+			language: "go",
+			expected: map[string]interface{}{
+				"frames": []interface{}{
+					map[string]interface{}{"function": "main.main", "file": "/src/app/main.go", "line": int64(8)},
+				},
+			},
+		},
+		{
+			name:     "unusual format",
+			target:   "something went wrong",
+			language: "go",
+			expected: map[string]interface{}{
+				"frames": []interface{}{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseStacktrace[interface{}](target, tt.language)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			require.IsType(t, pcommon.Map{}, result)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseStacktrace_invalid_language(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := ParseStacktrace[interface{}](target, "python")
+	assert.Error(t, err)
+}
+
+func Test_parseStacktrace_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseStacktrace[interface{}](target, "java")
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ParseVersion[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("ParseVersion requires a string value, got %T", val)
+		}
+		version, err := parseVersion(valStr)
+		if err != nil {
+			return nil, err
+		}
+		result := pcommon.NewMap()
+		result.PutInt("major", version[0])
+		result.PutInt("minor", version[1])
+		result.PutInt("patch", version[2])
+		return result, nil
+	}, nil
+}
+
+// parseVersion parses a semantic version string into its major, minor and patch components.
+// A leading "v" is allowed, missing minor or patch components default to 0 and
+// pre-release or build metadata suffixes are ignored.
+func parseVersion(version string) ([3]int64, error) {
+	var result [3]int64
+	core := strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > len(result) {
+		return result, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 63)
+		if err != nil {
+			return result, fmt.Errorf("invalid version %q", version)
+		}
+		result[i] = int64(n)
+	}
+	return result, nil
+}
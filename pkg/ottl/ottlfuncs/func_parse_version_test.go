@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_parseVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "full version",
+			version:  "1.22.3",
+			expected: map[string]interface{}{"major": int64(1), "minor": int64(22), "patch": int64(3)},
+		},
+		{
+			name:     "prefixed version with pre-release and build metadata",
+			version:  "v2.0.1-rc.1+build.5",
+			expected: map[string]interface{}{"major": int64(2), "minor": int64(0), "patch": int64(1)},
+		},
+		{
+			name:     "major and minor only",
+			version:  "1.4",
+			expected: map[string]interface{}{"major": int64(1), "minor": int64(4), "patch": int64(0)},
+		},
+		{
+			name:     "major only",
+			version:  "3",
+			expected: map[string]interface{}{"major": int64(3), "minor": int64(0), "patch": int64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.version, nil
+				},
+			}
+			exprFunc, err := ParseVersion[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseVersion_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name:  "empty",
+			value: "",
+		},
+		{
+			name:  "too many components",
+			value: "1.2.3.4",
+		},
+		{
+			name:  "non-numeric component",
+			value: "1.x.3",
+		},
+		{
+			name:  "negative component",
+			value: "1.-2.3",
+		},
+		{
+			name:  "non-string",
+			value: int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.value, nil
+				},
+			}
+			exprFunc, err := ParseVersion[interface{}](target)
+			require.NoError(t, err)
+			_, err = exprFunc(nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_parseVersion_get_nil(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}
+	exprFunc, err := ParseVersion[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
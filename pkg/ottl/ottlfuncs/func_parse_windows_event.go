@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// windowsEvent is the part of the XML rendering of a Windows event which is parsed
+type windowsEvent struct {
+	XMLName xml.Name `xml:"Event"`
+	System  struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID string `xml:"EventID"`
+		Level   string `xml:"Level"`
+		Channel string `xml:"Channel"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+}
+
+func ParseWindowsEvent[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		source, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		result, err := parseWindowsEvent(source)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}, nil
+}
+
+func parseWindowsEvent(source string) (pcommon.Map, error) {
+	var event windowsEvent
+	if err := xml.Unmarshal([]byte(source), &event); err != nil {
+		return pcommon.Map{}, fmt.Errorf("invalid Windows event XML: %w", err)
+	}
+
+	result := pcommon.NewMap()
+	if err := putWindowsEventInt(result, "EventID", event.System.EventID); err != nil {
+		return pcommon.Map{}, err
+	}
+	if err := putWindowsEventInt(result, "Level", event.System.Level); err != nil {
+		return pcommon.Map{}, err
+	}
+	result.PutStr("Provider", event.System.Provider.Name)
+	result.PutStr("Channel", strings.TrimSpace(event.System.Channel))
+
+	eventData := result.PutEmptyMap("EventData")
+	var unnamed []string
+	for _, data := range event.EventData.Data {
+		if data.Name == "" {
+			unnamed = append(unnamed, data.Value)
+			continue
+		}
+		eventData.PutStr(data.Name, data.Value)
+	}
+	// classic events hold unnamed data elements, which are kept in order
+	if len(unnamed) > 0 {
+		values := eventData.PutEmptySlice("Data")
+		for _, value := range unnamed {
+			values.AppendEmpty().SetStr(value)
+		}
+	}
+	return result, nil
+}
+
+// putWindowsEventInt puts the numeric value of a System element, elements which are absent are skipped
+func putWindowsEventInt(m pcommon.Map, key string, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Windows event XML, %s is not a number: %q", key, value)
+	}
+	m.PutInt(key, i)
+	return nil
+}
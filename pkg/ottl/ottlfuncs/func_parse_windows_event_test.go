@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const testWindowsEvent = `<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Microsoft-Windows-Security-Auditing" Guid="{54849625-5478-4994-a5ba-3e3b0328c30d}"/>
+    <EventID>4624</EventID>
+    <Version>2</Version>
+    <Level>0</Level>
+    <Task>12544</Task>
+    <Keywords>0x8020000000000000</Keywords>
+    <TimeCreated SystemTime="2022-11-07T10:02:45.523786500Z"/>
+    <EventRecordID>36591</EventRecordID>
+    <Channel>Security</Channel>
+    <Computer>WIN-HOST</Computer>
+  </System>
+  <EventData>
+    <Data Name="SubjectUserSid">S-1-5-18</Data>
+    <Data Name="TargetUserName">alice</Data>
+    <Data Name="LogonType">2</Data>
+  </EventData>
+</Event>`
+
+func Test_parseWindowsEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected func() pcommon.Map
+	}{
+		{
+			name:   "security event",
+			target: testWindowsEvent,
+			expected: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutInt("EventID", 4624)
+				m.PutInt("Level", 0)
+				m.PutStr("Provider", "Microsoft-Windows-Security-Auditing")
+				m.PutStr("Channel", "Security")
+				eventData := m.PutEmptyMap("EventData")
+				eventData.PutStr("SubjectUserSid", "S-1-5-18")
+				eventData.PutStr("TargetUserName", "alice")
+				eventData.PutStr("LogonType", "2")
+				return m
+			},
+		},
+		{
+			name:   "unnamed event data",
+			target: `<Event><System><Provider Name="Application Error"/><EventID Qualifiers="0">1000</EventID><Level>2</Level><Channel>Application</Channel></System><EventData><Data>app.exe</Data><Data>1.0.0.0</Data></EventData></Event>`,
+			expected: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutInt("EventID", 1000)
+				m.PutInt("Level", 2)
+				m.PutStr("Provider", "Application Error")
+				m.PutStr("Channel", "Application")
+				data := m.PutEmptyMap("EventData").PutEmptySlice("Data")
+				data.AppendEmpty().SetStr("app.exe")
+				data.AppendEmpty().SetStr("1.0.0.0")
+				return m
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := ParseWindowsEvent[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected().AsRaw(), result.(pcommon.Map).AsRaw())
+		})
+	}
+}
+
+func Test_parseWindowsEvent_error(t *testing.T) {
+	for _, source := range []string{
+		"<Event><System><EventID>4624</EventID></System>",
+		"<Log></Log>",
+		"<Event><System><EventID>login</EventID></System></Event>",
+	} {
+		target := &ottl.StandardGetSetter[interface{}]{
+			Getter: func(ctx interface{}) (interface{}, error) {
+				return source, nil
+			},
+		}
+		exprFunc, err := ParseWindowsEvent[interface{}](target)
+		require.NoError(t, err)
+		result, err := exprFunc(nil)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	}
+}
+
+func Test_parseWindowsEvent_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return int64(1), nil
+		},
+	}
+	exprFunc, err := ParseWindowsEvent[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
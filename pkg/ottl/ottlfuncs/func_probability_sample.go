@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func ProbabilitySample[K any](target ottl.Getter[K], ratio float64) (ottl.ExprFunc[K], error) {
+	if ratio < 0 || ratio > 1 || math.IsNaN(ratio) {
+		return nil, fmt.Errorf("invalid ratio for ProbabilitySample function, %v must be between 0 and 1", ratio)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var input string
+		switch v := val.(type) {
+		case string:
+			input = v
+		case pcommon.TraceID:
+			// sampled like its hex string so that trace_id and trace_id.string sample the same spans
+			input = v.HexString()
+		default:
+			return nil, nil
+		}
+		return sampled(input, ratio), nil
+	}, nil
+}
+
+// sampled returns whether input falls within the sampled ratio, based on its SHA-256 hash so that
+// the same input is always sampled the same way. Faster hashes like FNV are not uniform enough for
+// similar inputs such as sequential IDs.
+func sampled(input string, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	sum := sha256.Sum256([]byte(input))
+	return float64(binary.BigEndian.Uint64(sum[:8])) < ratio*math.MaxUint64
+}
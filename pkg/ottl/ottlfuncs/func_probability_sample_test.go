@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_ProbabilitySample(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float64
+	}{
+		{
+			name:  "none",
+			ratio: 0,
+		},
+		{
+			name:  "ten percent",
+			ratio: 0.1,
+		},
+		{
+			name:  "half",
+			ratio: 0.5,
+		},
+		{
+			name:  "all",
+			ratio: 1,
+		},
+	}
+	const inputs = 10000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			for i := 0; i < inputs; i++ {
+				exprFunc, err := ProbabilitySample[interface{}](literalGetter("trace-"+strconv.Itoa(i)), tt.ratio)
+				require.NoError(t, err)
+				result, err := exprFunc(nil)
+				require.NoError(t, err)
+				if result.(bool) {
+					count++
+				}
+			}
+			assert.InDelta(t, tt.ratio*inputs, count, 0.02*inputs)
+		})
+	}
+}
+
+func Test_ProbabilitySample_deterministic(t *testing.T) {
+	exprFunc, err := ProbabilitySample[interface{}](literalGetter("4bf92f3577b34da6a3ce929d0e0e4736"), 0.5)
+	require.NoError(t, err)
+	expected, err := exprFunc(nil)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		result, err := exprFunc(nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+}
+
+func Test_ProbabilitySample_traceID(t *testing.T) {
+	traceID := pcommon.TraceID([16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36})
+	for _, ratio := range []float64{0.1, 0.3, 0.5, 0.7, 0.9} {
+		fromID, err := ProbabilitySample[interface{}](literalGetter(traceID), ratio)
+		require.NoError(t, err)
+		fromString, err := ProbabilitySample[interface{}](literalGetter(traceID.HexString()), ratio)
+		require.NoError(t, err)
+
+		expected, err := fromString(nil)
+		require.NoError(t, err)
+		result, err := fromID(nil)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+}
+
+func Test_ProbabilitySample_validation(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.5} {
+		_, err := ProbabilitySample[interface{}](literalGetter("a"), ratio)
+		assert.Error(t, err)
+	}
+}
+
+func Test_ProbabilitySample_bad_input(t *testing.T) {
+	exprFunc, err := ProbabilitySample[interface{}](literalGetter(int64(1)), 0.5)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
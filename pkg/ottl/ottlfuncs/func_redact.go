@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// defaultRedactionToken replaces the matches of redact patterns if no replacement is supplied
+const defaultRedactionToken = "****"
+
+func Redact[K any](target ottl.GetSetter[K], patterns []string, replacement string) (ottl.ExprFunc[K], error) {
+	compiledPatterns := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiledPattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the regex pattern supplied to redact is not a valid pattern: %w", err)
+		}
+		compiledPatterns[i] = compiledPattern
+	}
+	if replacement == "" {
+		replacement = defaultRedactionToken
+	}
+
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		redacted := str
+		for _, compiledPattern := range compiledPatterns {
+			redacted = compiledPattern.ReplaceAllLiteralString(redacted, replacement)
+		}
+		if redacted != str {
+			if err = target.Set(ctx, redacted); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_redact(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       interface{}
+		patterns    []string
+		replacement string
+		expected    interface{}
+	}{
+		{
+			name:     "email and token",
+			value:    "login of alice@example.com with token=abc123def failed",
+			patterns: []string{`[\w.+-]+@[\w-]+\.[\w.]+`, `token=\w+`},
+			expected: "login of **** with **** failed",
+		},
+		{
+			name:        "custom replacement",
+			value:       "card 4111 1111 1111 1111 declined",
+			patterns:    []string{`\d{4}( \d{4}){3}`},
+			replacement: "[REDACTED]",
+			expected:    "card [REDACTED] declined",
+		},
+		{
+			name:     "no match",
+			value:    "nothing to hide",
+			patterns: []string{`token=\w+`},
+			expected: "nothing to hide",
+		},
+		{
+			name:     "non string",
+			value:    int64(1),
+			patterns: []string{`\d`},
+			expected: int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return value, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					value = val
+					return nil
+				},
+			}
+			exprFunc, err := Redact[interface{}](target, tt.patterns, tt.replacement)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func Test_redact_bad_pattern(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := Redact[interface{}](target, []string{`token=\w+`, `(`}, "")
+	assert.ErrorContains(t, err, "the regex pattern supplied to redact is not a valid pattern")
+}
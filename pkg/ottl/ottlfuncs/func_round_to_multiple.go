@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func RoundToMultiple[K any](target ottl.Getter[K], multiple float64) (ottl.ExprFunc[K], error) {
+	if multiple == 0 {
+		return nil, fmt.Errorf("invalid multiple for RoundToMultiple function, multiple cannot be zero")
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch v := val.(type) {
+		case int64:
+			return int64(math.Round(float64(v)/multiple) * multiple), nil
+		case float64:
+			return math.Round(v/multiple) * multiple, nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("RoundToMultiple requires a numeric value, got %T", val)
+		}
+	}, nil
+}
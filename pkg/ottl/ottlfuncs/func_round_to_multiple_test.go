@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_roundToMultiple(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		multiple float64
+		expected interface{}
+	}{
+		{
+			name:     "int rounded down",
+			value:    int64(123),
+			multiple: 10,
+			expected: int64(120),
+		},
+		{
+			name:     "int rounded up",
+			value:    int64(126),
+			multiple: 10,
+			expected: int64(130),
+		},
+		{
+			name:     "float rounded down",
+			value:    1.24,
+			multiple: 0.5,
+			expected: 1.0,
+		},
+		{
+			name:     "float rounded up",
+			value:    1.26,
+			multiple: 0.5,
+			expected: 1.5,
+		},
+		{
+			name:     "negative value",
+			value:    int64(-17),
+			multiple: 5,
+			expected: int64(-15),
+		},
+		{
+			name:     "nil value",
+			value:    nil,
+			multiple: 10,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.value, nil
+				},
+			}
+			exprFunc, err := RoundToMultiple[interface{}](target, tt.multiple)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_roundToMultiple_validation(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := RoundToMultiple[interface{}](target, 0)
+	assert.Error(t, err)
+}
+
+func Test_roundToMultiple_bad_input(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return "not a number", nil
+		},
+	}
+	exprFunc, err := RoundToMultiple[interface{}](target, 10)
+	assert.NoError(t, err)
+	_, err = exprFunc(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func SanitizeMetricName[K any](target ottl.GetSetter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if valStr, ok := val.(string); ok {
+			if sanitized := sanitizeMetricName(valStr); sanitized != valStr {
+				err = target.Set(ctx, sanitized)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// sanitizeMetricName rewrites name to match the Prometheus metric name pattern [a-zA-Z_:][a-zA-Z0-9_:]*.
+// Illegal characters are replaced by underscores, runs of underscores are collapsed into one
+// and a name starting with a digit is prefixed with an underscore.
+func sanitizeMetricName(name string) string {
+	if name == "" {
+		return name
+	}
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+	underscore := false
+	if name[0] >= '0' && name[0] <= '9' {
+		b.WriteByte('_')
+		underscore = true
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == ':':
+			b.WriteRune(r)
+			underscore = false
+		case !underscore:
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return b.String()
+}
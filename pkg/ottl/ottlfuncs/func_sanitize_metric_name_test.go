@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_sanitizeMetricName(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Value]{
+		Getter: func(ctx pcommon.Value) (interface{}, error) {
+			return ctx.Str(), nil
+		},
+		Setter: func(ctx pcommon.Value, val interface{}) error {
+			ctx.SetStr(val.(string))
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "dots",
+			input:    "http.server.duration",
+			expected: "http_server_duration",
+		},
+		{
+			name:     "dashes",
+			input:    "jvm-memory-used",
+			expected: "jvm_memory_used",
+		},
+		{
+			name:     "leading digit",
+			input:    "5xx.responses",
+			expected: "_5xx_responses",
+		},
+		{
+			name:     "collapsed runs",
+			input:    "cpu -- usage..total__seconds",
+			expected: "cpu_usage_total_seconds",
+		},
+		{
+			name:     "non ascii",
+			input:    "température.°C",
+			expected: "temp_rature_C",
+		},
+		{
+			name:     "valid",
+			input:    "namespace:http_requests_total",
+			expected: "namespace:http_requests_total",
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioValue := pcommon.NewValueStr(tt.input)
+
+			exprFunc, err := SanitizeMetricName[pcommon.Value](target)
+			assert.NoError(t, err)
+
+			result, err := exprFunc(scenarioValue)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, pcommon.NewValueStr(tt.expected), scenarioValue)
+		})
+	}
+}
+
+func Test_sanitizeMetricName_bad_input(t *testing.T) {
+	input := pcommon.NewValueInt(1)
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx interface{}, val interface{}) error {
+			t.Errorf("nothing should be set in this scenario")
+			return nil
+		},
+	}
+
+	exprFunc, err := SanitizeMetricName[interface{}](target)
+	assert.NoError(t, err)
+
+	result, err := exprFunc(input)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, pcommon.NewValueInt(1), input)
+}
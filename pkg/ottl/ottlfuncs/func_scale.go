@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Scale[K any](target ottl.GetSetter[K], factor float64) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var scaled interface{}
+		switch v := val.(type) {
+		case int64:
+			result := float64(v) * factor
+			if result == math.Trunc(result) && result >= math.MinInt64 && result < math.MaxInt64 {
+				scaled = int64(result)
+			} else {
+				scaled = result
+			}
+		case float64:
+			scaled = v * factor
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("scale requires a numeric value, got %T", val)
+		}
+		return nil, target.Set(ctx, scaled)
+	}, nil
+}
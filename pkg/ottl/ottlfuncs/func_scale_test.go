@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_scale(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		factor   float64
+		expected interface{}
+	}{
+		{
+			name:     "int",
+			value:    int64(3000000),
+			factor:   0.000001,
+			expected: int64(3),
+		},
+		{
+			name:     "int with fractional result",
+			value:    int64(1500000),
+			factor:   0.000001,
+			expected: 1.5,
+		},
+		{
+			name:     "float",
+			value:    2.5,
+			factor:   4,
+			expected: 10.0,
+		},
+		{
+			name:     "nil value",
+			value:    nil,
+			factor:   10,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return value, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					value = val
+					return nil
+				},
+			}
+			exprFunc, err := Scale[interface{}](target, tt.factor)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func Test_scale_non_numeric(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return "1024", nil
+		},
+	}
+	exprFunc, err := Scale[interface{}](target, 2)
+	assert.NoError(t, err)
+	_, err = exprFunc(nil)
+	assert.EqualError(t, err, "scale requires a numeric value, got string")
+}
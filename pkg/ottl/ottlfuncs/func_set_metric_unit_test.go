@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_setMetricUnit(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{
+			name:  "set unit",
+			value: "new unit",
+			want:  "new unit",
+		},
+		{
+			name:  "set empty unit",
+			value: "",
+			want:  "",
+		},
+		{
+			name:  "non-string value",
+			value: int64(1),
+			want:  "original unit",
+		},
+		{
+			name:  "nil value",
+			value: nil,
+			want:  "original unit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := pmetric.NewMetric()
+			metric.SetUnit("original unit")
+
+			getter := ottl.StandardGetSetter[testMetricContext]{
+				Getter: func(ctx testMetricContext) (interface{}, error) {
+					return tt.value, nil
+				},
+			}
+
+			exprFunc, err := SetMetricUnit[testMetricContext](getter)
+			assert.NoError(t, err)
+
+			result, err := exprFunc(testMetricContext{metric: metric})
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, tt.want, metric.Unit())
+		})
+	}
+}
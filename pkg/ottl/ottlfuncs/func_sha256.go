@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func SHA256[K any](target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := sha256Input(val)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:]), nil
+	}, nil
+}
+
+// sha256Input returns the bytes to hash for val: strings and bytes are hashed as is,
+// other values are hashed using their string representation.
+func sha256Input(val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return nil, fmt.Errorf("SHA256 requires a non-nil target")
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case bool:
+		return []byte(pcommon.NewValueBool(v).AsString()), nil
+	case int64:
+		return []byte(pcommon.NewValueInt(v).AsString()), nil
+	case float64:
+		return []byte(pcommon.NewValueDouble(v).AsString()), nil
+	case pcommon.Map:
+		m := pcommon.NewValueMap()
+		v.CopyTo(m.Map())
+		return []byte(m.AsString()), nil
+	case pcommon.Slice:
+		s := pcommon.NewValueSlice()
+		v.CopyTo(s.Slice())
+		return []byte(s.AsString()), nil
+	default:
+		return nil, fmt.Errorf("SHA256 does not support targets of type %T", val)
+	}
+}
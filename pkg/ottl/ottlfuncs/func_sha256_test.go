@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_sha256(t *testing.T) {
+	sum := func(s string) string {
+		digest := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(digest[:])
+	}
+
+	attrs := pcommon.NewMap()
+	attrs.PutStr("user", "alice")
+
+	tests := []struct {
+		name     string
+		target   interface{}
+		expected string
+	}{
+		{
+			name:     "string",
+			target:   "user-1234",
+			expected: sum("user-1234"),
+		},
+		{
+			name:     "empty string",
+			target:   "",
+			expected: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name:     "known digest",
+			target:   "abc",
+			expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:     "bytes",
+			target:   []byte("user-1234"),
+			expected: sum("user-1234"),
+		},
+		{
+			name:     "int",
+			target:   int64(1234),
+			expected: sum("1234"),
+		},
+		{
+			name:     "negative int",
+			target:   int64(-42),
+			expected: sum("-42"),
+		},
+		{
+			name:     "double",
+			target:   1.5,
+			expected: sum("1.5"),
+		},
+		{
+			name:     "bool",
+			target:   true,
+			expected: sum("true"),
+		},
+		{
+			name:     "map",
+			target:   attrs,
+			expected: sum(`{"user":"alice"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.target, nil
+				},
+			}
+			exprFunc, err := SHA256[interface{}](target)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_sha256_nil(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}
+	exprFunc, err := SHA256[interface{}](target)
+	require.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.EqualError(t, err, "SHA256 requires a non-nil target")
+	assert.Nil(t, result)
+}
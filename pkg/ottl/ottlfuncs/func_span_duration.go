@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// spanContext is implemented by the contexts giving access to a span, such as the traces context.
+type spanContext interface {
+	GetSpan() ptrace.Span
+}
+
+func SpanDuration[K spanContext]() (ottl.ExprFunc[K], error) {
+	return func(ctx K) (interface{}, error) {
+		span := ctx.GetSpan()
+		if span.EndTimestamp() == 0 {
+			return int64(0), nil
+		}
+		return int64(span.EndTimestamp()) - int64(span.StartTimestamp()), nil
+	}, nil
+}
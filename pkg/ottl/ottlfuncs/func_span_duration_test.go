@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type testSpanContext struct {
+	span ptrace.Span
+}
+
+func (ctx testSpanContext) GetSpan() ptrace.Span {
+	return ctx.span
+}
+
+func Test_spanDuration(t *testing.T) {
+	start := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  int64
+	}{
+		{
+			name:  "ended span",
+			start: start,
+			end:   start.Add(1500 * time.Millisecond),
+			want:  int64(1500 * time.Millisecond),
+		},
+		{
+			name:  "missing end timestamp",
+			start: start,
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := ptrace.NewSpan()
+			span.SetStartTimestamp(pcommon.NewTimestampFromTime(tt.start))
+			if !tt.end.IsZero() {
+				span.SetEndTimestamp(pcommon.NewTimestampFromTime(tt.end))
+			}
+
+			exprFunc, err := SpanDuration[testSpanContext]()
+			assert.NoError(t, err)
+
+			result, err := exprFunc(testSpanContext{span: span})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
@@ -15,8 +15,11 @@
 package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 
 import (
+	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -26,11 +29,20 @@ func Split[K any](target ottl.Getter[K], delimiter string) (ottl.ExprFunc[K], er
 		if err != nil {
 			return nil, err
 		}
-		if val != nil {
-			if valStr, ok := val.(string); ok {
-				return strings.Split(valStr, delimiter), nil
-			}
+		if val == nil {
+			return nil, nil
+		}
+		valStr, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("Split requires a string target, got %T", val)
+		}
+		result := pcommon.NewSlice()
+		if valStr == "" {
+			return result, nil
+		}
+		for _, part := range strings.Split(valStr, delimiter) {
+			result.AppendEmpty().SetStr(part)
 		}
-		return nil, nil
+		return result, nil
 	}, nil
 }
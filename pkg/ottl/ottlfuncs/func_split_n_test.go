@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_splitN(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    ottl.Getter[interface{}]
+		delimiter string
+		n         int64
+		expected  interface{}
+	}{
+		{
+			name: "split string with limit",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "A|B|C|D", nil
+				},
+			},
+			delimiter: "|",
+			n:         2,
+			expected:  []interface{}{"A", "B|C|D"},
+		},
+		{
+			name: "split string with limit larger than parts",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "A|B|C", nil
+				},
+			},
+			delimiter: "|",
+			n:         5,
+			expected:  []interface{}{"A", "B", "C"},
+		},
+		{
+			name: "split string with negative limit",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "A|B|C", nil
+				},
+			},
+			delimiter: "|",
+			n:         -1,
+			expected:  []interface{}{"A", "B", "C"},
+		},
+		{
+			name: "split string with zero limit",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "A|B|C", nil
+				},
+			},
+			delimiter: "|",
+			n:         0,
+			expected:  []interface{}{},
+		},
+		{
+			name: "split non-string",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return 123, nil
+				},
+			},
+			delimiter: "|",
+			n:         2,
+			expected:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := SplitN(tt.target, tt.delimiter, tt.n)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
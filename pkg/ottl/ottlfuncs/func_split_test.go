@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
@@ -27,7 +28,7 @@ func Test_split(t *testing.T) {
 		name      string
 		target    ottl.Getter[interface{}]
 		delimiter string
-		expected  interface{}
+		expected  []interface{}
 	}{
 		{
 			name: "split string",
@@ -37,7 +38,7 @@ func Test_split(t *testing.T) {
 				},
 			},
 			delimiter: "|",
-			expected:  []string{"A", "B", "C"},
+			expected:  []interface{}{"A", "B", "C"},
 		},
 		{
 			name: "split empty string",
@@ -47,7 +48,7 @@ func Test_split(t *testing.T) {
 				},
 			},
 			delimiter: "|",
-			expected:  []string{""},
+			expected:  []interface{}{},
 		},
 		{
 			name: "split empty delimiter",
@@ -57,7 +58,7 @@ func Test_split(t *testing.T) {
 				},
 			},
 			delimiter: "",
-			expected:  []string{"A", "|", "B", "|", "C"},
+			expected:  []interface{}{"A", "|", "B", "|", "C"},
 		},
 		{
 			name: "split empty string and empty delimiter",
@@ -67,17 +68,27 @@ func Test_split(t *testing.T) {
 				},
 			},
 			delimiter: "",
-			expected:  []string{},
+			expected:  []interface{}{},
 		},
 		{
-			name: "split non-string",
+			name: "delimiter not found",
 			target: &ottl.StandardGetSetter[interface{}]{
 				Getter: func(ctx interface{}) (interface{}, error) {
-					return 123, nil
+					return "/api/v1/users", nil
 				},
 			},
 			delimiter: "|",
-			expected:  nil,
+			expected:  []interface{}{"/api/v1/users"},
+		},
+		{
+			name: "split path",
+			target: &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return "/api/v1/users", nil
+				},
+			},
+			delimiter: "/",
+			expected:  []interface{}{"", "api", "v1", "users"},
 		},
 	}
 	for _, tt := range tests {
@@ -86,7 +97,33 @@ func Test_split(t *testing.T) {
 			assert.NoError(t, err)
 			result, err := exprFunc(nil)
 			assert.NoError(t, err)
-			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
 		})
 	}
 }
+
+func Test_split_non_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return 123, nil
+		},
+	}
+	exprFunc, err := Split[interface{}](target, "|")
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.ErrorContains(t, err, "Split requires a string target, got int")
+	assert.Nil(t, result)
+}
+
+func Test_split_nil(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}
+	exprFunc, err := Split[interface{}](target, "|")
+	assert.NoError(t, err)
+	result, err := exprFunc(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_stripANSI(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Value]{
+		Getter: func(ctx pcommon.Value) (interface{}, error) {
+			return ctx.Str(), nil
+		},
+		Setter: func(ctx pcommon.Value, val interface{}) error {
+			ctx.SetStr(val.(string))
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "colored",
+			input:    "\x1b[31mERROR\x1b[0m failed to connect",
+			expected: "ERROR failed to connect",
+		},
+		{
+			name:     "bold and 256 colors",
+			input:    "\x1b[1;38;5;208mWARN\x1b[m disk almost full",
+			expected: "WARN disk almost full",
+		},
+		{
+			name:     "cursor movement",
+			input:    "progress\x1b[2K\x1b[1Gdone",
+			expected: "progressdone",
+		},
+		{
+			name:     "hyperlink",
+			input:    "see \x1b]8;;http://example.com\x1b\\docs\x1b]8;;\x1b\\",
+			expected: "see docs",
+		},
+		{
+			name:     "plain",
+			input:    "INFO [main] started",
+			expected: "INFO [main] started",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioValue := pcommon.NewValueStr(tt.input)
+
+			exprFunc, err := StripANSI[pcommon.Value](target)
+			assert.NoError(t, err)
+
+			result, err := exprFunc(scenarioValue)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, pcommon.NewValueStr(tt.expected), scenarioValue)
+		})
+	}
+}
+
+func Test_stripANSI_bad_input(t *testing.T) {
+	input := pcommon.NewValueInt(1)
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx interface{}, val interface{}) error {
+			t.Errorf("nothing should be set in this scenario")
+			return nil
+		},
+	}
+
+	exprFunc, err := StripANSI[interface{}](target)
+	assert.NoError(t, err)
+
+	result, err := exprFunc(input)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, pcommon.NewValueInt(1), input)
+}
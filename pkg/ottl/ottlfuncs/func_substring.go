@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Substring[K any](target ottl.Getter[K], start int64, length int64) (ottl.ExprFunc[K], error) {
+	if start < 0 {
+		return nil, fmt.Errorf("invalid start for Substring function, %d cannot be negative", start)
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("invalid length for Substring function, %d cannot be negative", length)
+	}
+
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := val.(string)
+		if !ok {
+			return nil, nil
+		}
+		// start and length count characters rather than bytes, and are clamped to the end of the string
+		runes := []rune(str)
+		begin := start
+		if begin > int64(len(runes)) {
+			begin = int64(len(runes))
+		}
+		// compared before adding, as begin + length may overflow
+		end := int64(len(runes))
+		if length < end-begin {
+			end = begin + length
+		}
+		return string(runes[begin:end]), nil
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_substring(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		start    int64
+		length   int64
+		expected interface{}
+	}{
+		{
+			name:     "ASCII prefix",
+			value:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			start:    0,
+			length:   8,
+			expected: "4bf92f35",
+		},
+		{
+			name:     "ASCII middle",
+			value:    "hello world",
+			start:    6,
+			length:   3,
+			expected: "wor",
+		},
+		{
+			name:     "emoji",
+			value:    "\U0001F680 launch \U0001F30D",
+			start:    0,
+			length:   3,
+			expected: "\U0001F680 l",
+		},
+		{
+			name:     "multibyte characters",
+			value:    "café crème",
+			start:    3,
+			length:   4,
+			expected: "é cr",
+		},
+		{
+			name:     "length out of range",
+			value:    "hello",
+			start:    2,
+			length:   100,
+			expected: "llo",
+		},
+		{
+			name:     "max length",
+			value:    "hello",
+			start:    2,
+			length:   math.MaxInt64,
+			expected: "llo",
+		},
+		{
+			name:     "start out of range",
+			value:    "hello",
+			start:    10,
+			length:   2,
+			expected: "",
+		},
+		{
+			name:     "zero length",
+			value:    "hello",
+			start:    1,
+			length:   0,
+			expected: "",
+		},
+		{
+			name:     "non string",
+			value:    int64(12345),
+			start:    0,
+			length:   2,
+			expected: nil,
+		},
+		{
+			name:     "nil",
+			value:    nil,
+			start:    0,
+			length:   2,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return tt.value, nil
+				},
+			}
+			exprFunc, err := Substring[interface{}](target, tt.start, tt.length)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_substring_validation(t *testing.T) {
+	target := &ottl.StandardGetSetter[interface{}]{}
+	_, err := Substring[interface{}](target, -1, 2)
+	assert.ErrorContains(t, err, "invalid start for Substring function, -1 cannot be negative")
+	_, err = Substring[interface{}](target, 0, -2)
+	assert.ErrorContains(t, err, "invalid length for Substring function, -2 cannot be negative")
+}
@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+func ToDuration[K any](target ottl.Getter[K], unit string) (ottl.ExprFunc[K], error) {
+	multiplier, ok := durationUnits[unit]
+	if !ok {
+		return nil, fmt.Errorf("invalid unit for ToDuration function, %q is not one of \"ns\", \"us\", \"ms\", \"s\", \"m\" or \"h\"", unit)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch v := val.(type) {
+		case int64:
+			return v * int64(multiplier), nil
+		case float64:
+			return int64(v * float64(multiplier)), nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("ToDuration requires a numeric value, got %T", val)
+		}
+	}, nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_toDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		unit     string
+		expected interface{}
+	}{
+		{
+			name:     "nanoseconds",
+			value:    int64(250),
+			unit:     "ns",
+			expected: int64(250),
+		},
+		{
+			name:     "microseconds",
+			value:    int64(250),
+			unit:     "us",
+			expected: int64(250_000),
+		},
+		{
+			name:     "milliseconds",
+			value:    int64(1500),
+			unit:     "ms",
+			expected: int64(1_500_000_000),
+		},
+		{
+			name:     "seconds",
+			value:    int64(3),
+			unit:     "s",
+			expected: int64(3_000_000_000),
+		},
+		{
+			name:     "fractional seconds",
+			value:    1.5,
+			unit:     "s",
+			expected: int64(1_500_000_000),
+		},
+		{
+			name:     "minutes",
+			value:    int64(2),
+			unit:     "m",
+			expected: int64(120_000_000_000),
+		},
+		{
+			name:     "hours",
+			value:    0.5,
+			unit:     "h",
+			expected: int64(1_800_000_000_000),
+		},
+		{
+			name:     "negative value",
+			value:    int64(-2),
+			unit:     "ms",
+			expected: int64(-2_000_000),
+		},
+		{
+			name:     "nil value",
+			value:    nil,
+			unit:     "s",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ToDuration[interface{}](literalGetter(tt.value), tt.unit)
+			assert.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_toDuration_validation(t *testing.T) {
+	for _, unit := range []string{"", "d", "sec", "MS"} {
+		_, err := ToDuration[interface{}](literalGetter(int64(1)), unit)
+		assert.Error(t, err)
+	}
+}
+
+func Test_toDuration_bad_input(t *testing.T) {
+	exprFunc, err := ToDuration[interface{}](literalGetter("10"), "s")
+	assert.NoError(t, err)
+	_, err = exprFunc(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_trimSuffix(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		suffix   string
+		expected interface{}
+	}{
+		{
+			name:     "suffix present",
+			value:    "checkout.svc.cluster.local",
+			suffix:   ".svc.cluster.local",
+			expected: "checkout",
+		},
+		{
+			name:     "suffix absent",
+			value:    "checkout.local",
+			suffix:   ".svc.cluster.local",
+			expected: "checkout.local",
+		},
+		{
+			name:     "suffix only removed once",
+			value:    "checkout.svc.cluster.local.svc.cluster.local",
+			suffix:   ".svc.cluster.local",
+			expected: "checkout.svc.cluster.local",
+		},
+		{
+			name:     "empty suffix",
+			value:    "checkout.svc.cluster.local",
+			suffix:   "",
+			expected: "checkout.svc.cluster.local",
+		},
+		{
+			name:     "non string",
+			value:    int64(1),
+			suffix:   "1",
+			expected: int64(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := tt.value
+			target := &ottl.StandardGetSetter[interface{}]{
+				Getter: func(ctx interface{}) (interface{}, error) {
+					return value, nil
+				},
+				Setter: func(ctx interface{}, val interface{}) error {
+					value = val
+					return nil
+				},
+			}
+			exprFunc, err := TrimSuffix[interface{}](target, tt.suffix)
+			require.NoError(t, err)
+			result, err := exprFunc(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Truncate[K any](target ottl.GetSetter[K], limit int64) (ottl.ExprFunc[K], error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid limit for truncate function, %d cannot be negative", limit)
+	}
+	return func(ctx K) (interface{}, error) {
+		val, err := target.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch v := val.(type) {
+		case string:
+			if int64(len(v)) > limit {
+				err = target.Set(ctx, truncateString(v, int(limit)))
+				if err != nil {
+					return nil, err
+				}
+			}
+		case pcommon.Map:
+			if int64(v.Len()) > limit {
+				truncateMap(v, int(limit))
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// truncateString cuts value to at most limit bytes without splitting a multi-byte character.
+func truncateString(value string, limit int) string {
+	end := limit
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return value[:end]
+}
+
+// truncateMap keeps the limit entries of attrs with the lowest keys in lexicographic order,
+// so that the same entries are kept regardless of the order the map was built in.
+func truncateMap(attrs pcommon.Map, limit int) {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(key string, _ pcommon.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+	keep := make(map[string]struct{}, limit)
+	for _, key := range keys[:limit] {
+		keep[key] = struct{}{}
+	}
+	attrs.RemoveIf(func(key string, _ pcommon.Value) bool {
+		_, ok := keep[key]
+		return !ok
+	})
+}
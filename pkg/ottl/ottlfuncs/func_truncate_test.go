@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ottlfuncs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_truncate_string(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Value]{
+		Getter: func(ctx pcommon.Value) (interface{}, error) {
+			return ctx.Str(), nil
+		},
+		Setter: func(ctx pcommon.Value, val interface{}) error {
+			ctx.SetStr(val.(string))
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		limit    int64
+		expected string
+	}{
+		{
+			name:     "ascii",
+			input:    "hello world",
+			limit:    5,
+			expected: "hello",
+		},
+		{
+			name:     "multibyte character cut",
+			input:    "naïve",
+			limit:    3,
+			expected: "na",
+		},
+		{
+			name:     "multibyte character kept",
+			input:    "naïve",
+			limit:    4,
+			expected: "naï",
+		},
+		{
+			name:     "below limit",
+			input:    "hello",
+			limit:    10,
+			expected: "hello",
+		},
+		{
+			name:     "at limit",
+			input:    "hello",
+			limit:    5,
+			expected: "hello",
+		},
+		{
+			name:     "zero",
+			input:    "hello",
+			limit:    0,
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioValue := pcommon.NewValueStr(tt.input)
+
+			exprFunc, err := Truncate[pcommon.Value](target, tt.limit)
+			require.NoError(t, err)
+
+			result, err := exprFunc(scenarioValue)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, pcommon.NewValueStr(tt.expected), scenarioValue)
+		})
+	}
+}
+
+func Test_truncate_map(t *testing.T) {
+	target := &ottl.StandardGetSetter[pcommon.Map]{
+		Getter: func(ctx pcommon.Map) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx pcommon.Map, val interface{}) error {
+			t.Errorf("maps are truncated in place")
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		limit    int64
+		expected map[string]interface{}
+	}{
+		{
+			name:     "lowest keys kept",
+			limit:    2,
+			expected: map[string]interface{}{"a": int64(1), "b": true},
+		},
+		{
+			name:     "below limit",
+			limit:    5,
+			expected: map[string]interface{}{"a": int64(1), "b": true, "c": "hello"},
+		},
+		{
+			name:     "zero",
+			limit:    0,
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// insertion order differs from key order, the kept entries only depend on the keys
+			scenarioMap := pcommon.NewMap()
+			scenarioMap.PutStr("c", "hello")
+			scenarioMap.PutBool("b", true)
+			scenarioMap.PutInt("a", 1)
+
+			exprFunc, err := Truncate[pcommon.Map](target, tt.limit)
+			require.NoError(t, err)
+
+			result, err := exprFunc(scenarioMap)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+
+			assert.Equal(t, tt.expected, scenarioMap.AsRaw())
+		})
+	}
+}
+
+func Test_truncate_validation(t *testing.T) {
+	_, err := Truncate[interface{}](&ottl.StandardGetSetter[interface{}]{}, -1)
+	assert.EqualError(t, err, "invalid limit for truncate function, -1 cannot be negative")
+}
+
+func Test_truncate_bad_input(t *testing.T) {
+	input := pcommon.NewValueInt(1)
+	target := &ottl.StandardGetSetter[interface{}]{
+		Getter: func(ctx interface{}) (interface{}, error) {
+			return ctx, nil
+		},
+		Setter: func(ctx interface{}, val interface{}) error {
+			t.Errorf("nothing should be set in this scenario")
+			return nil
+		},
+	}
+
+	exprFunc, err := Truncate[interface{}](target, 1)
+	require.NoError(t, err)
+
+	result, err := exprFunc(input)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, pcommon.NewValueInt(1), input)
+}
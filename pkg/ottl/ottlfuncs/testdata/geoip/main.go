@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Program geoip generates city-test.mmdb, the MaxMind city database used by the GeoIP tests.
+// It holds a few networks only, with the fields read by GeoIP. Run it from this directory with:
+//
+//	go run . city-test.mmdb
+package main
+
+import (
+	"log"
+	"net"
+	"os"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+)
+
+func main() {
+	w, err := mmdbwriter.New(mmdbwriter.Options{
+		DatabaseType: "GeoIP2-City",
+		RecordSize:   24,
+		Description:  map[string]string{"en": "OTTL GeoIP test database"},
+		// a fixed build time so that the database is regenerated identically
+		BuildEpoch: 1667433600,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	add := func(cidr, country, iso, city string, lat, lon float64) {
+		_, n, _ := net.ParseCIDR(cidr)
+		rec := mmdbtype.Map{
+			"country":  mmdbtype.Map{"iso_code": mmdbtype.String(iso), "names": mmdbtype.Map{"en": mmdbtype.String(country)}},
+			"location": mmdbtype.Map{"latitude": mmdbtype.Float64(lat), "longitude": mmdbtype.Float64(lon)},
+		}
+		if city != "" {
+			rec["city"] = mmdbtype.Map{"names": mmdbtype.Map{"en": mmdbtype.String(city)}}
+		}
+		if err := w.Insert(n, rec); err != nil {
+			log.Fatal(err)
+		}
+	}
+	add("81.2.69.0/24", "United Kingdom", "GB", "London", 51.5142, -0.0931)
+	add("2001:480::/32", "United States", "US", "San Diego", 32.7203, -117.1552)
+	add("89.160.20.112/28", "Sweden", "SE", "", 59.3247, 18.056)
+	f, err := os.Create(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := w.WriteTo(f); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -25,7 +25,38 @@ func Functions[K any]() map[string]interface{} {
 		"IsMatch":              ottlfuncs.IsMatch[K],
 		"Concat":               ottlfuncs.Concat[K],
 		"Split":                ottlfuncs.Split[K],
+		"SplitN":               ottlfuncs.SplitN[K],
 		"Int":                  ottlfuncs.Int[K],
+		"RoundToMultiple":      ottlfuncs.RoundToMultiple[K],
+		"ParseVersion":         ottlfuncs.ParseVersion[K],
+		"CompareVersions":      ottlfuncs.CompareVersions[K],
+		"FilterSlice":          ottlfuncs.FilterSlice[K],
+		"ToDuration":           ottlfuncs.ToDuration[K],
+		"GeoIP":                ottlfuncs.GeoIP[K],
+		"ParseNestedKeyValue":  ottlfuncs.ParseNestedKeyValue[K],
+		"Between":              ottlfuncs.Between[K],
+		"CountMatches":         ottlfuncs.CountMatches[K],
+		"Join":                 ottlfuncs.Join[K],
+		"ParseCEF":             ottlfuncs.ParseCEF[K],
+		"ParseWindowsEvent":    ottlfuncs.ParseWindowsEvent[K],
+		"ParseINI":             ottlfuncs.ParseINI[K],
+		"FlattenSlice":         ottlfuncs.FlattenSlice[K],
+		"MapAnyKeyMatches":     ottlfuncs.MapAnyKeyMatches[K],
+		"MapAnyValueMatches":   ottlfuncs.MapAnyValueMatches[K],
+		"ParseDN":              ottlfuncs.ParseDN[K],
+		"ParseAcceptLanguage":  ottlfuncs.ParseAcceptLanguage[K],
+		"Substring":            ottlfuncs.Substring[K],
+		"Base64Decode":         ottlfuncs.Base64Decode[K],
+		"ParseJSON":            ottlfuncs.ParseJSON[K],
+		"SHA256":               ottlfuncs.SHA256[K],
+		"ParseCookies":         ottlfuncs.ParseCookies[K],
+		"ParseStacktrace":      ottlfuncs.ParseStacktrace[K],
+		"ParseHeaders":         ottlfuncs.ParseHeaders[K],
+		"Entropy":              ottlfuncs.Entropy[K],
+		"ParseRatio":           ottlfuncs.ParseRatio[K],
+		"DeepEqual":            ottlfuncs.DeepEqual[K],
+		"ParseEmail":           ottlfuncs.ParseEmail[K],
+		"ProbabilitySample":    ottlfuncs.ProbabilitySample[K],
 		"keep_keys":            ottlfuncs.KeepKeys[K],
 		"set":                  ottlfuncs.Set[K],
 		"truncate_all":         ottlfuncs.TruncateAll[K],
@@ -36,5 +67,18 @@ func Functions[K any]() map[string]interface{} {
 		"replace_all_patterns": ottlfuncs.ReplaceAllPatterns[K],
 		"delete_key":           ottlfuncs.DeleteKey[K],
 		"delete_matching_keys": ottlfuncs.DeleteMatchingKeys[K],
+		"strip_ansi":           ottlfuncs.StripANSI[K],
+		"normalize_log_level":  ottlfuncs.NormalizeLogLevel[K],
+		"drop_null_values":     ottlfuncs.DropNullValues[K],
+		"scale":                ottlfuncs.Scale[K],
+		"move_json_field":      ottlfuncs.MoveJSONField[K],
+		"redact":               ottlfuncs.Redact[K],
+		"normalize_unicode":    ottlfuncs.NormalizeUnicode[K],
+		"replace_regex":        ottlfuncs.ReplaceRegex[K],
+		"trim_prefix":          ottlfuncs.TrimPrefix[K],
+		"trim_suffix":          ottlfuncs.TrimSuffix[K],
+		"sanitize_metric_name": ottlfuncs.SanitizeMetricName[K],
+		"truncate":             ottlfuncs.Truncate[K],
+		"convert_case":         ottlfuncs.ConvertCase[K],
 	}
 }
@@ -193,6 +193,17 @@ func TestProcess(t *testing.T) {
 			statement: `set(attributes["test"], Split(attributes["not_exist"], "|"))`,
 			want:      func(td plog.Logs) {},
 		},
+		{
+			statement: `set(attributes["test"], SplitN(attributes["flags"], "|", 2))`,
+			want: func(td plog.Logs) {
+				v1 := td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().PutEmptySlice("test")
+				v1.AppendEmpty().SetStr("A")
+				v1.AppendEmpty().SetStr("B|C")
+				v2 := td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).Attributes().PutEmptySlice("test")
+				v2.AppendEmpty().SetStr("C")
+				v2.AppendEmpty().SetStr("D")
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoints"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
@@ -30,6 +31,8 @@ func Test_DefaultFunctions(t *testing.T) {
 	expected["convert_gauge_to_sum"] = convertGaugeToSum
 	expected["convert_summary_sum_val_to_sum"] = convertSummarySumValToSum
 	expected["convert_summary_count_val_to_sum"] = convertSummaryCountValToSum
+	expected["set_metric_description"] = ottlfuncs.SetMetricDescription[ottldatapoints.TransformContext]
+	expected["set_metric_unit"] = ottlfuncs.SetMetricUnit[ottldatapoints.TransformContext]
 
 	actual := Functions()
 
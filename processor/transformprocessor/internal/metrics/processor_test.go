@@ -98,6 +98,13 @@ func TestProcess(t *testing.T) {
 				td.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(3).SetUnit("new unit")
 			},
 		},
+		{
+			statements: []string{`set_metric_description("Sum") where metric.type == METRIC_DATA_TYPE_SUM`, `set_metric_unit("ms") where metric.type == METRIC_DATA_TYPE_SUM`},
+			want: func(td pmetric.Metrics) {
+				td.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).SetDescription("Sum")
+				td.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).SetUnit("ms")
+			},
+		},
 		{
 			statements: []string{`set(metric.description, "Sum") where metric.type == METRIC_DATA_TYPE_SUM`},
 			want: func(td pmetric.Metrics) {
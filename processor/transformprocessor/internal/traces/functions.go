@@ -16,10 +16,22 @@ package traces // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottltraces"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
+// registry is a map of names to functions for traces pipelines
+var registry = map[string]interface{}{
+	"SpanDuration": ottlfuncs.SpanDuration[ottltraces.TransformContext],
+}
+
+func init() {
+	// Init traces registry with default functions common to all signals
+	for k, v := range common.Functions[ottltraces.TransformContext]() {
+		registry[k] = v
+	}
+}
+
 func Functions() map[string]interface{} {
-	// No trace-only functions yet.
-	return common.Functions[ottltraces.TransformContext]()
+	return registry
 }
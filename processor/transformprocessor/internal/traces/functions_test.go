@@ -21,11 +21,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottltraces"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
 func Test_DefaultFunctions(t *testing.T) {
 	expected := common.Functions[ottltraces.TransformContext]()
+	expected["SpanDuration"] = ottlfuncs.SpanDuration[ottltraces.TransformContext]
+
 	actual := Functions()
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
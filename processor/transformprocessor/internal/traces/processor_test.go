@@ -177,6 +177,12 @@ func TestProcess(t *testing.T) {
 				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().PutStr("test", "pass")
 			},
 		},
+		{
+			statement: `set(attributes["duration"], SpanDuration()) where name == "operationA"`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().PutInt("duration", int64(TestSpanEndTime.Sub(TestSpanStartTime)))
+			},
+		},
 		{
 			statement: `set(kind, SPAN_KIND_SERVER) where kind == 1`,
 			want: func(td ptrace.Traces) {
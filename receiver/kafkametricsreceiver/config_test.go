@@ -46,11 +46,13 @@ func TestLoadConfig(t *testing.T) {
 		TopicMatch:                "test_\\w+",
 		GroupMatch:                "test_\\w+",
 		Authentication: kafkaexporter.Authentication{
-			TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{
-					CAFile:   "ca.pem",
-					CertFile: "cert.pem",
-					KeyFile:  "key.pem",
+			TLS: &kafkaexporter.TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{
+						CAFile:   "ca.pem",
+						CertFile: "cert.pem",
+						KeyFile:  "key.pem",
+					},
 				},
 			},
 		},
@@ -56,9 +56,11 @@ func TestNewReceiver_invalid_scraper_error(t *testing.T) {
 func TestNewReceiver_invalid_auth_error(t *testing.T) {
 	c := createDefaultConfig().(*Config)
 	c.Authentication = kafkaexporter.Authentication{
-		TLS: &configtls.TLSClientSetting{
-			TLSSetting: configtls.TLSSetting{
-				CAFile: "/invalid",
+		TLS: &kafkaexporter.TLSConfig{
+			TLSClientSetting: configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile: "/invalid",
+				},
 			},
 		},
 	}
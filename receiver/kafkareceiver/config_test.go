@@ -49,11 +49,13 @@ func TestLoadConfig(t *testing.T) {
 				ClientID:         "otel-collector",
 				GroupID:          "otel-collector",
 				Authentication: kafkaexporter.Authentication{
-					TLS: &configtls.TLSClientSetting{
-						TLSSetting: configtls.TLSSetting{
-							CAFile:   "ca.pem",
-							CertFile: "cert.pem",
-							KeyFile:  "key.pem",
+					TLS: &kafkaexporter.TLSConfig{
+						TLSClientSetting: configtls.TLSClientSetting{
+							TLSSetting: configtls.TLSSetting{
+								CAFile:   "ca.pem",
+								CertFile: "cert.pem",
+								KeyFile:  "key.pem",
+							},
 						},
 					},
 				},
@@ -81,11 +83,13 @@ func TestLoadConfig(t *testing.T) {
 				ClientID:         "otel-collector",
 				GroupID:          "otel-collector",
 				Authentication: kafkaexporter.Authentication{
-					TLS: &configtls.TLSClientSetting{
-						TLSSetting: configtls.TLSSetting{
-							CAFile:   "ca.pem",
-							CertFile: "cert.pem",
-							KeyFile:  "key.pem",
+					TLS: &kafkaexporter.TLSConfig{
+						TLSClientSetting: configtls.TLSClientSetting{
+							TLSSetting: configtls.TLSSetting{
+								CAFile:   "ca.pem",
+								CertFile: "cert.pem",
+								KeyFile:  "key.pem",
+							},
 						},
 					},
 				},
@@ -64,9 +64,11 @@ func TestNewTracesReceiver_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
 		Authentication: kafkaexporter.Authentication{
-			TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{
-					CAFile: "/doesnotexist",
+			TLS: &kafkaexporter.TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{
+						CAFile: "/doesnotexist",
+					},
 				},
 			},
 		},
@@ -253,9 +255,11 @@ func TestNewMetricsExporter_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
 		Authentication: kafkaexporter.Authentication{
-			TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{
-					CAFile: "/doesnotexist",
+			TLS: &kafkaexporter.TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{
+						CAFile: "/doesnotexist",
+					},
 				},
 			},
 		},
@@ -441,9 +445,11 @@ func TestNewLogsExporter_err_auth_type(t *testing.T) {
 	c := Config{
 		ProtocolVersion: "2.0.0",
 		Authentication: kafkaexporter.Authentication{
-			TLS: &configtls.TLSClientSetting{
-				TLSSetting: configtls.TLSSetting{
-					CAFile: "/doesnotexist",
+			TLS: &kafkaexporter.TLSConfig{
+				TLSClientSetting: configtls.TLSClientSetting{
+					TLSSetting: configtls.TLSSetting{
+						CAFile: "/doesnotexist",
+					},
 				},
 			},
 		},
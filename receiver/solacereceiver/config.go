@@ -17,6 +17,7 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtls"
@@ -28,16 +29,24 @@ const (
 )
 
 var (
-	errMissingAuthDetails     = errors.New("authentication details are required, either for plain user name password or XOAUTH2 or client certificate")
-	errMissingQueueName       = errors.New("queue definition is required, queue definition has format queue://<queuename>")
-	errMissingPlainTextParams = errors.New("missing plain text auth params: Username, Password")
-	errMissingXauth2Params    = errors.New("missing xauth2 text auth params: Username, Bearer")
+	errMissingBroker           = errors.New("at least one broker is required")
+	errMissingAuthDetails      = errors.New("authentication details are required, either for plain user name password or XOAUTH2 or client certificate")
+	errMissingQueueName        = errors.New("queue definition is required, queue definition has format queue://<queuename>")
+	errMissingPlainTextParams  = errors.New("missing plain text auth params: Username, Password")
+	errMissingXauth2Params     = errors.New("missing xauth2 text auth params: Username, Bearer")
+	errMissingFilterProperty   = errors.New("message filter rule requires a property")
+	errInvalidIdleTimeout      = errors.New("idle timeout must not be negative")
+	errInvalidConsumerTimeout  = errors.New("consumer timeout must not be negative")
+	errInvalidMaxBatchSpans    = errors.New("max batch spans must not be negative")
+	errInvalidMaxBatchTimeout  = errors.New("max batch timeout must not be negative, and must be set when max batch spans is set")
+	errInvalidSpanNameProperty = errors.New("span name property must not be blank or contain surrounding whitespace")
 )
 
 // Config defines configuration for Solace receiver.
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
-	// The list of solace brokers (default localhost:5671)
+	// The list of solace brokers (default localhost:5671). The receiver connects to the first broker, and fails
+	// over to the next broker in order when a connection attempt fails.
 	Broker []string `mapstructure:"broker"`
 
 	// The name of the solace queue to consume from, it is required parameter
@@ -49,19 +58,73 @@ type Config struct {
 	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 
 	Auth Authentication `mapstructure:"auth"`
+
+	// MessageFilters are used to drop messages prior to unmarshalling. A message is dropped if it matches any rule.
+	MessageFilters []MessageFilterRule `mapstructure:"message_filters"`
+
+	// SpanNameFromProperty is the name of the user property of the traced message used as span name.
+	// If the property is not a string or is absent, the default span name is used.
+	SpanNameFromProperty string `mapstructure:"span_name_from_property"`
+
+	// IdleTimeout is the duration without received messages after which the receiver reports the idle state
+	// while connected. Zero disables the idle state.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+
+	// ConsumerTimeout bounds the time the next consumer may take to process the traces of a message.
+	// Messages that time out are not acknowledged so that they are redelivered. Zero disables the timeout.
+	ConsumerTimeout time.Duration `mapstructure:"consumer_timeout"`
+
+	// MaxBatchSpans is the number of spans from which the traces of received messages are forwarded to the next
+	// consumer in a single call. The messages of a batch are acknowledged once it is forwarded. Zero disables batching.
+	MaxBatchSpans int `mapstructure:"max_batch_spans"`
+
+	// MaxBatchTimeout is the maximum time a batch is held after its first message was received before it is forwarded,
+	// regardless of its number of spans. It must be positive when MaxBatchSpans is set.
+	MaxBatchTimeout time.Duration `mapstructure:"max_batch_timeout"`
 }
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if len(cfg.Broker) == 0 {
+		return errMissingBroker
+	}
 	if cfg.Auth.PlainText == nil && cfg.Auth.External == nil && cfg.Auth.XAuth2 == nil {
 		return errMissingAuthDetails
 	}
 	if len(strings.TrimSpace(cfg.Queue)) == 0 {
 		return errMissingQueueName
 	}
+	for _, rule := range cfg.MessageFilters {
+		if len(strings.TrimSpace(rule.Property)) == 0 {
+			return errMissingFilterProperty
+		}
+	}
+	if cfg.IdleTimeout < 0 {
+		return errInvalidIdleTimeout
+	}
+	if cfg.ConsumerTimeout < 0 {
+		return errInvalidConsumerTimeout
+	}
+	if cfg.MaxBatchSpans < 0 {
+		return errInvalidMaxBatchSpans
+	}
+	if cfg.MaxBatchTimeout < 0 || (cfg.MaxBatchSpans > 0 && cfg.MaxBatchTimeout == 0) {
+		return errInvalidMaxBatchTimeout
+	}
+	if cfg.SpanNameFromProperty != "" && strings.TrimSpace(cfg.SpanNameFromProperty) != cfg.SpanNameFromProperty {
+		return errInvalidSpanNameProperty
+	}
 	return nil
 }
 
+// MessageFilterRule matches messages that have an application property with the given value.
+type MessageFilterRule struct {
+	// The name of the application property to match, it is required parameter
+	Property string `mapstructure:"property"`
+	// The value the application property must have for the message to be dropped
+	Value string `mapstructure:"value"`
+}
+
 // Authentication defines authentication strategies.
 type Authentication struct {
 	PlainText *SaslPlainTextConfig `mapstructure:"sasl_plain"`
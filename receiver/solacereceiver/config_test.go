@@ -17,6 +17,7 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -91,6 +92,15 @@ func TestConfigValidateMissingAuth(t *testing.T) {
 	assert.Equal(t, errMissingAuthDetails, err)
 }
 
+func TestConfigValidateMissingBroker(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+	cfg.Broker = nil
+	err := cfg.Validate()
+	assert.Equal(t, errMissingBroker, err)
+}
+
 func TestConfigValidateMissingQueue(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
@@ -98,6 +108,69 @@ func TestConfigValidateMissingQueue(t *testing.T) {
 	assert.Equal(t, errMissingQueueName, err)
 }
 
+func TestConfigValidateMissingFilterProperty(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+	cfg.MessageFilters = []MessageFilterRule{{Value: "heartbeat"}}
+	err := cfg.Validate()
+	assert.Equal(t, errMissingFilterProperty, err)
+}
+
+func TestConfigValidateInvalidSpanNameProperty(t *testing.T) {
+	for _, property := range []string{" ", " operation", "operation\t"} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Queue = "someQueue"
+		cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+		cfg.SpanNameFromProperty = property
+		err := cfg.Validate()
+		assert.Equal(t, errInvalidSpanNameProperty, err)
+	}
+}
+
+func TestConfigValidateInvalidIdleTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+	cfg.IdleTimeout = -time.Second
+	err := cfg.Validate()
+	assert.Equal(t, errInvalidIdleTimeout, err)
+}
+
+func TestConfigValidateInvalidConsumerTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+	cfg.ConsumerTimeout = -time.Second
+	err := cfg.Validate()
+	assert.Equal(t, errInvalidConsumerTimeout, err)
+}
+
+func TestConfigValidateInvalidMaxBatchSpans(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Queue = "someQueue"
+	cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+	cfg.MaxBatchSpans = -1
+	cfg.MaxBatchTimeout = time.Second
+	err := cfg.Validate()
+	assert.Equal(t, errInvalidMaxBatchSpans, err)
+}
+
+func TestConfigValidateInvalidMaxBatchTimeout(t *testing.T) {
+	for _, batch := range []struct {
+		spans   int
+		timeout time.Duration
+	}{{100, 0}, {100, -time.Second}, {0, -time.Second}} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Queue = "someQueue"
+		cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+		cfg.MaxBatchSpans = batch.spans
+		cfg.MaxBatchTimeout = batch.timeout
+		err := cfg.Validate()
+		assert.Equal(t, errInvalidMaxBatchTimeout, err)
+	}
+}
+
 func TestConfigValidateSuccess(t *testing.T) {
 	successCases := map[string]func(*Config){
 		"With Plaintext Auth": func(c *Config) {
@@ -112,6 +185,31 @@ func TestConfigValidateSuccess(t *testing.T) {
 		"With External Auth": func(c *Config) {
 			c.Auth.External = &SaslExternalConfig{}
 		},
+		"With Message Filters": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.MessageFilters = []MessageFilterRule{{Property: "application-message-type", Value: "heartbeat"}}
+		},
+		"With Multiple Brokers": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.Broker = []string{"primary:5671", "backup:5671"}
+		},
+		"With Span Name Property": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.SpanNameFromProperty = "operation"
+		},
+		"With Idle Timeout": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.IdleTimeout = time.Minute
+		},
+		"With Consumer Timeout": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.ConsumerTimeout = 5 * time.Second
+		},
+		"With Span Batching": func(c *Config) {
+			c.Auth.External = &SaslExternalConfig{}
+			c.MaxBatchSpans = 1000
+			c.MaxBatchTimeout = 200 * time.Millisecond
+		},
 	}
 
 	for caseName, configure := range successCases {
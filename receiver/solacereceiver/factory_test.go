@@ -87,25 +87,26 @@ func TestCreateTracesReceiverBadConfigIncompleteAuth(t *testing.T) {
 }
 
 func TestCreateTracesReceiverBadMetrics(t *testing.T) {
-	// register a metric first with the same name
-	statName := "solacereceiver/primary/failed_reconnections"
+	// register a metric first with the same name, the metrics are recorded with OpenCensus while the
+	// useOtelForInternalMetricsGateID feature gate is disabled
+	statName := "solacereceiver/" + t.Name() + "/failed_reconnections"
 	stat := stats.Int64(statName, "", stats.UnitDimensionless)
-	err := view.Register(&view.View{
+	v := &view.View{
 		Name:        buildReceiverCustomMetricName(statName),
 		Description: "some description",
 		Measure:     stat,
 		Aggregation: view.Sum(),
+	}
+	require.NoError(t, view.Register(v))
+	t.Cleanup(func() {
+		view.Unregister(v)
 	})
-	require.NoError(t, err)
 
-	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
-	require.NoError(t, err)
 	factory := NewFactory()
-	cfg := factory.CreateDefaultConfig()
-
-	sub, err := cm.Sub(config.NewComponentIDWithName(componentType, "primary").String())
-	require.NoError(t, err)
-	require.NoError(t, config.UnmarshalReceiver(sub, cfg))
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetIDName(t.Name())
+	cfg.Queue = "some-queue"
+	cfg.Auth = Authentication{External: &SaslExternalConfig{}}
 
 	receiver, err := factory.CreateTracesReceiver(
 		context.Background(),
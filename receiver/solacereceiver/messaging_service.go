@@ -34,8 +34,8 @@ type messagingService interface {
 	failed(ctx context.Context, msg *inboundMessage) error
 }
 
-// messagingServiceFactory is a factory to create new messagingService instances
-type messagingServiceFactory func() messagingService
+// messagingServiceFactory is a factory to create new messagingService instances connecting to the given broker.
+type messagingServiceFactory func(broker string) messagingService
 
 // connTLSConfig abstracts out amqp.ConnTLSConfig in order for substitution in tests
 var connTLSConfig = amqp.ConnTLSConfig
@@ -56,29 +56,25 @@ func newAMQPMessagingServiceFactory(cfg *Config, logger *zap.Logger) (messagingS
 
 	var tlsConfig amqp.ConnOption
 
-	broker := cfg.Broker[0]
 	// If the TLS config is nil, insecure is true and we should use amqp rather than amqps
 	scheme := "amqp"
 	if loadedTLSConfig != nil {
 		scheme = "amqps"
 		tlsConfig = connTLSConfig(loadedTLSConfig)
 	}
-	amqpHostAddress := fmt.Sprintf("%s://%s", scheme, broker)
-
-	connectConfig := &amqpConnectConfig{
-		addr:       amqpHostAddress,
-		tlsConfig:  tlsConfig,
-		saslConfig: saslConnOption,
-	}
 
 	receiverConfig := &amqpReceiverConfig{
 		queue:      cfg.Queue,
 		maxUnacked: cfg.MaxUnacked,
 	}
 
-	return func() messagingService {
+	return func(broker string) messagingService {
 		return &amqpMessagingService{
-			connectConfig:  connectConfig,
+			connectConfig: &amqpConnectConfig{
+				addr:       fmt.Sprintf("%s://%s", scheme, broker),
+				tlsConfig:  tlsConfig,
+				saslConfig: saslConnOption,
+			},
 			receiverConfig: receiverConfig,
 			logger:         logger,
 		}
@@ -187,7 +187,7 @@ func TestNewAMQPMessagingServiceFactory(t *testing.T) {
 				assert.Nil(t, factory)
 			} else {
 				assert.NoError(t, err)
-				actual := factory().(*amqpMessagingService)
+				actual := factory(broker).(*amqpMessagingService)
 				// assert that want == actual, checking individual fields (due to function pointers can't use deep equal)
 				assert.Equal(t, tt.want.connectConfig.addr, actual.connectConfig.addr)
 				testFunctionEquality(t, tt.want.connectConfig.saslConfig, actual.connectConfig.saslConfig)
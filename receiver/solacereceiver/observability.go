@@ -16,9 +16,24 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.uber.org/atomic"
 )
 
 const (
@@ -27,90 +42,284 @@ const (
 	// metricPrefix used to prefix solace specific metrics
 	metricPrefix = "solacereceiver"
 	nameSep      = "/"
+	// brokerAttribute is the attribute of the active broker metric holding the broker address
+	brokerAttribute = "broker"
+	// instrumentationScope is the name of the meter of the receiver metrics
+	instrumentationScope = "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver"
+	// useOtelForInternalMetricsGateID is the feature gate enabling the meter provider of the collector,
+	// the metrics are recorded with OpenCensus while it is disabled
+	useOtelForInternalMetricsGateID = "telemetry.useOtelForInternalMetrics"
 )
 
+// brokerTagKey is the tag of the active broker metric holding the broker address when recording with OpenCensus
+var brokerTagKey = tag.MustNewKey(brokerAttribute)
+
+// reportedSpanLatencyBounds are the bucket bounds of the reported span latency histogram in milliseconds,
+// the default bounds of the OpenTelemetry histograms
+var reportedSpanLatencyBounds = []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+
+// errInvalidInstanceName is returned for receiver instance names that would produce malformed metric names
+var errInvalidInstanceName = errors.New("receiver instance name must not contain the metric name separator or whitespace")
+
 type receiverState uint8
 
 const (
 	receiverStateStarting receiverState = iota
 	receiverStateConnecting
 	receiverStateConnected
-	receiverStateIdle
+	receiverStateIdle // disabled, the receiver does not connect anymore
 	receiverStateTerminating
 	receiverStateTerminated
+	receiverStateConnectedIdle // connected, but no message was received within the idle timeout
 )
 
-type opencensusMetrics struct {
+type receiverMetrics struct {
+	// useOtelForMetrics records the metrics with the OpenTelemetry instruments rather than the OpenCensus measures
+	useOtelForMetrics bool
+
+	counters struct {
+		failedReconnections            syncint64.Counter
+		recoverableUnmarshallingErrors syncint64.Counter
+		fatalUnmarshallingErrors       syncint64.Counter
+		droppedSpanMessages            syncint64.Counter
+		droppedSpans                   syncint64.Counter
+		receivedSpanMessages           syncint64.Counter
+		reportedSpans                  syncint64.Counter
+		filteredMessages               syncint64.Counter
+		settlementErrors               syncint64.Counter
+		consumerTimeouts               syncint64.Counter
+		downstreamExportErrors         syncint64.Counter
+	}
+	histograms struct {
+		reportedSpanLatency syncfloat64.Histogram
+	}
+	gauges struct {
+		receiverStatus asyncint64.Gauge
+		needUpgrade    asyncint64.Gauge
+		activeBroker   asyncint64.Gauge
+	}
+	// the last values recorded for the gauges, observed when the metrics are collected
+	values struct {
+		receiverStatus lastValue
+		needUpgrade    lastValue
+		activeBroker   brokerValues
+	}
+
 	stats struct {
 		failedReconnections            *stats.Int64Measure
 		recoverableUnmarshallingErrors *stats.Int64Measure
 		fatalUnmarshallingErrors       *stats.Int64Measure
 		droppedSpanMessages            *stats.Int64Measure
+		droppedSpans                   *stats.Int64Measure
 		receivedSpanMessages           *stats.Int64Measure
 		reportedSpans                  *stats.Int64Measure
+		reportedSpanLatency            *stats.Float64Measure
 		receiverStatus                 *stats.Int64Measure
 		needUpgrade                    *stats.Int64Measure
+		filteredMessages               *stats.Int64Measure
+		settlementErrors               *stats.Int64Measure
+		consumerTimeouts               *stats.Int64Measure
+		downstreamExportErrors         *stats.Int64Measure
+		activeBroker                   *stats.Int64Measure
 	}
-	views struct {
-		failedReconnections            *view.View
-		recoverableUnmarshallingErrors *view.View
-		fatalUnmarshallingErrors       *view.View
-		droppedSpanMessages            *view.View
-		receivedSpanMessages           *view.View
-		reportedSpans                  *view.View
-		receiverStatus                 *view.View
-		needUpgrade                    *view.View
+	views []*view.View
+}
+
+// lastValue holds the last value recorded for a gauge
+type lastValue struct {
+	recorded atomic.Bool
+	value    atomic.Int64
+}
+
+func (v *lastValue) record(value int64) {
+	v.value.Store(value)
+	v.recorded.Store(true)
+}
+
+// observe observes the last value with gauge, if any value was recorded
+func (v *lastValue) observe(ctx context.Context, gauge asyncint64.Gauge) {
+	if v.recorded.Load() {
+		gauge.Observe(ctx, v.value.Load())
 	}
 }
 
-// receiver will register internal telemetry views
-func newOpenCensusMetrics(instanceName string) (*opencensusMetrics, error) {
-	m := &opencensusMetrics{}
+// brokerValues holds the value of the active broker gauge for each broker the receiver connected to,
+// 1 for the broker currently connected to and 0 for the others
+type brokerValues struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func (v *brokerValues) record(active string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.values == nil {
+		v.values = map[string]int64{}
+	}
+	for broker := range v.values {
+		v.values[broker] = 0
+	}
+	v.values[active] = 1
+}
+
+// each calls fn with the value of each broker
+func (v *brokerValues) each(fn func(broker string, value int64)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for broker, value := range v.values {
+		fn(broker, value)
+	}
+}
+
+// observe observes the value of each broker with gauge, with the broker as attribute
+func (v *brokerValues) observe(ctx context.Context, gauge asyncint64.Gauge) {
+	v.each(func(broker string, value int64) {
+		gauge.Observe(ctx, value, attribute.String(brokerAttribute, broker))
+	})
+}
+
+// newReceiverMetrics creates the receiver metrics. They are recorded with the instruments of the meter provider of the
+// collector when useOtelForMetrics is set, which the collector only reports with the useOtelForInternalMetricsGateID
+// feature gate enabled, and with OpenCensus views otherwise. The metric names are prefixed with the instance name,
+// if any, trimmed of surrounding whitespace.
+func newReceiverMetrics(instanceName string, meterProvider metric.MeterProvider, useOtelForMetrics bool) (*receiverMetrics, error) {
+	instanceName = strings.TrimSpace(instanceName)
+	if strings.Contains(instanceName, nameSep) || strings.IndexFunc(instanceName, unicode.IsSpace) >= 0 {
+		return nil, fmt.Errorf("%w: %q", errInvalidInstanceName, instanceName)
+	}
+	m := &receiverMetrics{useOtelForMetrics: useOtelForMetrics}
 	prefix := metricPrefix + nameSep
 	if instanceName != "" {
 		prefix += instanceName + nameSep
 	}
+	var err error
+	if useOtelForMetrics {
+		err = m.createOtelMetrics(meterProvider.Meter(instrumentationScope), prefix)
+	} else {
+		err = m.createOpenCensusMetrics(prefix)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// createOtelMetrics creates the instruments of the metrics with meter
+func (m *receiverMetrics) createOtelMetrics(meter metric.Meter, prefix string) error {
+	counter := func(name, description string) (syncint64.Counter, error) {
+		return meter.SyncInt64().Counter(buildReceiverCustomMetricName(prefix+name),
+			instrument.WithDescription(description), instrument.WithUnit(unit.Dimensionless))
+	}
+	gauge := func(name, description string) (asyncint64.Gauge, error) {
+		return meter.AsyncInt64().Gauge(buildReceiverCustomMetricName(prefix+name),
+			instrument.WithDescription(description), instrument.WithUnit(unit.Dimensionless))
+	}
 
+	var err error
+	if m.counters.failedReconnections, err = counter("failed_reconnections", "Number of failed broker reconnections"); err != nil {
+		return err
+	}
+	if m.counters.recoverableUnmarshallingErrors, err = counter("recoverable_unmarshalling_errors", "Number of recoverable message unmarshalling errors"); err != nil {
+		return err
+	}
+	if m.counters.fatalUnmarshallingErrors, err = counter("fatal_unmarshalling_errors", "Number of fatal message unmarshalling errors"); err != nil {
+		return err
+	}
+	if m.counters.droppedSpanMessages, err = counter("dropped_span_messages", "Number of dropped span messages"); err != nil {
+		return err
+	}
+	if m.counters.droppedSpans, err = counter("dropped_spans", "Number of dropped spans"); err != nil {
+		return err
+	}
+	if m.counters.receivedSpanMessages, err = counter("received_span_messages", "Number of received span messages"); err != nil {
+		return err
+	}
+	if m.counters.reportedSpans, err = counter("reported_spans", "Number of reported spans"); err != nil {
+		return err
+	}
+	if m.counters.filteredMessages, err = counter("filtered_messages", "Number of messages dropped by the configured message filters"); err != nil {
+		return err
+	}
+	if m.counters.settlementErrors, err = counter("settlement_errors", "Number of messages that could not be settled (acknowledged or rejected) with the broker"); err != nil {
+		return err
+	}
+	if m.counters.consumerTimeouts, err = counter("consumer_timeouts", "Number of messages not acknowledged because the next consumer did not process them within the consumer timeout"); err != nil {
+		return err
+	}
+	if m.counters.downstreamExportErrors, err = counter("downstream_export_errors", "Number of failed attempts to forward traces to the next consumer"); err != nil {
+		return err
+	}
+	if m.histograms.reportedSpanLatency, err = meter.SyncFloat64().Histogram(buildReceiverCustomMetricName(prefix+"reported_span_latency"),
+		instrument.WithDescription("Latency in milliseconds from receiving a span message from the broker until its spans are forwarded to the next consumer"),
+		instrument.WithUnit(unit.Milliseconds)); err != nil {
+		return err
+	}
+	if m.gauges.receiverStatus, err = gauge("receiver_status", "Indicates the status of the receiver as an enum. 0 = starting, 1 = connecting, 2 = connected, 3 = disabled (often paired with needs_upgrade), 4 = terminating, 5 = terminated, 6 = connected but idle"); err != nil {
+		return err
+	}
+	if m.gauges.needUpgrade, err = gauge("need_upgrade", "Indicates with value 1 that receiver requires an upgrade and is not compatible with messages received from a broker"); err != nil {
+		return err
+	}
+
+	if m.gauges.activeBroker, err = gauge("active_broker", "Indicates with value 1 the broker the receiver is connected to, brokers connected to before have value 0"); err != nil {
+		return err
+	}
+
+	return meter.RegisterCallback(
+		[]instrument.Asynchronous{m.gauges.receiverStatus, m.gauges.needUpgrade, m.gauges.activeBroker},
+		func(ctx context.Context) {
+			m.values.receiverStatus.observe(ctx, m.gauges.receiverStatus)
+			m.values.needUpgrade.observe(ctx, m.gauges.needUpgrade)
+			m.values.activeBroker.observe(ctx, m.gauges.activeBroker)
+		},
+	)
+}
+
+// createOpenCensusMetrics creates and registers the views of the metrics
+func (m *receiverMetrics) createOpenCensusMetrics(prefix string) error {
 	m.stats.failedReconnections = stats.Int64(prefix+"failed_reconnections", "Number of failed broker reconnections", stats.UnitDimensionless)
 	m.stats.recoverableUnmarshallingErrors = stats.Int64(prefix+"recoverable_unmarshalling_errors", "Number of recoverable message unmarshalling errors", stats.UnitDimensionless)
 	m.stats.fatalUnmarshallingErrors = stats.Int64(prefix+"fatal_unmarshalling_errors", "Number of fatal message unmarshalling errors", stats.UnitDimensionless)
 	m.stats.droppedSpanMessages = stats.Int64(prefix+"dropped_span_messages", "Number of dropped span messages", stats.UnitDimensionless)
+	m.stats.droppedSpans = stats.Int64(prefix+"dropped_spans", "Number of dropped spans", stats.UnitDimensionless)
 	m.stats.receivedSpanMessages = stats.Int64(prefix+"received_span_messages", "Number of received span messages", stats.UnitDimensionless)
 	m.stats.reportedSpans = stats.Int64(prefix+"reported_spans", "Number of reported spans", stats.UnitDimensionless)
-	m.stats.receiverStatus = stats.Int64(prefix+"receiver_status", "Indicates the status of the receiver as an enum. 0 = starting, 1 = connecting, 2 = connected, 3 = disabled (often paired with needs_upgrade), 4 = terminating, 5 = terminated", stats.UnitDimensionless)
+	m.stats.reportedSpanLatency = stats.Float64(prefix+"reported_span_latency", "Latency in milliseconds from receiving a span message from the broker until its spans are forwarded to the next consumer", stats.UnitMilliseconds)
+	m.stats.receiverStatus = stats.Int64(prefix+"receiver_status", "Indicates the status of the receiver as an enum. 0 = starting, 1 = connecting, 2 = connected, 3 = disabled (often paired with needs_upgrade), 4 = terminating, 5 = terminated, 6 = connected but idle", stats.UnitDimensionless)
 	m.stats.needUpgrade = stats.Int64(prefix+"need_upgrade", "Indicates with value 1 that receiver requires an upgrade and is not compatible with messages received from a broker", stats.UnitDimensionless)
+	m.stats.filteredMessages = stats.Int64(prefix+"filtered_messages", "Number of messages dropped by the configured message filters", stats.UnitDimensionless)
+	m.stats.settlementErrors = stats.Int64(prefix+"settlement_errors", "Number of messages that could not be settled (acknowledged or rejected) with the broker", stats.UnitDimensionless)
+	m.stats.consumerTimeouts = stats.Int64(prefix+"consumer_timeouts", "Number of messages not acknowledged because the next consumer did not process them within the consumer timeout", stats.UnitDimensionless)
+	m.stats.downstreamExportErrors = stats.Int64(prefix+"downstream_export_errors", "Number of failed attempts to forward traces to the next consumer", stats.UnitDimensionless)
+	m.stats.activeBroker = stats.Int64(prefix+"active_broker", "Indicates with value 1 the broker the receiver is connected to, brokers connected to before have value 0", stats.UnitDimensionless)
 
-	m.views.failedReconnections = fromMeasure(m.stats.failedReconnections, view.Count())
-	m.views.recoverableUnmarshallingErrors = fromMeasure(m.stats.recoverableUnmarshallingErrors, view.Count())
-	m.views.fatalUnmarshallingErrors = fromMeasure(m.stats.fatalUnmarshallingErrors, view.Count())
-	m.views.droppedSpanMessages = fromMeasure(m.stats.droppedSpanMessages, view.Count())
-	m.views.receivedSpanMessages = fromMeasure(m.stats.receivedSpanMessages, view.Count())
-	m.views.reportedSpans = fromMeasure(m.stats.reportedSpans, view.Sum())
-	m.views.receiverStatus = fromMeasure(m.stats.receiverStatus, view.LastValue())
-	m.views.needUpgrade = fromMeasure(m.stats.needUpgrade, view.LastValue())
-
-	err := view.Register(
-		m.views.failedReconnections,
-		m.views.recoverableUnmarshallingErrors,
-		m.views.fatalUnmarshallingErrors,
-		m.views.droppedSpanMessages,
-		m.views.receivedSpanMessages,
-		m.views.reportedSpans,
-		m.views.receiverStatus,
-		m.views.needUpgrade,
-	)
-	if err != nil {
-		return nil, err
+	m.views = []*view.View{
+		fromMeasure(m.stats.failedReconnections, view.Count()),
+		fromMeasure(m.stats.recoverableUnmarshallingErrors, view.Count()),
+		fromMeasure(m.stats.fatalUnmarshallingErrors, view.Count()),
+		fromMeasure(m.stats.droppedSpanMessages, view.Count()),
+		fromMeasure(m.stats.droppedSpans, view.Sum()),
+		fromMeasure(m.stats.receivedSpanMessages, view.Count()),
+		fromMeasure(m.stats.reportedSpans, view.Sum()),
+		fromMeasure(m.stats.reportedSpanLatency, view.Distribution(reportedSpanLatencyBounds...)),
+		fromMeasure(m.stats.receiverStatus, view.LastValue()),
+		fromMeasure(m.stats.needUpgrade, view.LastValue()),
+		fromMeasure(m.stats.filteredMessages, view.Count()),
+		fromMeasure(m.stats.settlementErrors, view.Count()),
+		fromMeasure(m.stats.consumerTimeouts, view.Count()),
+		fromMeasure(m.stats.downstreamExportErrors, view.Count()),
+		fromMeasure(m.stats.activeBroker, view.LastValue(), brokerTagKey),
 	}
-	return m, nil
+	return view.Register(m.views...)
 }
 
-func fromMeasure(measure stats.Measure, agg *view.Aggregation) *view.View {
+func fromMeasure(measure stats.Measure, agg *view.Aggregation, tagKeys ...tag.Key) *view.View {
 	return &view.View{
 		Name:        buildReceiverCustomMetricName(measure.Name()),
 		Description: measure.Description(),
 		Measure:     measure,
 		Aggregation: agg,
+		TagKeys:     tagKeys,
 	}
 }
 
@@ -119,41 +328,140 @@ func buildReceiverCustomMetricName(metric string) string {
 }
 
 // recordFailedReconnection increments the metric that records failed reconnection event.
-func (m *opencensusMetrics) recordFailedReconnection() {
+func (m *receiverMetrics) recordFailedReconnection() {
+	if m.useOtelForMetrics {
+		m.counters.failedReconnections.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.failedReconnections.M(1))
 }
 
 // recordRecoverableUnmarshallingError increments the metric that records a recoverable error by trace message unmarshalling.
-func (m *opencensusMetrics) recordRecoverableUnmarshallingError() {
+func (m *receiverMetrics) recordRecoverableUnmarshallingError() {
+	if m.useOtelForMetrics {
+		m.counters.recoverableUnmarshallingErrors.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.recoverableUnmarshallingErrors.M(1))
 }
 
 // recordFatalUnmarshallingError increments the metric that records a fatal arrow by trace message unmarshalling.
-func (m *opencensusMetrics) recordFatalUnmarshallingError() {
+func (m *receiverMetrics) recordFatalUnmarshallingError() {
+	if m.useOtelForMetrics {
+		m.counters.fatalUnmarshallingErrors.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.fatalUnmarshallingErrors.M(1))
 }
 
 // recordDroppedSpanMessages increments the metric that records a dropped span message
-func (m *opencensusMetrics) recordDroppedSpanMessages() {
+func (m *receiverMetrics) recordDroppedSpanMessages() {
+	if m.useOtelForMetrics {
+		m.counters.droppedSpanMessages.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.droppedSpanMessages.M(1))
 }
 
+// recordDroppedSpans increments the metric that records the number of spans of dropped span messages by count
+func (m *receiverMetrics) recordDroppedSpans(count int64) {
+	if m.useOtelForMetrics {
+		m.counters.droppedSpans.Add(context.Background(), count)
+		return
+	}
+	stats.Record(context.Background(), m.stats.droppedSpans.M(count))
+}
+
 // recordReceivedSpanMessages increments the metric that records a received span message
-func (m *opencensusMetrics) recordReceivedSpanMessages() {
+func (m *receiverMetrics) recordReceivedSpanMessages() {
+	if m.useOtelForMetrics {
+		m.counters.receivedSpanMessages.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.receivedSpanMessages.M(1))
 }
 
 // recordReportedSpans increments the metric that records the number of spans reported to the next consumer
-func (m *opencensusMetrics) recordReportedSpans() {
+func (m *receiverMetrics) recordReportedSpans() {
+	if m.useOtelForMetrics {
+		m.counters.reportedSpans.Add(context.Background(), 1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.reportedSpans.M(1))
 }
 
+// recordSpanLatency records the time elapsed between receiving a span message and forwarding its spans to the next consumer
+func (m *receiverMetrics) recordSpanLatency(d time.Duration) {
+	latency := float64(d) / float64(time.Millisecond)
+	if m.useOtelForMetrics {
+		m.histograms.reportedSpanLatency.Record(context.Background(), latency)
+		return
+	}
+	stats.Record(context.Background(), m.stats.reportedSpanLatency.M(latency))
+}
+
 // recordReceiverStatus sets the metric that records the current state of the receiver to the given state
-func (m *opencensusMetrics) recordReceiverStatus(status receiverState) {
+func (m *receiverMetrics) recordReceiverStatus(status receiverState) {
+	if m.useOtelForMetrics {
+		m.values.receiverStatus.record(int64(status))
+		return
+	}
 	stats.Record(context.Background(), m.stats.receiverStatus.M(int64(status)))
 }
 
 // RecordNeedRestart turns a need restart flag on
-func (m *opencensusMetrics) recordNeedUpgrade() {
+func (m *receiverMetrics) recordNeedUpgrade() {
+	if m.useOtelForMetrics {
+		m.values.needUpgrade.record(1)
+		return
+	}
 	stats.Record(context.Background(), m.stats.needUpgrade.M(1))
 }
+
+// recordFilteredMessages increments the metric that records a message dropped by the message filters
+func (m *receiverMetrics) recordFilteredMessages() {
+	if m.useOtelForMetrics {
+		m.counters.filteredMessages.Add(context.Background(), 1)
+		return
+	}
+	stats.Record(context.Background(), m.stats.filteredMessages.M(1))
+}
+
+// recordSettlementError increments the metric that records a failure to settle a message with the broker
+func (m *receiverMetrics) recordSettlementError() {
+	if m.useOtelForMetrics {
+		m.counters.settlementErrors.Add(context.Background(), 1)
+		return
+	}
+	stats.Record(context.Background(), m.stats.settlementErrors.M(1))
+}
+
+// recordConsumerTimeout increments the metric that records a message for which the next consumer timed out
+func (m *receiverMetrics) recordConsumerTimeout() {
+	if m.useOtelForMetrics {
+		m.counters.consumerTimeouts.Add(context.Background(), 1)
+		return
+	}
+	stats.Record(context.Background(), m.stats.consumerTimeouts.M(1))
+}
+
+// recordDownstreamExportError increments the metric that records a failed attempt to forward traces to the next consumer
+func (m *receiverMetrics) recordDownstreamExportError() {
+	if m.useOtelForMetrics {
+		m.counters.downstreamExportErrors.Add(context.Background(), 1)
+		return
+	}
+	stats.Record(context.Background(), m.stats.downstreamExportErrors.M(1))
+}
+
+// recordActiveBroker sets the metric that records the broker the receiver is connected to
+func (m *receiverMetrics) recordActiveBroker(broker string) {
+	m.values.activeBroker.record(broker)
+	if m.useOtelForMetrics {
+		return
+	}
+	// the value of every broker is recorded again, as the broker connected to before is set to 0
+	m.values.activeBroker.each(func(broker string, value int64) {
+		_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(brokerTagKey, broker)}, m.stats.activeBroker.M(value))
+	})
+}
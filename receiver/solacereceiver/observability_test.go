@@ -15,101 +15,404 @@
 package solacereceiver
 
 import (
+	"context"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 type metricsTestCase struct {
-	fn       func()        // function to test updating metrics
-	v        *view.View    // view to reference
-	m        stats.Measure // expected measure of the view
-	calls    int           // number of times to call fn
-	expected int           // expected value of reported metric at end of calls
+	fn       func() // function to test updating metrics
+	name     string // name of the metric to reference
+	calls    int    // number of times to call fn
+	expected int    // expected value of reported metric at end of calls
 }
 
 func TestRecordMetrics(t *testing.T) {
 	metrics := newTestMetrics(t)
 	testCases := []metricsTestCase{
-		{metrics.recordFailedReconnection, metrics.views.failedReconnections, metrics.stats.failedReconnections, 3, 3},
-		{metrics.recordRecoverableUnmarshallingError, metrics.views.recoverableUnmarshallingErrors, metrics.stats.recoverableUnmarshallingErrors, 3, 3},
-		{metrics.recordFatalUnmarshallingError, metrics.views.fatalUnmarshallingErrors, metrics.stats.fatalUnmarshallingErrors, 3, 3},
-		{metrics.recordDroppedSpanMessages, metrics.views.droppedSpanMessages, metrics.stats.droppedSpanMessages, 3, 3},
-		{metrics.recordReceivedSpanMessages, metrics.views.receivedSpanMessages, metrics.stats.receivedSpanMessages, 3, 3},
-		{metrics.recordReportedSpans, metrics.views.reportedSpans, metrics.stats.reportedSpans, 3, 3},
+		{metrics.recordFailedReconnection, "failed_reconnections", 3, 3},
+		{metrics.recordRecoverableUnmarshallingError, "recoverable_unmarshalling_errors", 3, 3},
+		{metrics.recordFatalUnmarshallingError, "fatal_unmarshalling_errors", 3, 3},
+		{metrics.recordDroppedSpanMessages, "dropped_span_messages", 3, 3},
+		{func() {
+			metrics.recordDroppedSpans(4)
+		}, "dropped_spans", 3, 12},
+		{metrics.recordReceivedSpanMessages, "received_span_messages", 3, 3},
+		{metrics.recordReportedSpans, "reported_spans", 3, 3},
 		{func() {
 			metrics.recordReceiverStatus(receiverStateTerminated)
-		}, metrics.views.receiverStatus, metrics.stats.receiverStatus, 3, int(receiverStateTerminated)},
-		{metrics.recordNeedUpgrade, metrics.views.needUpgrade, metrics.stats.needUpgrade, 3, 1},
+		}, "receiver_status", 3, int(receiverStateTerminated)},
+		{metrics.recordNeedUpgrade, "need_upgrade", 3, 1},
+		{metrics.recordFilteredMessages, "filtered_messages", 3, 3},
+		{metrics.recordSettlementError, "settlement_errors", 3, 3},
+		{metrics.recordConsumerTimeout, "consumer_timeouts", 3, 3},
+		{metrics.recordDownstreamExportError, "downstream_export_errors", 3, 3},
 	}
 	for _, tc := range testCases {
-		t.Run(tc.m.Name(), func(t *testing.T) {
+		t.Run(tc.name, func(t *testing.T) {
 			for i := 0; i < tc.calls; i++ {
 				tc.fn()
 			}
-			validateMetric(t, tc.v, tc.expected)
+			validateMetric(t, metrics, tc.name, tc.expected)
 		})
 	}
 }
 
-func validateMetric(t *testing.T, v *view.View, expected interface{}) {
-	// hack to reset stats to 0
-	defer func() {
-		view.Unregister(v)
-		err := view.Register(v)
-		assert.NoError(t, err)
-	}()
-	rows, err := view.RetrieveData(v.Name)
-	assert.NoError(t, err)
-	if expected != nil {
-		require.Len(t, rows, 1)
-		value := reflect.Indirect(reflect.ValueOf(rows[0].Data)).FieldByName("Value").Interface()
-		assert.EqualValues(t, expected, value)
-	} else {
-		assert.Len(t, rows, 0)
+func TestRecordMetricsName(t *testing.T) {
+	metrics := newTestMetrics(t)
+	metrics.recordFailedReconnection()
+	rm, err := testReaders.get(metrics).reader.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, instrumentationScope, rm.ScopeMetrics[0].Scope.Name)
+	require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+	assert.Equal(t, "receiver/solace/solacereceiver/"+t.Name()+"/failed_reconnections", rm.ScopeMetrics[0].Metrics[0].Name)
+	assert.Equal(t, "Number of failed broker reconnections", rm.ScopeMetrics[0].Metrics[0].Description)
+}
+
+func TestNewReceiverMetricsInstanceName(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceName string
+		expectedName string
+		expectedErr  bool
+	}{
+		{
+			name:         "empty",
+			instanceName: "",
+			expectedName: "receiver/solace/solacereceiver/failed_reconnections",
+		},
+		{
+			name:         "valid",
+			instanceName: "primary",
+			expectedName: "receiver/solace/solacereceiver/primary/failed_reconnections",
+		},
+		{
+			name:         "surrounding whitespace",
+			instanceName: " primary\t",
+			expectedName: "receiver/solace/solacereceiver/primary/failed_reconnections",
+		},
+		{
+			name:         "whitespace only",
+			instanceName: "  ",
+			expectedName: "receiver/solace/solacereceiver/failed_reconnections",
+		},
+		{
+			name:         "embedded separator",
+			instanceName: "primary/secondary",
+			expectedErr:  true,
+		},
+		{
+			name:         "embedded whitespace",
+			instanceName: "primary secondary",
+			expectedErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			metrics, err := newReceiverMetrics(tt.instanceName, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), true)
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, errInvalidInstanceName)
+				assert.Nil(t, metrics)
+				return
+			}
+			require.NoError(t, err)
+			metrics.recordFailedReconnection()
+			rm, err := reader.Collect(context.Background())
+			require.NoError(t, err)
+			require.Len(t, rm.ScopeMetrics, 1)
+			require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+			assert.Equal(t, tt.expectedName, rm.ScopeMetrics[0].Metrics[0].Name)
+		})
+	}
+}
+
+func TestRecordSpanLatency(t *testing.T) {
+	metrics := newTestMetrics(t)
+	metrics.recordSpanLatency(2 * time.Millisecond)
+	metrics.recordSpanLatency(20 * time.Millisecond)
+	metrics.recordSpanLatency(1500 * time.Microsecond)
+	rm, err := testReaders.get(metrics).reader.Collect(context.Background())
+	require.NoError(t, err)
+	var histogram *metricdata.Histogram
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if strings.HasSuffix(m.Name, nameSep+"reported_span_latency") {
+				data, ok := m.Data.(metricdata.Histogram)
+				require.True(t, ok, "unexpected data type %T", m.Data)
+				histogram = &data
+			}
+		}
+	}
+	require.NotNil(t, histogram)
+	require.Len(t, histogram.DataPoints, 1)
+	dp := histogram.DataPoints[0]
+	assert.EqualValues(t, 3, dp.Count)
+	assert.InDelta(t, 23.5, dp.Sum, 0.001)
+	require.Len(t, dp.BucketCounts, len(dp.Bounds)+1)
+	// the default bounds start with 0, 5, 10, 25
+	assert.Equal(t, []float64{0, 5, 10, 25}, dp.Bounds[:4])
+	assert.Equal(t, []uint64{0, 2, 0, 1}, dp.BucketCounts[:4])
+	require.NotNil(t, dp.Min)
+	assert.InDelta(t, 1.5, *dp.Min, 0.001)
+	require.NotNil(t, dp.Max)
+	assert.InDelta(t, 20, *dp.Max, 0.001)
+}
+
+func TestRecordActiveBroker(t *testing.T) {
+	metrics := newTestMetrics(t)
+	assert.Empty(t, activeBrokers(t, metrics))
+	metrics.recordActiveBroker("primary:5671")
+	assert.Equal(t, map[string]int64{"primary:5671": 1}, activeBrokers(t, metrics))
+	metrics.recordActiveBroker("backup:5671")
+	assert.Equal(t, map[string]int64{"primary:5671": 0, "backup:5671": 1}, activeBrokers(t, metrics))
+	metrics.recordActiveBroker("primary:5671")
+	assert.Equal(t, map[string]int64{"primary:5671": 1, "backup:5671": 0}, activeBrokers(t, metrics))
+}
+
+func TestRecordMetricsOpenCensus(t *testing.T) {
+	metrics := newTestOpenCensusMetrics(t)
+	testCases := []metricsTestCase{
+		{metrics.recordFailedReconnection, "failed_reconnections", 3, 3},
+		{metrics.recordRecoverableUnmarshallingError, "recoverable_unmarshalling_errors", 3, 3},
+		{metrics.recordFatalUnmarshallingError, "fatal_unmarshalling_errors", 3, 3},
+		{metrics.recordDroppedSpanMessages, "dropped_span_messages", 3, 3},
+		{func() {
+			metrics.recordDroppedSpans(4)
+		}, "dropped_spans", 3, 12},
+		{metrics.recordReceivedSpanMessages, "received_span_messages", 3, 3},
+		{metrics.recordReportedSpans, "reported_spans", 3, 3},
+		{func() {
+			metrics.recordReceiverStatus(receiverStateTerminated)
+		}, "receiver_status", 3, int(receiverStateTerminated)},
+		{metrics.recordNeedUpgrade, "need_upgrade", 3, 1},
+		{metrics.recordFilteredMessages, "filtered_messages", 3, 3},
+		{metrics.recordSettlementError, "settlement_errors", 3, 3},
+		{metrics.recordConsumerTimeout, "consumer_timeouts", 3, 3},
+		{metrics.recordDownstreamExportError, "downstream_export_errors", 3, 3},
 	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < tc.calls; i++ {
+				tc.fn()
+			}
+			rows, err := view.RetrieveData(openCensusView(t, metrics, tc.name).Name)
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			value := reflect.Indirect(reflect.ValueOf(rows[0].Data)).FieldByName("Value").Interface()
+			assert.EqualValues(t, tc.expected, value)
+		})
+	}
+}
+
+func TestRecordSpanLatencyOpenCensus(t *testing.T) {
+	metrics := newTestOpenCensusMetrics(t)
+	metrics.recordSpanLatency(2 * time.Millisecond)
+	metrics.recordSpanLatency(20 * time.Millisecond)
+	metrics.recordSpanLatency(1500 * time.Microsecond)
+	rows, err := view.RetrieveData(openCensusView(t, metrics, "reported_span_latency").Name)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	data, ok := rows[0].Data.(*view.DistributionData)
+	require.True(t, ok, "unexpected data type %T", rows[0].Data)
+	assert.EqualValues(t, 3, data.Count)
+	assert.InDelta(t, 23.5, data.Sum(), 0.001)
+	// OpenCensus drops the 0 bound, the buckets start with values below 5, below 10 and below 25
+	assert.Equal(t, []int64{2, 0, 1}, data.CountPerBucket[:3])
 }
 
-// TestRegisterViewsExpectingFailure validates that if an error is returned from view.Register, we panic and don't continue with initialization
+func TestRecordActiveBrokerOpenCensus(t *testing.T) {
+	metrics := newTestOpenCensusMetrics(t)
+	activeBrokers := func() map[string]float64 {
+		rows, err := view.RetrieveData(openCensusView(t, metrics, "active_broker").Name)
+		require.NoError(t, err)
+		values := map[string]float64{}
+		for _, row := range rows {
+			require.Len(t, row.Tags, 1)
+			assert.Equal(t, brokerTagKey, row.Tags[0].Key)
+			data, ok := row.Data.(*view.LastValueData)
+			require.True(t, ok, "unexpected data type %T", row.Data)
+			values[row.Tags[0].Value] = data.Value
+		}
+		return values
+	}
+	assert.Empty(t, activeBrokers())
+	metrics.recordActiveBroker("primary:5671")
+	assert.Equal(t, map[string]float64{"primary:5671": 1}, activeBrokers())
+	metrics.recordActiveBroker("backup:5671")
+	assert.Equal(t, map[string]float64{"primary:5671": 0, "backup:5671": 1}, activeBrokers())
+}
+
+// TestRegisterViewsExpectingFailure validates that if an error is returned from view.Register, the metrics are not created
 func TestRegisterViewsExpectingFailure(t *testing.T) {
 	statName := "solacereceiver/" + t.Name() + "/failed_reconnections"
 	stat := stats.Int64(statName, "", stats.UnitDimensionless)
-	err := view.Register(&view.View{
+	v := &view.View{
 		Name:        buildReceiverCustomMetricName(statName),
 		Description: "some description",
 		Measure:     stat,
 		Aggregation: view.Sum(),
+	}
+	require.NoError(t, view.Register(v))
+	t.Cleanup(func() {
+		view.Unregister(v)
 	})
-	require.NoError(t, err)
-	metrics, err := newOpenCensusMetrics(t.Name())
+	metrics, err := newReceiverMetrics(t.Name(), metric.NewNoopMeterProvider(), false)
 	assert.Error(t, err)
 	assert.Nil(t, metrics)
 }
 
-// newTestMetrics builds a new metrics that will cleanup when testing.T completes
-func newTestMetrics(t *testing.T) *opencensusMetrics {
-	m, err := newOpenCensusMetrics(t.Name())
+// openCensusView returns the view of metrics with the given name
+func openCensusView(t *testing.T, metrics *receiverMetrics, name string) *view.View {
+	for _, v := range metrics.views {
+		if strings.HasSuffix(v.Name, nameSep+name) {
+			return v
+		}
+	}
+	t.Fatalf("no view for metric %s", name)
+	return nil
+}
+
+// activeBrokers returns the values of the active broker metric by broker
+func activeBrokers(t *testing.T, metrics *receiverMetrics) map[string]int64 {
+	rm, err := testReaders.get(metrics).reader.Collect(context.Background())
+	require.NoError(t, err)
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if !strings.HasSuffix(m.Name, nameSep+"active_broker") {
+				continue
+			}
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			require.True(t, ok, "unexpected data type %T", m.Data)
+			for _, dp := range gauge.DataPoints {
+				broker, found := dp.Attributes.Value(brokerAttribute)
+				require.True(t, found)
+				values[broker.AsString()] = dp.Value
+			}
+		}
+	}
+	return values
+}
+
+// validateMetric validates the value of the metric with the given name. Counters are validated
+// against the value seen by the previous validation, gauges are validated against their last value.
+// A nil expected value validates that the metric was not recorded.
+func validateMetric(t *testing.T, metrics *receiverMetrics, name string, expected interface{}) {
+	value, isCounter, found := testReaders.get(metrics).collect(t, name)
+	if expected == nil {
+		assert.False(t, found && (!isCounter || value != 0), "expected metric %s not to be recorded, got %d", name, value)
+		return
+	}
+	require.True(t, found, "expected metric %s to be recorded", name)
+	assert.EqualValues(t, expected, value)
+}
+
+// metricValue returns the last value of the gauge with the given name and whether it was recorded
+func metricValue(t *testing.T, metrics *receiverMetrics, name string) (int64, bool) {
+	value, _, found := testReaders.get(metrics).peek(t, name)
+	return value, found
+}
+
+// testMetricReader collects the metrics of a receiverMetrics and tracks the counter values seen by previous validations
+type testMetricReader struct {
+	reader   sdkmetric.Reader
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+// peek returns the current value of the metric with the given name
+func (r *testMetricReader) peek(t *testing.T, name string) (value int64, isCounter bool, found bool) {
+	rm, err := r.reader.Collect(context.Background())
 	require.NoError(t, err)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if !strings.HasSuffix(m.Name, nameSep+name) {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				// instruments that were never recorded are collected without data points
+				if len(data.DataPoints) == 0 {
+					return 0, true, false
+				}
+				require.Len(t, data.DataPoints, 1)
+				return data.DataPoints[0].Value, true, true
+			case metricdata.Gauge[int64]:
+				if len(data.DataPoints) == 0 {
+					return 0, false, false
+				}
+				require.Len(t, data.DataPoints, 1)
+				return data.DataPoints[0].Value, false, true
+			default:
+				t.Fatalf("unexpected data type %T for metric %s", m.Data, name)
+			}
+		}
+	}
+	return 0, false, false
+}
+
+// collect returns the value of the metric with the given name, counters are returned relative to the previous collection
+func (r *testMetricReader) collect(t *testing.T, name string) (value int64, isCounter bool, found bool) {
+	value, isCounter, found = r.peek(t, name)
+	if !isCounter {
+		return value, isCounter, found
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.counters[name]
+	r.counters[name] = value
+	return value - previous, isCounter, found
+}
+
+type testMetricReaders struct {
+	mu      sync.Mutex
+	readers map[*receiverMetrics]*testMetricReader
+}
+
+var testReaders = &testMetricReaders{readers: map[*receiverMetrics]*testMetricReader{}}
+
+func (r *testMetricReaders) get(metrics *receiverMetrics) *testMetricReader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.readers[metrics]
+}
+
+// newTestMetrics builds a new metrics collected by a manual reader that will cleanup when testing.T completes
+func newTestMetrics(t *testing.T) *receiverMetrics {
+	reader := sdkmetric.NewManualReader()
+	// subtest names contain the metric name separator
+	m, err := newReceiverMetrics(strings.ReplaceAll(t.Name(), nameSep, "_"), sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), true)
+	require.NoError(t, err)
+	testReaders.mu.Lock()
+	testReaders.readers[m] = &testMetricReader{reader: reader, counters: map[string]int64{}}
+	testReaders.mu.Unlock()
 	t.Cleanup(func() {
-		unregisterMetrics(m)
+		testReaders.mu.Lock()
+		delete(testReaders.readers, m)
+		testReaders.mu.Unlock()
 	})
 	return m
 }
 
-// unregisterMetrics is used to unregister the metrics for testing purposes
-func unregisterMetrics(metrics *opencensusMetrics) {
-	view.Unregister(
-		metrics.views.failedReconnections,
-		metrics.views.recoverableUnmarshallingErrors,
-		metrics.views.fatalUnmarshallingErrors,
-		metrics.views.droppedSpanMessages,
-		metrics.views.receivedSpanMessages,
-		metrics.views.reportedSpans,
-		metrics.views.receiverStatus,
-		metrics.views.needUpgrade,
-	)
+// newTestOpenCensusMetrics builds a new metrics recorded with OpenCensus that will unregister its views when testing.T completes
+func newTestOpenCensusMetrics(t *testing.T) *receiverMetrics {
+	m, err := newReceiverMetrics(t.Name(), metric.NewNoopMeterProvider(), false)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		view.Unregister(m.views...)
+	})
+	return m
 }
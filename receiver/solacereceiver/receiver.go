@@ -17,6 +17,7 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -24,10 +25,14 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
+var errConsumerTimeout = errors.New("next consumer did not process the traces within the consumer timeout")
+
 // solaceTracesReceiver uses azure AMQP to consume and handle telemetry data from SOlace. Implements component.TracesReceiver
 type solaceTracesReceiver struct {
 	instanceID config.ComponentID
@@ -36,7 +41,7 @@ type solaceTracesReceiver struct {
 
 	nextConsumer consumer.Traces
 	settings     component.ReceiverCreateSettings
-	metrics      *opencensusMetrics
+	metrics      *receiverMetrics
 	unmarshaller tracesUnmarshaller
 	// cancel is the function that will cancel the context associated with the main worker loop
 	cancel            context.CancelFunc
@@ -47,6 +52,8 @@ type solaceTracesReceiver struct {
 	terminating *atomic.Bool
 	// retryTimeout is the timeout between connection attempts
 	retryTimeout time.Duration
+	// activeBroker is the index of the configured broker to connect to, advanced to the next broker when a dial fails
+	activeBroker int
 }
 
 // newTracesReceiver creates a new solaceTraceReceiver as a component.TracesReceiver
@@ -67,13 +74,14 @@ func newTracesReceiver(config *Config, receiverCreateSettings component.Receiver
 		return nil, err
 	}
 
-	metrics, err := newOpenCensusMetrics(config.ID().Name())
+	metrics, err := newReceiverMetrics(config.ID().Name(), receiverCreateSettings.MeterProvider,
+		featuregate.GetRegistry().IsEnabled(useOtelForInternalMetricsGateID))
 	if err != nil {
 		receiverCreateSettings.Logger.Warn("Error registering metrics", zap.Any("error", err))
 		return nil, err
 	}
 
-	unmarshaller := newTracesUnmarshaller(receiverCreateSettings.Logger, metrics)
+	unmarshaller := newTracesUnmarshaller(receiverCreateSettings.Logger, metrics, config.SpanNameFromProperty)
 
 	return &solaceTracesReceiver{
 		instanceID:        config.ID(),
@@ -148,16 +156,19 @@ reconnectionLoop:
 					s.recordConnectionState(receiverStateConnecting)
 				}
 			}()
-			service := s.factory()
+			broker := s.broker()
+			service := s.factory(broker)
 			defer service.close(ctx)
 
 			if err := service.dial(); err != nil {
-				s.settings.Logger.Debug("Encountered error while connecting messaging service", zap.Error(err))
+				s.settings.Logger.Debug("Encountered error while connecting messaging service", zap.String("broker", broker), zap.Error(err))
 				s.metrics.recordFailedReconnection()
+				s.failover()
 				return
 			}
 			// dial was successful, record the connected state
 			s.recordConnectionState(receiverStateConnected)
+			s.metrics.recordActiveBroker(broker)
 
 			if err := s.receiveMessages(ctx, service); err != nil {
 				s.settings.Logger.Debug("Encountered error while receiving messages", zap.Error(err))
@@ -173,6 +184,24 @@ reconnectionLoop:
 	}
 }
 
+// broker returns the configured broker to connect to
+func (s *solaceTracesReceiver) broker() string {
+	if len(s.config.Broker) == 0 {
+		return ""
+	}
+	return s.config.Broker[s.activeBroker]
+}
+
+// failover advances to the next configured broker, in order, after the failure to connect to the current one.
+// The next broker is attempted after the retry timeout, the first broker follows the last one.
+func (s *solaceTracesReceiver) failover() {
+	if len(s.config.Broker) < 2 {
+		return
+	}
+	s.activeBroker = (s.activeBroker + 1) % len(s.config.Broker)
+	s.settings.Logger.Info("Failing over to the next broker", zap.String("broker", s.broker()))
+}
+
 // recordConnectionState will record the given connection state unless in the terminating state.
 // This does not fully prevent the state transitions terminating->(state)->terminated but
 // is a best effort without mutex protection and additional state tracking, and in reality if
@@ -185,6 +214,15 @@ func (s *solaceTracesReceiver) recordConnectionState(state receiverState) {
 
 // receiveMessages will continuously receive, unmarshal and propagate messages
 func (s *solaceTracesReceiver) receiveMessages(ctx context.Context, service messagingService) error {
+	var idle *idleMonitor
+	if s.config.IdleTimeout > 0 {
+		idle = newIdleMonitor(s.config.IdleTimeout, s.recordConnectionState)
+		defer idle.stop()
+	}
+	var batch *spanBatch
+	if s.config.MaxBatchSpans > 0 {
+		batch = &spanBatch{}
+	}
 	for {
 		select { // ctx.Done will be closed when we should terminate
 		case <-ctx.Done():
@@ -192,9 +230,18 @@ func (s *solaceTracesReceiver) receiveMessages(ctx context.Context, service mess
 		default:
 		}
 		// any error encountered will be returned to caller
-		if err := s.receiveMessage(ctx, service); err != nil {
+		var err error
+		if batch != nil {
+			err = s.receiveBatchedMessage(ctx, service, batch)
+		} else {
+			err = s.receiveMessage(ctx, service)
+		}
+		if err != nil {
 			return err
 		}
+		if idle != nil {
+			idle.messageReceived()
+		}
 	}
 
 }
@@ -207,42 +254,257 @@ func (s *solaceTracesReceiver) receiveMessage(ctx context.Context, service messa
 		s.settings.Logger.Warn("Failed to receive message from messaging service", zap.Error(err))
 		return err // propagate any receive message error up to caller
 	}
+	received := time.Now()
 	// only set the disposition action after we have received a message successfully
 	disposition := service.accept
 	defer func() { // on return of receiveMessage, we want to either ack or nack the message
-		if actionErr := disposition(ctx, msg); err == nil && actionErr != nil {
+		if actionErr := s.settle(ctx, disposition, msg); actionErr != nil && err == nil {
 			err = actionErr
 		}
 	}()
 	// message received successfully
 	s.metrics.recordReceivedSpanMessages()
+	traces, forward, unmarshalErr := s.unmarshalMessage(msg)
+	if unmarshalErr != nil {
+		disposition = service.failed // if we don't know the version, reject the trace message since we will disable the receiver
+		return unmarshalErr
+	}
+	if forward && s.forwardTraces(ctx, traces, []time.Time{received}) {
+		disposition = service.failed
+	}
+	return nil
+}
+
+// spanBatch holds the traces of received messages until they are forwarded to the next consumer in a single call.
+// The messages are settled once the batch is forwarded, all accepted or all rejected.
+type spanBatch struct {
+	traces   ptrace.Traces
+	spans    int
+	messages []*inboundMessage
+	// received holds the time at which each message of the batch was received
+	received []time.Time
+	// deadline is the time at which the batch is forwarded regardless of its number of spans
+	deadline time.Time
+}
+
+// receiveBatchedMessage receives a message and adds its traces to the batch, forwarding the batch once it holds
+// MaxBatchSpans spans or MaxBatchTimeout elapsed since its first message was received. Like receiveMessage, it
+// returns an error if a fatal error occurs. The messages of the pending batch are then left unsettled, so that they
+// are redelivered when the connection is closed.
+func (s *solaceTracesReceiver) receiveBatchedMessage(ctx context.Context, service messagingService, batch *spanBatch) error {
+	receiveCtx := ctx
+	if len(batch.messages) > 0 {
+		var cancel context.CancelFunc
+		receiveCtx, cancel = context.WithDeadline(ctx, batch.deadline)
+		defer cancel()
+	}
+	msg, err := service.receiveMessage(receiveCtx)
+	if err != nil {
+		if receiveCtx.Err() != nil && ctx.Err() == nil { // no message was received before the batch timeout
+			return s.forwardBatch(ctx, service, batch)
+		}
+		s.settings.Logger.Warn("Failed to receive message from messaging service", zap.Error(err))
+		return err // propagate any receive message error up to caller
+	}
+	received := time.Now()
+	s.metrics.recordReceivedSpanMessages()
+	traces, forward, unmarshalErr := s.unmarshalMessage(msg)
+	if unmarshalErr != nil {
+		// reject the trace message since we will disable the receiver, the settlement error is less relevant
+		_ = s.settle(ctx, service.failed, msg)
+		return unmarshalErr
+	}
+	if !forward {
+		return s.settle(ctx, service.accept, msg)
+	}
+
+	if len(batch.messages) == 0 {
+		batch.traces = ptrace.NewTraces()
+		batch.deadline = time.Now().Add(s.config.MaxBatchTimeout)
+	}
+	batch.spans += traces.SpanCount()
+	traces.ResourceSpans().MoveAndAppendTo(batch.traces.ResourceSpans())
+	batch.messages = append(batch.messages, msg)
+	batch.received = append(batch.received, received)
+	if batch.spans >= s.config.MaxBatchSpans || !time.Now().Before(batch.deadline) {
+		return s.forwardBatch(ctx, service, batch)
+	}
+	return nil
+}
+
+// forwardBatch forwards the traces of the batch, settles its messages and empties the batch.
+// It returns the first settlement error.
+func (s *solaceTracesReceiver) forwardBatch(ctx context.Context, service messagingService, batch *spanBatch) (err error) {
+	disposition := service.accept
+	if s.forwardTraces(ctx, batch.traces, batch.received) {
+		disposition = service.failed
+	}
+	for _, msg := range batch.messages {
+		if actionErr := s.settle(ctx, disposition, msg); actionErr != nil && err == nil {
+			err = actionErr
+		}
+	}
+	*batch = spanBatch{}
+	return err
+}
+
+// settle applies the disposition to the message, recording settlement failures.
+func (s *solaceTracesReceiver) settle(ctx context.Context, disposition func(context.Context, *inboundMessage) error, msg *inboundMessage) error {
+	err := disposition(ctx, msg)
+	if err != nil {
+		// settlement failures are tracked separately as they can lead to redelivered, duplicate messages
+		s.metrics.recordSettlementError()
+	}
+	return err
+}
+
+// unmarshalMessage filters and unmarshals a received message. It returns false if the message has no traces to forward,
+// in which case it must be accepted, and an error if the message version is unknown, in which case it must be rejected.
+func (s *solaceTracesReceiver) unmarshalMessage(msg *inboundMessage) (ptrace.Traces, bool, error) {
+	// drop filtered messages prior to unmarshalling, they are accepted so they are not redelivered
+	if s.isFiltered(msg) {
+		s.metrics.recordFilteredMessages()
+		return ptrace.Traces{}, false, nil
+	}
 	// unmarshal the message. unmarshalling errors are not fatal unless the version is unknown
 	traces, unmarshalErr := s.unmarshaller.unmarshal(msg)
 	if unmarshalErr != nil {
 		s.settings.Logger.Error("Encountered error while unmarshalling message", zap.Error(unmarshalErr))
 		s.metrics.recordFatalUnmarshallingError()
 		if errors.Is(unmarshalErr, errUnknownTraceMessgeVersion) {
-			disposition = service.failed // if we don't know the version, reject the trace message since we will disable the receiver
-			return unmarshalErr
+			return ptrace.Traces{}, false, unmarshalErr
 		}
 		s.metrics.recordDroppedSpanMessages() // if the error is some other unmarshalling error, we will ack the message and drop the content
-		return nil                            // don't propagate error, but don't continue forwarding traces
-	}
-	// forward to next consumer. Forwarding errors are not fatal so are not propagated to the caller.
-	// Temporary consumer errors will lead to redelivered messages, permanent will be accepted
-	forwardErr := s.nextConsumer.ConsumeTraces(ctx, traces)
-	if forwardErr != nil {
-		if !consumererror.IsPermanent(forwardErr) { // reject the message if the error is not permanent so we can retry, don't increment dropped span messages
-			s.settings.Logger.Warn("Encountered temporary error while forwarding traces to next receiver, will allow redelivery", zap.Error(forwardErr))
-			disposition = service.failed
-		} else { // error is permanent, we want to accept the message and increment the number of dropped messages
-			s.settings.Logger.Warn("Encountered permanent error while forwarding traces to next receiver, will swallow trace", zap.Error(forwardErr))
-			s.metrics.recordDroppedSpanMessages()
+		return ptrace.Traces{}, false, nil    // don't propagate error, but don't continue forwarding traces
+	}
+	return traces, true, nil
+}
+
+// forwardTraces forwards the traces unmarshalled from the messages received at the given times to the next consumer, recording
+// the latency of each message. Forwarding errors
+// are not fatal, it returns whether the messages must be rejected so that they are redelivered: temporary consumer errors
+// lead to redelivered messages, permanent ones to accepted messages.
+func (s *solaceTracesReceiver) forwardTraces(ctx context.Context, traces ptrace.Traces, received []time.Time) bool {
+	forwarded := time.Now()
+	for _, t := range received {
+		s.metrics.recordSpanLatency(forwarded.Sub(t))
+	}
+	messages := len(received)
+	forwardErr := s.consumeTraces(ctx, traces)
+	if forwardErr == nil {
+		for i := 0; i < messages; i++ {
+			s.metrics.recordReportedSpans()
 		}
-	} else {
-		s.metrics.recordReportedSpans()
+		return false
 	}
-	return nil
+	// counted apart from the unmarshalling errors to tell failures of the pipeline from failures of the receiver
+	s.metrics.recordDownstreamExportError()
+	if errors.Is(forwardErr, errConsumerTimeout) { // reject the message so that it is redelivered once the next consumer recovers
+		s.settings.Logger.Warn("Next consumer timed out while forwarding traces, will allow redelivery", zap.Duration("consumer_timeout", s.config.ConsumerTimeout))
+		s.metrics.recordConsumerTimeout()
+		return true
+	}
+	if !consumererror.IsPermanent(forwardErr) { // reject the message if the error is not permanent so we can retry, don't increment dropped span messages
+		s.settings.Logger.Warn("Encountered temporary error while forwarding traces to next receiver, will allow redelivery", zap.Error(forwardErr))
+		return true
+	}
+	// error is permanent, we want to accept the message and increment the number of dropped messages
+	s.settings.Logger.Warn("Encountered permanent error while forwarding traces to next receiver, will swallow trace", zap.Error(forwardErr))
+	for i := 0; i < messages; i++ {
+		s.metrics.recordDroppedSpanMessages()
+	}
+	s.metrics.recordDroppedSpans(int64(traces.SpanCount()))
+	return false
+}
+
+// consumeTraces forwards traces to the next consumer, returning errConsumerTimeout if the consumer timeout elapses first.
+// A hanging consumer may not honour the cancellation of its context, so it is not waited for once the timeout elapsed.
+func (s *solaceTracesReceiver) consumeTraces(ctx context.Context, traces ptrace.Traces) error {
+	if s.config.ConsumerTimeout <= 0 {
+		return s.nextConsumer.ConsumeTraces(ctx, traces)
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.config.ConsumerTimeout)
+	defer cancel()
+	result := make(chan error, 1) // buffered so that a consumer returning after the timeout does not block
+	go func() {
+		result <- s.nextConsumer.ConsumeTraces(ctx, traces)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errConsumerTimeout
+		}
+		return ctx.Err()
+	}
+}
+
+// isFiltered returns true if the message matches any of the configured message filter rules
+func (s *solaceTracesReceiver) isFiltered(msg *inboundMessage) bool {
+	for _, rule := range s.config.MessageFilters {
+		if value, ok := msg.ApplicationProperties[rule.Property]; ok && fmt.Sprint(value) == rule.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// idleMonitor records the connected idle state once no message was received for the timeout,
+// and the connected state again when the next message is received.
+type idleMonitor struct {
+	timeout time.Duration
+	record  func(receiverState)
+
+	mu           sync.Mutex
+	timer        *time.Timer
+	lastReceived time.Time
+	idle         bool
+	stopped      bool
+}
+
+func newIdleMonitor(timeout time.Duration, record func(receiverState)) *idleMonitor {
+	m := &idleMonitor{
+		timeout:      timeout,
+		record:       record,
+		lastReceived: time.Now(),
+	}
+	m.timer = time.AfterFunc(timeout, m.check)
+	return m
+}
+
+// check records the idle state if the timeout elapsed since the last message, otherwise it rearms the timer.
+// Rearming from the timer rather than on every message keeps messageReceived cheap.
+func (m *idleMonitor) check() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return
+	}
+	if remaining := m.timeout - time.Since(m.lastReceived); remaining > 0 {
+		m.timer.Reset(remaining)
+		return
+	}
+	m.idle = true
+	m.record(receiverStateConnectedIdle)
+}
+
+func (m *idleMonitor) messageReceived() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastReceived = time.Now()
+	if m.idle {
+		m.idle = false
+		m.record(receiverStateConnected)
+		m.timer.Reset(m.timeout)
+	}
+}
+
+func (m *idleMonitor) stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopped = true
+	m.timer.Stop()
 }
 
 func sleep(ctx context.Context, d time.Duration) {
@@ -22,8 +22,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -165,10 +167,331 @@ func TestReceiveMessage(t *testing.T) {
 			if testCase.validation != nil {
 				testCase.validation(t, receiver)
 			}
+			var expectedSettlementErrors interface{}
+			if testCase.ackErr != nil || testCase.nackErr != nil {
+				expectedSettlementErrors = 1
+			}
+			validateMetric(t, receiver.metrics, "settlement_errors", expectedSettlementErrors)
 		})
 	}
 }
 
+func TestReceiveMessageDroppedSpans(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.nextConsumer = consumertest.NewErr(consumererror.NewPermanent(errors.New("a permanent error")))
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		traces := ptrace.NewTraces()
+		spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for i := 0; i < 3; i++ {
+			spans.AppendEmpty()
+		}
+		return traces, nil
+	}
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, receiver.receiveMessage(context.Background(), messagingService))
+	}
+	validateMetric(t, receiver.metrics, "dropped_span_messages", 2)
+	validateMetric(t, receiver.metrics, "dropped_spans", 6)
+	validateMetric(t, receiver.metrics, "reported_spans", nil)
+	validateMetric(t, receiver.metrics, "downstream_export_errors", 2)
+}
+
+func TestReceiveMessageDownstreamExportError(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.nextConsumer = consumertest.NewErr(errors.New("a temporary error"))
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	nacks := 0
+	messagingService.nackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		nacks++
+		return nil
+	}
+	assert.NoError(t, receiver.receiveMessage(context.Background(), messagingService))
+	assert.Equal(t, 1, nacks)
+	validateMetric(t, receiver.metrics, "downstream_export_errors", 1)
+	// consumer errors are not unmarshalling errors
+	validateMetric(t, receiver.metrics, "recoverable_unmarshalling_errors", nil)
+	validateMetric(t, receiver.metrics, "fatal_unmarshalling_errors", nil)
+}
+
+func TestReceiveMessageFiltered(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MessageFilters = []MessageFilterRule{
+		{Property: "application-message-type", Value: "heartbeat"},
+	}
+	msg := &inboundMessage{
+		ApplicationProperties: map[string]interface{}{
+			"application-message-type": "heartbeat",
+		},
+	}
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return msg, nil
+	}
+	ackCalled := false
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		ackCalled = true
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		t.Error("did not expect unmarshal to be called for a filtered message")
+		return ptrace.Traces{}, nil
+	}
+	err := receiver.receiveMessage(context.Background(), messagingService)
+	assert.NoError(t, err)
+	assert.True(t, ackCalled)
+	validateMetric(t, receiver.metrics, "filtered_messages", 1)
+	validateReceiverMetrics(t, receiver, 1, nil, nil, nil)
+}
+
+func TestReceiveMessageNotFiltered(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MessageFilters = []MessageFilterRule{
+		{Property: "application-message-type", Value: "heartbeat"},
+	}
+	msg := &inboundMessage{
+		ApplicationProperties: map[string]interface{}{
+			"application-message-type": "span",
+		},
+	}
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return msg, nil
+	}
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		return nil
+	}
+	unmarshalCalled := false
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		unmarshalCalled = true
+		return ptrace.NewTraces(), nil
+	}
+	err := receiver.receiveMessage(context.Background(), messagingService)
+	assert.NoError(t, err)
+	assert.True(t, unmarshalCalled)
+	validateMetric(t, receiver.metrics, "filtered_messages", nil)
+	validateReceiverMetrics(t, receiver, 1, nil, nil, 1)
+}
+
+func TestReceiveMessageSettlementError(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	settleErr := errors.New("connection reset while settling")
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		return settleErr
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	err := receiver.receiveMessage(context.Background(), messagingService)
+	assert.Equal(t, settleErr, err)
+	validateMetric(t, receiver.metrics, "settlement_errors", 1)
+	validateMetric(t, receiver.metrics, "failed_reconnections", nil)
+	validateReceiverMetrics(t, receiver, 1, nil, nil, 1)
+}
+
+func TestReceiveMessageConsumerTimeout(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.ConsumerTimeout = 10 * time.Millisecond
+	// the slow consumer ignores the cancellation of its context, as a hanging consumer would
+	release := make(chan struct{})
+	defer close(release)
+	slowConsumer, err := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		<-release
+		return nil
+	})
+	require.NoError(t, err)
+	receiver.nextConsumer = slowConsumer
+
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	var ackCalled, nackCalled bool
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		ackCalled = true
+		return nil
+	}
+	messagingService.nackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		nackCalled = true
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	err = receiver.receiveMessage(context.Background(), messagingService)
+	assert.NoError(t, err)
+	assert.False(t, ackCalled)
+	assert.True(t, nackCalled)
+	validateMetric(t, receiver.metrics, "consumer_timeouts", 1)
+	validateMetric(t, receiver.metrics, "downstream_export_errors", 1)
+	validateReceiverMetrics(t, receiver, 1, nil, nil, nil)
+}
+
+func TestReceiveMessageWithinConsumerTimeout(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.ConsumerTimeout = time.Minute
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	var ackCalled bool
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		ackCalled = true
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	err := receiver.receiveMessage(context.Background(), messagingService)
+	assert.NoError(t, err)
+	assert.True(t, ackCalled)
+	validateMetric(t, receiver.metrics, "consumer_timeouts", nil)
+	validateMetric(t, receiver.metrics, "downstream_export_errors", nil)
+	validateReceiverMetrics(t, receiver, 1, nil, nil, 1)
+}
+
+// batchTraces returns traces holding the given number of spans
+func batchTraces(spans int) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	scopeSpans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		scopeSpans.Spans().AppendEmpty()
+	}
+	return traces
+}
+
+func TestReceiveBatchedMessageFlushOnSpanCount(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MaxBatchSpans = 5
+	receiver.config.MaxBatchTimeout = time.Minute
+	sink := &consumertest.TracesSink{}
+	receiver.nextConsumer = sink
+
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	acks := 0
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		acks++
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return batchTraces(2), nil
+	}
+
+	batch := &spanBatch{}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+		// the messages are not acknowledged before the batch is forwarded
+		assert.Equal(t, 0, acks)
+		assert.Empty(t, sink.AllTraces())
+	}
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	assert.Equal(t, 3, acks)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 6, sink.AllTraces()[0].SpanCount())
+	assert.Empty(t, batch.messages)
+	validateReceiverMetrics(t, receiver, 3, nil, nil, 3)
+}
+
+func TestReceiveBatchedMessageFlushOnTimeout(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MaxBatchSpans = 100
+	receiver.config.MaxBatchTimeout = 10 * time.Millisecond
+	sink := &consumertest.TracesSink{}
+	receiver.nextConsumer = sink
+
+	receiveCalls := 0
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		receiveCalls++
+		if receiveCalls == 1 {
+			return &inboundMessage{}, nil
+		}
+		// no other message is received before the batch timeout
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	acks := 0
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		acks++
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return batchTraces(2), nil
+	}
+
+	batch := &spanBatch{}
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	assert.Equal(t, 0, acks)
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	assert.Equal(t, 1, acks)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 2, sink.AllTraces()[0].SpanCount())
+	validateReceiverMetrics(t, receiver, 1, nil, nil, 1)
+}
+
+func TestReceiveBatchedMessageTemporaryError(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MaxBatchSpans = 4
+	receiver.config.MaxBatchTimeout = time.Minute
+	receiver.nextConsumer = consumertest.NewErr(errors.New("temporary error"))
+
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return &inboundMessage{}, nil
+	}
+	nacks := 0
+	messagingService.nackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		nacks++
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return batchTraces(2), nil
+	}
+
+	batch := &spanBatch{}
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	// all the messages of the batch are rejected
+	assert.Equal(t, 2, nacks)
+	validateReceiverMetrics(t, receiver, 2, nil, nil, nil)
+	// the batch is forwarded once
+	validateMetric(t, receiver.metrics, "downstream_export_errors", 1)
+}
+
+func TestReceiveBatchedMessageReceiveError(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.MaxBatchSpans = 4
+	receiver.config.MaxBatchTimeout = time.Minute
+	someError := errors.New("some error")
+
+	receiveCalls := 0
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		receiveCalls++
+		if receiveCalls == 1 {
+			return &inboundMessage{}, nil
+		}
+		return nil, someError
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return batchTraces(2), nil
+	}
+
+	// the pending message is neither forwarded nor settled, so that it is redelivered
+	batch := &spanBatch{}
+	require.NoError(t, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	assert.Equal(t, someError, receiver.receiveBatchedMessage(context.Background(), messagingService, batch))
+	validateReceiverMetrics(t, receiver, 1, nil, nil, nil)
+}
+
 // receiveMessages ctx done return
 func TestReceiveMessagesTerminateWithCtxDone(t *testing.T) {
 	receiver, messagingService, unmarshaller := newReceiver(t)
@@ -202,22 +525,54 @@ func TestReceiveMessagesTerminateWithCtxDone(t *testing.T) {
 	validateReceiverMetrics(t, receiver, 1, nil, nil, 1)
 }
 
+func TestReceiveMessagesIdleTimeout(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.IdleTimeout = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receiveCalls := 0
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		receiveCalls++
+		if receiveCalls == 1 {
+			// no message for longer than the idle timeout
+			assert.Eventually(t, func() bool {
+				status, found := metricValue(t, receiver.metrics, "receiver_status")
+				return found && status == int64(receiverStateConnectedIdle)
+			}, time.Second, time.Millisecond)
+			return &inboundMessage{}, nil
+		}
+		// the previous message transitioned the receiver back to connected
+		validateMetric(t, receiver.metrics, "receiver_status", receiverStateConnected)
+		cancel()
+		return nil, errors.New("some error")
+	}
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		return nil
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	err := receiver.receiveMessages(ctx, messagingService)
+	assert.Error(t, err)
+	assert.Equal(t, 2, receiveCalls)
+}
+
 func TestReceiverLifecycle(t *testing.T) {
 	receiver, messagingService, _ := newReceiver(t)
 	dialCalled := make(chan struct{})
 	messagingService.dialFunc = func() error {
-		validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateConnecting)
+		validateMetric(t, receiver.metrics, "receiver_status", receiverStateConnecting)
 		close(dialCalled)
 		return nil
 	}
 	closeCalled := make(chan struct{})
 	messagingService.closeFunc = func(ctx context.Context) {
-		validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateTerminating)
+		validateMetric(t, receiver.metrics, "receiver_status", receiverStateTerminating)
 		close(closeCalled)
 	}
 	receiveMessagesCalled := make(chan struct{})
 	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
-		validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateConnected)
+		validateMetric(t, receiver.metrics, "receiver_status", receiverStateConnected)
 		close(receiveMessagesCalled)
 		<-ctx.Done()
 		return nil, errors.New("some error")
@@ -230,7 +585,7 @@ func TestReceiverLifecycle(t *testing.T) {
 	err = receiver.Shutdown(context.Background())
 	assert.NoError(t, err)
 	assertChannelClosed(t, closeCalled)
-	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateTerminated)
+	validateMetric(t, receiver.metrics, "receiver_status", receiverStateTerminated)
 	// we error on receive message, so we should not report any metrics
 	validateReceiverMetrics(t, receiver, nil, nil, nil, nil)
 }
@@ -245,7 +600,7 @@ func TestReceiverDialFailureContinue(t *testing.T) {
 	dialDone := make(chan struct{})
 	factoryDone := make(chan struct{})
 	closeDone := make(chan struct{})
-	receiver.factory = func() messagingService {
+	receiver.factory = func(string) messagingService {
 		factoryCalled++
 		if factoryCalled == expectedAttempts {
 			close(factoryDone)
@@ -262,7 +617,7 @@ func TestReceiverDialFailureContinue(t *testing.T) {
 	msgService.closeFunc = func(ctx context.Context) {
 		closeCalled++
 		// asset we never left connecting state prior to closing closeDone
-		validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateConnecting)
+		validateMetric(t, receiver.metrics, "receiver_status", receiverStateConnecting)
 		if closeCalled == expectedAttempts {
 			close(closeDone)
 			<-ctx.Done() // wait for ctx.Done
@@ -279,15 +634,68 @@ func TestReceiverDialFailureContinue(t *testing.T) {
 	// expect close to be called twice
 	assertChannelClosed(t, closeDone)
 	// assert failed reconnections
-	validateMetric(t, receiver.metrics.views.failedReconnections, expectedAttempts)
+	validateMetric(t, receiver.metrics, "failed_reconnections", expectedAttempts)
 
 	err = receiver.Shutdown(context.Background())
 	assert.NoError(t, err)
-	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateTerminated)
+	validateMetric(t, receiver.metrics, "receiver_status", receiverStateTerminated)
 	// we error on dial, should never get to receive messages
 	validateReceiverMetrics(t, receiver, nil, nil, nil, nil)
 }
 
+func TestReceiverBrokerFailover(t *testing.T) {
+	receiver, msgService, _ := newReceiver(t)
+	receiver.config.Broker = []string{"primary:5671", "backup:5671"}
+	dialErr := errors.New("Some dial error")
+
+	var brokers []string
+	receiver.factory = func(broker string) messagingService {
+		brokers = append(brokers, broker)
+		return msgService
+	}
+	// the primary broker is down, the backup broker is up
+	msgService.dialFunc = func() error {
+		if brokers[len(brokers)-1] == "primary:5671" {
+			return dialErr
+		}
+		return nil
+	}
+	receiveDone := make(chan struct{})
+	msgService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		close(receiveDone)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	closeDone := make(chan struct{})
+	msgService.closeFunc = func(ctx context.Context) {
+		if len(brokers) == 2 {
+			close(closeDone)
+		}
+	}
+
+	err := receiver.Start(context.Background(), nil)
+	assert.NoError(t, err)
+	assertChannelClosed(t, receiveDone)
+	assert.Equal(t, []string{"primary:5671", "backup:5671"}, brokers)
+	validateMetric(t, receiver.metrics, "failed_reconnections", 1)
+	assert.Equal(t, map[string]int64{"backup:5671": 1}, activeBrokers(t, receiver.metrics))
+
+	err = receiver.Shutdown(context.Background())
+	assert.NoError(t, err)
+	assertChannelClosed(t, closeDone)
+}
+
+func TestReceiverBrokerFailoverWrapsAround(t *testing.T) {
+	receiver, _, _ := newReceiver(t)
+	receiver.config.Broker = []string{"first:5671", "second:5671", "third:5671"}
+	var brokers []string
+	for i := 0; i < 4; i++ {
+		brokers = append(brokers, receiver.broker())
+		receiver.failover()
+	}
+	assert.Equal(t, []string{"first:5671", "second:5671", "third:5671", "first:5671"}, brokers)
+}
+
 func TestReceiverUnmarshalVersionFailureExpectingDisable(t *testing.T) {
 	receiver, msgService, unmarshaller := newReceiver(t)
 	dialDone := make(chan struct{})
@@ -333,17 +741,17 @@ func TestReceiverUnmarshalVersionFailureExpectingDisable(t *testing.T) {
 	// we receive 1 message, encounter a fatal unmarshalling error and we nack the message so it is not actually dropped
 	validateReceiverMetrics(t, receiver, 1, nil, 1, nil)
 	// assert idle state
-	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateIdle)
+	validateMetric(t, receiver.metrics, "receiver_status", receiverStateIdle)
 
 	err = receiver.Shutdown(context.Background())
 	assert.NoError(t, err)
-	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateTerminated)
+	validateMetric(t, receiver.metrics, "receiver_status", receiverStateTerminated)
 }
 
 func newReceiver(t *testing.T) (*solaceTracesReceiver, *mockMessagingService, *mockUnmarshaller) {
 	unmarshaller := &mockUnmarshaller{}
 	service := &mockMessagingService{}
-	messagingServiceFactory := func() messagingService {
+	messagingServiceFactory := func(string) messagingService {
 		return service
 	}
 	metrics := newTestMetrics(t)
@@ -363,10 +771,10 @@ func newReceiver(t *testing.T) (*solaceTracesReceiver, *mockMessagingService, *m
 }
 
 func validateReceiverMetrics(t *testing.T, receiver *solaceTracesReceiver, receivedMsgVal, droppedMsgVal, fatalUnmarshalling, reportedSpan interface{}) {
-	validateMetric(t, receiver.metrics.views.receivedSpanMessages, receivedMsgVal)
-	validateMetric(t, receiver.metrics.views.droppedSpanMessages, droppedMsgVal)
-	validateMetric(t, receiver.metrics.views.fatalUnmarshallingErrors, fatalUnmarshalling)
-	validateMetric(t, receiver.metrics.views.reportedSpans, reportedSpan)
+	validateMetric(t, receiver.metrics, "received_span_messages", receivedMsgVal)
+	validateMetric(t, receiver.metrics, "dropped_span_messages", droppedMsgVal)
+	validateMetric(t, receiver.metrics, "fatal_unmarshalling_errors", fatalUnmarshalling)
+	validateMetric(t, receiver.metrics, "reported_spans", reportedSpan)
 }
 
 type mockMessagingService struct {
@@ -37,14 +37,15 @@ type tracesUnmarshaller interface {
 }
 
 // newUnmarshalleer returns a new unmarshaller ready for message unmarshalling
-func newTracesUnmarshaller(logger *zap.Logger, metrics *opencensusMetrics) tracesUnmarshaller {
+func newTracesUnmarshaller(logger *zap.Logger, metrics *receiverMetrics, spanNameProperty string) tracesUnmarshaller {
 	return &solaceTracesUnmarshaller{
 		logger:  logger,
 		metrics: metrics,
 		// v1 unmarshaller is implemented by solaceMessageUnmarshallerV1
 		v1: &solaceMessageUnmarshallerV1{
-			logger:  logger,
-			metrics: metrics,
+			logger:           logger,
+			metrics:          metrics,
+			spanNameProperty: spanNameProperty,
 		},
 	}
 }
@@ -52,7 +53,7 @@ func newTracesUnmarshaller(logger *zap.Logger, metrics *opencensusMetrics) trace
 // solaceTracesUnmarshaller implements tracesUnmarshaller.
 type solaceTracesUnmarshaller struct {
 	logger  *zap.Logger
-	metrics *opencensusMetrics
+	metrics *receiverMetrics
 	v1      tracesUnmarshaller
 }
 
@@ -90,7 +91,9 @@ func (u *solaceTracesUnmarshaller) unmarshal(message *inboundMessage) (ptrace.Tr
 
 type solaceMessageUnmarshallerV1 struct {
 	logger  *zap.Logger
-	metrics *opencensusMetrics
+	metrics *receiverMetrics
+	// spanNameProperty is the user property used as span name, if set
+	spanNameProperty string
 }
 
 // unmarshal implements tracesUnmarshaller.unmarshal
@@ -153,7 +156,7 @@ func (u *solaceMessageUnmarshallerV1) mapClientSpanData(spanData *model_v1.SpanD
 	const clientSpanName = "(topic) receive"
 
 	// client span constants
-	clientSpan.SetName(clientSpanName)
+	clientSpan.SetName(u.spanName(spanData, clientSpanName))
 	// SPAN_KIND_CONSUMER == 5
 	clientSpan.SetKind(5)
 
@@ -186,6 +189,18 @@ func (u *solaceMessageUnmarshallerV1) mapClientSpanData(spanData *model_v1.SpanD
 	}
 }
 
+// spanName returns the value of the configured span name user property if it is a non-empty string,
+// otherwise the given default name.
+func (u *solaceMessageUnmarshallerV1) spanName(spanData *model_v1.SpanData, defaultName string) string {
+	if u.spanNameProperty == "" {
+		return defaultName
+	}
+	if value, ok := spanData.UserProperties[u.spanNameProperty]; ok && value.GetStringValue() != "" {
+		return value.GetStringValue()
+	}
+	return defaultName
+}
+
 // mapAttributes takes a set of attributes from SpanData and maps them to ClientSpan.Attributes().
 // Will also copy any user properties stored in the SpanData with a best effort approach.
 func (u *solaceMessageUnmarshallerV1) mapClientSpanAttributes(spanData *model_v1.SpanData, attrMap pcommon.Map) {
@@ -247,7 +247,7 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := newTracesUnmarshaller(zap.NewNop(), newTestMetrics(t))
+			u := newTracesUnmarshaller(zap.NewNop(), newTestMetrics(t), "")
 			traces, err := u.unmarshal(tt.message)
 			if tt.err != nil {
 				require.Error(t, err)
@@ -317,7 +317,7 @@ func TestUnmarshallerMapResourceSpan(t *testing.T) {
 			actual := pcommon.NewMap()
 			u.mapResourceSpanAttributes(tt.spanData, actual)
 			assert.Equal(t, tt.want, actual.AsRaw())
-			validateMetric(t, u.metrics.views.recoverableUnmarshallingErrors, tt.expectedUnmarshallingErrors)
+			validateMetric(t, u.metrics, "recoverable_unmarshalling_errors", tt.expectedUnmarshallingErrors)
 		})
 	}
 }
@@ -390,6 +390,64 @@ func TestUnmarshallerMapClientSpanData(t *testing.T) {
 	}
 }
 
+func TestUnmarshallerSpanNameFromProperty(t *testing.T) {
+	stringProperty := func(value string) *model_v1.SpanData_UserPropertyValue {
+		return &model_v1.SpanData_UserPropertyValue{
+			Value: &model_v1.SpanData_UserPropertyValue_StringValue{StringValue: value},
+		}
+	}
+	tests := []struct {
+		name           string
+		property       string
+		userProperties map[string]*model_v1.SpanData_UserPropertyValue
+		want           string
+	}{
+		{
+			name:     "From Property",
+			property: "operation",
+			userProperties: map[string]*model_v1.SpanData_UserPropertyValue{
+				"operation": stringProperty("order created"),
+			},
+			want: "order created",
+		},
+		{
+			name:     "Property Absent",
+			property: "operation",
+			userProperties: map[string]*model_v1.SpanData_UserPropertyValue{
+				"other": stringProperty("order created"),
+			},
+			want: "(topic) receive",
+		},
+		{
+			name:     "Property Not A String",
+			property: "operation",
+			userProperties: map[string]*model_v1.SpanData_UserPropertyValue{
+				"operation": {
+					Value: &model_v1.SpanData_UserPropertyValue_Int32Value{Int32Value: 1},
+				},
+			},
+			want: "(topic) receive",
+		},
+		{
+			name:     "Property Not Configured",
+			property: "",
+			userProperties: map[string]*model_v1.SpanData_UserPropertyValue{
+				"operation": stringProperty("order created"),
+			},
+			want: "(topic) receive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newTestV1Unmarshaller(t)
+			u.spanNameProperty = tt.property
+			actual := ptrace.NewTraces().ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			u.mapClientSpanData(&model_v1.SpanData{UserProperties: tt.userProperties}, actual)
+			assert.Equal(t, tt.want, actual.Name())
+		})
+	}
+}
+
 func TestUnmarshallerMapClientSpanAttributes(t *testing.T) {
 	var (
 		protocolVersion      = "5.0"
@@ -561,7 +619,7 @@ func TestUnmarshallerMapClientSpanAttributes(t *testing.T) {
 			actual := pcommon.NewMap()
 			u.mapClientSpanAttributes(tt.spanData, actual)
 			assert.Equal(t, tt.want, actual.AsRaw())
-			validateMetric(t, u.metrics.views.recoverableUnmarshallingErrors, tt.expectedUnmarshallingErrors)
+			validateMetric(t, u.metrics, "recoverable_unmarshalling_errors", tt.expectedUnmarshallingErrors)
 		})
 	}
 }
@@ -814,7 +872,7 @@ func TestUnmarshallerEvents(t *testing.T) {
 			u.mapEvents(tt.spanData, actual)
 			// order is nondeterministic for attributes, so we must sort to get a valid comparison
 			compareSpans(t, expected, actual)
-			validateMetric(t, u.metrics.views.recoverableUnmarshallingErrors, tt.unmarshallingErrors)
+			validateMetric(t, u.metrics, "recoverable_unmarshalling_errors", tt.unmarshallingErrors)
 		})
 	}
 }
@@ -893,7 +951,7 @@ func TestUnmarshallerRGMID(t *testing.T) {
 			u := newTestV1Unmarshaller(t)
 			actual := u.rgmidToString(tt.in)
 			assert.Equal(t, tt.expected, actual)
-			validateMetric(t, u.metrics.views.recoverableUnmarshallingErrors, tt.numErr)
+			validateMetric(t, u.metrics, "recoverable_unmarshalling_errors", tt.numErr)
 		})
 	}
 }
@@ -1056,10 +1114,10 @@ func TestSolaceMessageUnmarshallerV1InsertUserPropertyUnsupportedType(t *testing
 	u.insertUserProperty(attributeMap, key, "invalid data type")
 	_, ok := attributeMap.Get("messaging.solace.user_properties." + key)
 	assert.False(t, ok)
-	validateMetric(t, u.metrics.views.recoverableUnmarshallingErrors, 1)
+	validateMetric(t, u.metrics, "recoverable_unmarshalling_errors", 1)
 }
 
 func newTestV1Unmarshaller(t *testing.T) *solaceMessageUnmarshallerV1 {
 	m := newTestMetrics(t)
-	return &solaceMessageUnmarshallerV1{zap.NewNop(), m}
+	return &solaceMessageUnmarshallerV1{logger: zap.NewNop(), metrics: m}
 }